@@ -0,0 +1,35 @@
+// Package api is an interface-based layer over github.com/dchooyc/book.
+// Parsing, fetching, storage and export sit behind small interfaces so
+// callers can swap in their own implementations, and the free functions
+// GetBook and GetBookURLs are kept as shims over the default parser so
+// existing code only needs its import path changed.
+//
+// Its data types are aliases of package book's, so it makes no
+// compatibility promise beyond that package's own: a change to book.Book
+// is a change to Book here too.
+package api
+
+import (
+	"io"
+
+	"github.com/dchooyc/book"
+)
+
+type (
+	Book      = book.Book
+	Books     = book.Books
+	BookRef   = book.BookRef
+	AuthorRef = book.AuthorRef
+	Genre     = book.Genre
+	List      = book.List
+)
+
+var DefaultParser Parser = NewParser()
+
+func GetBook(r io.Reader) (*Book, error) {
+	return DefaultParser.ParseBook(r)
+}
+
+func GetBookURLs(r io.Reader) ([]string, error) {
+	return DefaultParser.ParseBookURLs(r)
+}
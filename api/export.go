@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/bibtex"
+	"github.com/dchooyc/book/marc"
+)
+
+type Exporter interface {
+	Export(w io.Writer, books []Book) error
+}
+
+type ExporterFunc func(w io.Writer, books []Book) error
+
+func (f ExporterFunc) Export(w io.Writer, books []Book) error {
+	return f(w, books)
+}
+
+var (
+	JSONExporter Exporter = ExporterFunc(func(w io.Writer, books []Book) error {
+		return json.NewEncoder(w).Encode(Books{Books: books})
+	})
+
+	JSONLDExporter Exporter = ExporterFunc(func(w io.Writer, books []Book) error {
+		docs := make([]map[string]interface{}, len(books))
+		for i, b := range books {
+			docs[i] = b.JSONLD()
+		}
+
+		return json.NewEncoder(w).Encode(docs)
+	})
+
+	JSONLExporter Exporter = ExporterFunc(func(w io.Writer, books []Book) error {
+		_, err := book.WriteJSONL(w, book.IterBooks(books))
+		return err
+	})
+
+	YAMLExporter Exporter = ExporterFunc(book.EncodeBooksYAML)
+	XMLExporter  Exporter = ExporterFunc(book.EncodeXML)
+
+	BibTeXExporter  Exporter = ExporterFunc(bibtex.Encode)
+	MARCExporter    Exporter = ExporterFunc(marc.Encode)
+	MARCXMLExporter Exporter = ExporterFunc(marc.EncodeXML)
+
+	MarkdownExporter Exporter = ExporterFunc(func(w io.Writer, books []Book) error {
+		return book.WriteMarkdown(w, books)
+	})
+
+	CSVExporter Exporter = ExporterFunc(func(w io.Writer, books []Book) error {
+		return book.BooksTable(books).WriteCSV(w)
+	})
+
+	NotionExporter       Exporter = ExporterFunc(book.WriteNotionCSV)
+	AirtableExporter     Exporter = ExporterFunc(book.WriteAirtableCSV)
+	GoodreadsCSVExporter Exporter = ExporterFunc(book.WriteGoodreadsCSV)
+)
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"github.com/dchooyc/book"
+)
+
+type Fetcher = book.Fetcher
+
+// NewFetcher returns the HTTP fetcher, configured with the same options as
+// the original Client.
+func NewFetcher(opts ...book.ClientOption) Fetcher {
+	return book.NewClient(opts...)
+}
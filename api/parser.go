@@ -0,0 +1,34 @@
+package api
+
+import (
+	"io"
+
+	"github.com/dchooyc/book"
+)
+
+type Parser interface {
+	ParseBook(r io.Reader) (*Book, error)
+	ParseBookURLs(r io.Reader) ([]string, error)
+	ParseList(r io.Reader) (*List, error)
+}
+
+type htmlParser struct {
+	opts []book.ParseOption
+}
+
+// NewParser returns the HTML parser for Goodreads pages.
+func NewParser(opts ...book.ParseOption) Parser {
+	return htmlParser{opts: opts}
+}
+
+func (p htmlParser) ParseBook(r io.Reader) (*Book, error) {
+	return book.GetBook(r, p.opts...)
+}
+
+func (p htmlParser) ParseBookURLs(r io.Reader) ([]string, error) {
+	return book.GetBookURLs(r)
+}
+
+func (p htmlParser) ParseList(r io.Reader) (*List, error) {
+	return book.GetList(r)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/dchooyc/book"
+)
+
+var ErrNotFound = book.ErrNotFound
+
+// Store keeps books keyed by work ID, falling back to URL for books
+// without one.
+type Store interface {
+	Put(ctx context.Context, b Book) error
+	Get(ctx context.Context, key string) (Book, error)
+	Keys(ctx context.Context) ([]string, error)
+}
+
+func Key(b Book) string {
+	if b.ID != "" {
+		return b.ID
+	}
+
+	return b.URL
+}
+
+type memoryStore struct {
+	mu    sync.RWMutex
+	books map[string]Book
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{books: map[string]Book{}}
+}
+
+func (s *memoryStore) Put(ctx context.Context, b Book) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.books[Key(b)] = b
+
+	return ctx.Err()
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (Book, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.books[key]
+	if !ok {
+		return Book{}, ErrNotFound
+	}
+
+	return b, ctx.Err()
+}
+
+func (s *memoryStore) Keys(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.books))
+	for key := range s.books {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, ctx.Err()
+}
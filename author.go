@@ -0,0 +1,286 @@
+package book
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	AuthorURLIndicator       = "/author/show/"
+	AuthorNameIndicator      = "authorName"
+	AuthorPhotoIndicator     = "authorLeftContainer"
+	AuthorDataTitleIndicator = "dataTitle"
+	AuthorBioIndicator       = "aboutAuthorInfo"
+	AuthorRatingIndicator    = "average"
+	AuthorFollowersIndicator = "/author_followings"
+)
+
+type Author struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	ID         string   `json:"id"`
+	PhotoURL   string   `json:"photo_url"`
+	Bio        string   `json:"bio"`
+	BirthDate  string   `json:"birth_date"`
+	DeathDate  string   `json:"death_date"`
+	Genres     []Genre  `json:"genres"`
+	Influences []string `json:"influences"`
+	Followers  int      `json:"followers"`
+	Rating     float64  `json:"rating"`
+}
+
+func GetAuthor(r io.Reader) (*Author, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	author := &Author{}
+
+	extractAuthorInfo(doc, author)
+
+	return author, nil
+}
+
+func extractAuthorInfo(doc *html.Node, author *Author) {
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		author.URL = getAttr(link, "href")
+		author.ID = leadingDigits(lastPathSegment(author.URL))
+	}
+
+	if h1 := findFirst(doc, byClass("h1", AuthorNameIndicator)); h1 != nil {
+		author.Name = textContent(h1)
+	}
+
+	if container := findFirst(doc, byClass("div", AuthorPhotoIndicator)); container != nil {
+		if img := findFirst(container, func(n *html.Node) bool { return isElement(n, "img") }); img != nil {
+			author.PhotoURL = getAttr(img, "src")
+		}
+	}
+
+	if n := findFirst(doc, byAttr("", "itemprop", "birthDate")); n != nil {
+		author.BirthDate = textContent(n)
+	}
+
+	if n := findFirst(doc, byAttr("", "itemprop", "deathDate")); n != nil {
+		author.DeathDate = textContent(n)
+	}
+
+	for _, title := range findAll(doc, byClass("div", AuthorDataTitleIndicator)) {
+		item := nextElementSibling(title)
+		if item == nil {
+			continue
+		}
+
+		switch textContent(title) {
+		case "Genre":
+			for _, a := range findAll(item, func(n *html.Node) bool { return isElement(n, "a") }) {
+				if strings.Contains(getAttr(a, "href"), BookGenresIndicator) {
+					author.Genres = append(author.Genres, Genre(lastPathSegment(getAttr(a, "href"))))
+				}
+			}
+		case "Influences":
+			for _, a := range findAll(item, func(n *html.Node) bool { return isElement(n, "a") }) {
+				if strings.Contains(getAttr(a, "href"), AuthorURLIndicator) {
+					author.Influences = append(author.Influences, textContent(a))
+				}
+			}
+		}
+	}
+
+	if info := findFirst(doc, byClass("div", AuthorBioIndicator)); info != nil {
+		author.Bio = extractFreeText(info)
+	}
+
+	if n := findFirst(doc, byClass("span", AuthorRatingIndicator)); n != nil {
+		val, err := strconv.ParseFloat(textContent(n), 64)
+		if err == nil {
+			author.Rating = val
+		}
+	}
+
+	followers := findFirst(doc, func(n *html.Node) bool {
+		return isElement(n, "a") && strings.HasPrefix(getAttr(n, "href"), AuthorFollowersIndicator)
+	})
+	if followers != nil {
+		text := textContent(followers)
+		if start := strings.LastIndex(text, "("); start >= 0 {
+			author.Followers = parseCount(text[start:])
+		}
+	}
+}
+
+func extractFreeText(n *html.Node) string {
+	short, full := "", ""
+
+	for _, span := range findAll(n, func(n *html.Node) bool { return isElement(n, "span") }) {
+		id := getAttr(span, "id")
+
+		switch {
+		case strings.HasPrefix(id, "freeTextContainer"):
+			if short == "" {
+				short = textContent(span)
+			}
+		case strings.HasPrefix(id, "freeText"):
+			if full == "" {
+				full = textContent(span)
+			}
+		}
+	}
+
+	if full == "" {
+		full = short
+	}
+
+	if full == "" {
+		full = textContent(n)
+	}
+
+	return full
+}
+
+// AuthorRef is an author as listed on a book. Refs without an ID marshal
+// to the plain author name, so encoded books look the same as before;
+// refs parsed from a page with an author link encode as
+// {"name":…,"id":…} in JSON and YAML so the ID survives a round trip.
+type AuthorRef struct {
+	name string
+	id   string
+}
+
+func NewAuthorRef(name, id string) AuthorRef {
+	return AuthorRef{name: name, id: id}
+}
+
+func (a AuthorRef) Name() string {
+	return a.name
+}
+
+func (a AuthorRef) ID() string {
+	return a.id
+}
+
+// Slug is the name as Goodreads writes it in author URLs, e.g.
+// "Frank_Herbert".
+func (a AuthorRef) Slug() string {
+	return strings.Join(strings.Fields(a.name), "_")
+}
+
+// Surname is the last word of the name along with any particles before
+// it, so "Ursula K. Le Guin" gives "Le Guin". A name already written
+// surname first, "Le Guin, Ursula K.", gives what comes before the comma.
+func (a AuthorRef) Surname() string {
+	if last, _, ok := strings.Cut(a.name, ","); ok {
+		return strings.Join(strings.Fields(last), " ")
+	}
+
+	parts := strings.Fields(a.name)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	start := len(parts) - 1
+	for start > 1 && surnameParticles[strings.ToLower(parts[start-1])] {
+		start--
+	}
+
+	return strings.Join(parts[start:], " ")
+}
+
+// SortName is the name written surname first, "Le Guin, Ursula K.", as
+// library catalogues and the Goodreads export's "Author l-f" column have
+// it. A name that already has a comma is taken to be in that form and is
+// only tidied.
+func (a AuthorRef) SortName() string {
+	if strings.Contains(a.name, ",") {
+		parts := strings.Split(a.name, ",")
+		for i, part := range parts {
+			parts[i] = strings.Join(strings.Fields(part), " ")
+		}
+
+		return strings.Join(parts, ", ")
+	}
+
+	last := a.Surname()
+
+	first := strings.TrimSpace(strings.TrimSuffix(strings.Join(strings.Fields(a.name), " "), last))
+	if first == "" {
+		return last
+	}
+
+	return last + ", " + first
+}
+
+var surnameParticles = map[string]bool{
+	"de": true, "del": true, "della": true, "der": true, "di": true, "du": true,
+	"la": true, "le": true, "van": true, "von": true, "da": true, "dos": true,
+}
+
+// URL is the author's profile page, or an author search for the name when
+// the ID is unknown.
+func (a AuthorRef) URL() string {
+	if a.id == "" {
+		return GoodreadsBaseURL + AuthorSearchURLPrefix + url.QueryEscape(a.name)
+	}
+
+	return GoodreadsBaseURL + AuthorURLIndicator + a.id + "." + url.PathEscape(a.Slug())
+}
+
+func (a AuthorRef) String() string {
+	return a.name
+}
+
+func (a AuthorRef) MarshalText() ([]byte, error) {
+	return []byte(a.name), nil
+}
+
+func (a *AuthorRef) UnmarshalText(text []byte) error {
+	*a = AuthorRef{name: string(text)}
+	return nil
+}
+
+// authorRefJSON is the encoded form of an AuthorRef with a known ID.
+type authorRefJSON struct {
+	Name string `json:"name" yaml:"name"`
+	ID   string `json:"id" yaml:"id"`
+}
+
+func (a AuthorRef) MarshalJSON() ([]byte, error) {
+	if a.id == "" {
+		return json.Marshal(a.name)
+	}
+
+	return json.Marshal(authorRefJSON{Name: a.name, ID: a.id})
+}
+
+// UnmarshalJSON accepts both the plain name and the {"name","id"} object.
+func (a *AuthorRef) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*a = AuthorRef{name: name}
+		return nil
+	}
+
+	var ref authorRefJSON
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return err
+	}
+
+	*a = AuthorRef{name: ref.Name, id: ref.ID}
+
+	return nil
+}
+
+func AuthorRefs(names ...string) []AuthorRef {
+	refs := make([]AuthorRef, len(names))
+	for i, name := range names {
+		refs[i] = AuthorRef{name: name}
+	}
+
+	return refs
+}
@@ -0,0 +1,148 @@
+package book_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+func TestAuthorRefJSONRoundTrip(t *testing.T) {
+	want := book.Book{
+		Title: "Good Omens",
+		Authors: []book.AuthorRef{
+			book.NewAuthorRef("Terry Pratchett", "1654"),
+			book.NewAuthorRef("Neil Gaiman", ""),
+		},
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(data, []byte(`"authors":[{"name":"Terry Pratchett","id":"1654"},"Neil Gaiman"]`)) {
+		t.Errorf("encoded authors as %s", data)
+	}
+
+	var got book.Book
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestAuthorRefYAMLRoundTrip(t *testing.T) {
+	want := book.Book{
+		Title: "Good Omens",
+		Authors: []book.AuthorRef{
+			book.NewAuthorRef("Terry Pratchett", "1654"),
+			book.NewAuthorRef("Neil Gaiman", ""),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := book.EncodeYAML(&buf, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := book.DecodeYAML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got.Authors, want.Authors) {
+		t.Errorf("got %+v, want %+v", got.Authors, want.Authors)
+	}
+}
+
+func TestAuthorRefUnmarshalPlainName(t *testing.T) {
+	var got []book.AuthorRef
+	if err := json.Unmarshal([]byte(`["Ursula K. Le Guin", {"name": "Iain M. Banks", "id": "7628"}]`), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []book.AuthorRef{
+		book.NewAuthorRef("Ursula K. Le Guin", ""),
+		book.NewAuthorRef("Iain M. Banks", "7628"),
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+const authorPage = `<!DOCTYPE html>
+<html><head>
+<link rel="canonical" href="https://www.goodreads.com/author/show/58.Frank_Herbert">
+</head><body>
+<div class="authorLeftContainer">
+  <a href="/photo/author/58.Frank_Herbert"><img alt="Frank Herbert" src="https://images.gr-assets.com/authors/1168661521p5/58.jpg"></a>
+</div>
+<div class="rightContainer">
+  <h1 class="authorName"><span itemprop="name">Frank Herbert</span></h1>
+  <div class="dataTitle">Born</div>
+  <div class="dataItem">in Tacoma, Washington, The United States</div>
+  <div class="dataItem" itemprop="birthDate">October 08, 1920</div>
+  <div class="dataTitle">Died</div>
+  <div class="dataItem" itemprop="deathDate">February 11, 1986</div>
+  <div class="dataTitle">Genre</div>
+  <div class="dataItem">
+    <a href="/genres/science-fiction">Science Fiction</a>, <a href="/genres/fantasy">Fantasy</a>
+  </div>
+  <div class="dataTitle">Influences</div>
+  <div class="dataItem">
+    <span id="freeTextContainerauthor58"><a href="/author/show/1455.Aldous_Huxley">Aldous Huxley</a>, <a href="/author/show/7604.Jack_Vance">Jack Vance</a></span>
+  </div>
+  <div class="aboutAuthorInfo">
+    <span id="freeTextContainerauthor58">Franklin Patrick Herbert Jr. was an American science fiction…</span>
+    <span id="freeTextauthor58" style="display:none">Franklin Patrick Herbert Jr. was an American science fiction author best known for Dune.</span>
+  </div>
+  <div class="hreview-aggregate">
+    Average rating <span class="average">4.14</span>
+  </div>
+  <a href="/author_followings?id=58&amp;method=get">Frank Herbert's Followers (12,345)</a>
+</div>
+</body></html>`
+
+func TestGetAuthor(t *testing.T) {
+	got, err := book.GetAuthor(strings.NewReader(authorPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &book.Author{
+		Name:       "Frank Herbert",
+		URL:        "https://www.goodreads.com/author/show/58.Frank_Herbert",
+		ID:         "58",
+		PhotoURL:   "https://images.gr-assets.com/authors/1168661521p5/58.jpg",
+		Bio:        "Franklin Patrick Herbert Jr. was an American science fiction author best known for Dune.",
+		BirthDate:  "October 08, 1920",
+		DeathDate:  "February 11, 1986",
+		Genres:     []book.Genre{"science-fiction", "fantasy"},
+		Influences: []string{"Aldous Huxley", "Jack Vance"},
+		Followers:  12345,
+		Rating:     4.14,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAuthor =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestGetAuthorMissingFields(t *testing.T) {
+	got, err := book.GetAuthor(strings.NewReader(`<html><body><h1 class="authorName">Anonymous</h1><span class="average">n/a</span></body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (&book.Author{Name: "Anonymous"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAuthor = %+v, want %+v", got, want)
+	}
+}
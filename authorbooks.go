@@ -0,0 +1,33 @@
+package book
+
+import (
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+const AuthorBooksURLIndicator = "/author/list/"
+
+type AuthorBooks struct {
+	Author   string    `json:"author"`
+	Books    []BookRef `json:"books"`
+	NextPage string    `json:"next_page,omitempty"`
+}
+
+func GetAuthorBooks(r io.Reader) (*AuthorBooks, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &AuthorBooks{
+		Books:    extractBookRefRows(doc),
+		NextPage: extractNextPage(doc),
+	}
+
+	if name := findFirst(doc, byClass("a", AuthorNameIndicator)); name != nil {
+		list.Author = textContent(name)
+	}
+
+	return list, nil
+}
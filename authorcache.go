@@ -0,0 +1,172 @@
+package book
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+const AuthorSearchURLPrefix = "/search?search_type=authors&q="
+
+type AuthorCache struct {
+	path string
+
+	mu    sync.RWMutex
+	ids   map[string]string
+	dirty bool
+}
+
+func NewAuthorCache() *AuthorCache {
+	return &AuthorCache{ids: map[string]string{}}
+}
+
+func LoadAuthorCache(path string) (*AuthorCache, error) {
+	cache := NewAuthorCache()
+	cache.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &cache.ids); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+func (c *AuthorCache) Lookup(name string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	id, ok := c.ids[NormalizeTitle(name)]
+
+	return id, ok
+}
+
+func (c *AuthorCache) Store(name, id string) {
+	key := NormalizeTitle(name)
+	if key == "" || id == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ids[key] != id {
+		c.ids[key] = id
+		c.dirty = true
+	}
+}
+
+func (c *AuthorCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.ids)
+}
+
+func (c *AuthorCache) Save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c.ids, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+
+	c.dirty = false
+
+	return nil
+}
+
+func WithAuthorCache(cache *AuthorCache) ClientOption {
+	return func(c *Client) {
+		c.authorCache = cache
+	}
+}
+
+func (c *Client) ResolveAuthorID(ctx context.Context, name string) (string, error) {
+	if c.authorCache != nil {
+		if id, ok := c.authorCache.Lookup(name); ok {
+			return id, nil
+		}
+	}
+
+	body, err := c.Get(ctx, AuthorSearchURLPrefix+url.QueryEscape(name))
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	want := NormalizeTitle(name)
+	id := ""
+
+	for _, a := range findAll(doc, func(n *html.Node) bool { return isElement(n, "a") }) {
+		href := getAttr(a, "href")
+		if !strings.Contains(href, AuthorURLIndicator) {
+			continue
+		}
+
+		candidate := leadingDigits(lastPathSegment(href))
+		if candidate == "" {
+			continue
+		}
+
+		if id == "" {
+			id = candidate
+		}
+
+		if NormalizeTitle(textContent(a)) == want {
+			id = candidate
+			break
+		}
+	}
+
+	if id == "" {
+		return "", ErrNotFound
+	}
+
+	c.rememberAuthor(name, id)
+
+	return id, nil
+}
+
+func (c *Client) rememberAuthor(name, id string) {
+	if c.authorCache != nil {
+		c.authorCache.Store(name, id)
+	}
+}
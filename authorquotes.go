@@ -0,0 +1,54 @@
+package book
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const AuthorQuotesURLIndicator = "/author/quotes/"
+
+type AuthorQuotes struct {
+	Author   string  `json:"author"`
+	AuthorID string  `json:"author_id"`
+	Quotes   []Quote `json:"quotes"`
+	NextPage string  `json:"next_page,omitempty"`
+}
+
+func GetAuthorQuotes(r io.Reader) (*AuthorQuotes, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &AuthorQuotes{
+		Quotes:   []Quote{},
+		NextPage: extractNextPage(doc),
+	}
+
+	if h1 := findFirst(doc, func(n *html.Node) bool { return isElement(n, "h1") }); h1 != nil {
+		link := findFirst(h1, func(n *html.Node) bool {
+			return isElement(n, "a") && strings.Contains(getAttr(n, "href"), AuthorURLIndicator)
+		})
+		if link != nil {
+			page.Author = textContent(link)
+			page.AuthorID = leadingDigits(lastPathSegment(getAttr(link, "href")))
+		}
+	}
+
+	for _, n := range findAll(doc, byClass("div", QuoteIndicator)) {
+		quote, ok := extractQuote(n)
+		if !ok {
+			continue
+		}
+
+		if quote.Author == "" {
+			quote.Author = page.Author
+		}
+
+		page.Quotes = append(page.Quotes, quote)
+	}
+
+	return page, nil
+}
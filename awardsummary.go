@@ -0,0 +1,91 @@
+package book
+
+import "sort"
+
+type AwardSummary struct {
+	Author      string       `json:"author"`
+	Wins        int          `json:"wins"`
+	Nominations int          `json:"nominations"`
+	Awards      []AwardTally `json:"awards"`
+}
+
+// AwardTally counts an author's results in one award category and year.
+// Nominations includes wins.
+type AwardTally struct {
+	Award       string   `json:"award"`
+	Year        int      `json:"year"`
+	Wins        int      `json:"wins"`
+	Nominations int      `json:"nominations"`
+	Works       []string `json:"works"`
+}
+
+// AwardSummary rolls up the author's wins and nominations across crawled
+// award categories, matching nominees by author name. Tallies are ordered
+// by year, newest first, then by award.
+func (a Author) AwardSummary(categories []ChoiceCategory) AwardSummary {
+	summary := AwardSummary{Author: a.Name, Awards: []AwardTally{}}
+	name := NormalizeTitle(a.Name)
+
+	for _, category := range categories {
+		tally := AwardTally{Award: category.Name, Year: category.Year, Works: []string{}}
+
+		for _, nominee := range categoryEntries(category) {
+			if !hasAuthor(nominee.Authors, name) {
+				continue
+			}
+
+			tally.Nominations++
+			tally.Works = append(tally.Works, nominee.Title)
+
+			if nominee.Winner {
+				tally.Wins++
+			}
+		}
+
+		if tally.Nominations == 0 {
+			continue
+		}
+
+		summary.Wins += tally.Wins
+		summary.Nominations += tally.Nominations
+		summary.Awards = append(summary.Awards, tally)
+	}
+
+	sort.SliceStable(summary.Awards, func(i, j int) bool {
+		if summary.Awards[i].Year != summary.Awards[j].Year {
+			return summary.Awards[i].Year > summary.Awards[j].Year
+		}
+
+		return summary.Awards[i].Award < summary.Awards[j].Award
+	})
+
+	return summary
+}
+
+// categoryEntries is the nominee list plus the winner when the page only
+// showed it separately.
+func categoryEntries(category ChoiceCategory) []ChoiceNominee {
+	entries := category.Nominees
+
+	if category.Winner == nil {
+		return entries
+	}
+
+	for _, nominee := range entries {
+		if nominee.URL == category.Winner.URL {
+			return entries
+		}
+	}
+
+	return append(append([]ChoiceNominee{}, entries...), *category.Winner)
+}
+
+func hasAuthor(authors []string, normalized string) bool {
+	for _, author := range authors {
+		if NormalizeTitle(author) == normalized {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,201 @@
+// Package bibtex writes books as BibTeX @book entries for citing them
+// from LaTeX.
+package bibtex
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dchooyc/book"
+)
+
+// ErrNoTitle is returned for a book without a title, which every BibTeX
+// style needs for a @book entry.
+var ErrNoTitle = errors.New("book has no title")
+
+// Encoder writes @book entries, keeping citation keys unique across
+// everything it has written.
+type Encoder struct {
+	w    *bufio.Writer
+	keys map[string]int
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w), keys: map[string]int{}}
+}
+
+// Encode writes one entry per book. Keys that collide, such as two books
+// by one author in the same year and with the same first title word, get
+// "a", "b" and so on appended in the order they were written. It stops
+// with ErrNoTitle at the first book without a title, having written the
+// ones before it.
+func (e *Encoder) Encode(books ...book.Book) error {
+	for _, b := range books {
+		if strings.TrimSpace(b.Title) == "" {
+			if err := e.w.Flush(); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("%w: %s", ErrNoTitle, firstNonEmpty(b.URL, b.ID))
+		}
+
+		key := Key(b)
+
+		e.keys[key]++
+		if n := e.keys[key]; n > 1 {
+			key += suffix(n - 1)
+		}
+
+		writeEntry(e.w, key, b)
+	}
+
+	return e.w.Flush()
+}
+
+// Encode writes books to w as a BibTeX database.
+func Encode(w io.Writer, books []book.Book) error {
+	return NewEncoder(w).Encode(books...)
+}
+
+var stopWords = map[string]bool{"a": true, "an": true, "the": true, "of": true, "on": true, "in": true}
+
+// Key builds a citation key from the first author's surname, the year and
+// the first significant word of the title, e.g. "leguin1974dispossessed".
+// It only depends on the Book, so re-exporting keeps keys stable.
+func Key(b book.Book) string {
+	var sb strings.Builder
+
+	if len(b.Authors) > 0 {
+		sb.WriteString(keyPart(b.Authors[0].Surname()))
+	}
+
+	if year := Year(b); year != 0 {
+		sb.WriteString(strconv.Itoa(year))
+	}
+
+	for _, word := range strings.Fields(book.Transliterate(b.Title)) {
+		if part := keyPart(word); part != "" && !stopWords[part] {
+			sb.WriteString(part)
+			break
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "goodreads" + b.ID
+	}
+
+	return sb.String()
+}
+
+// Year is the year of the edition's publication, or the work's first
+// publication when the edition's isn't known, or zero.
+func Year(b book.Book) int {
+	if b.Details == nil {
+		return 0
+	}
+
+	for _, date := range []string{b.Details.Published, b.Details.FirstPublished} {
+		if d, err := book.ParseDate(date); err == nil {
+			return d.Time.Year()
+		}
+	}
+
+	return 0
+}
+
+func keyPart(s string) string {
+	var sb strings.Builder
+
+	for _, r := range strings.ToLower(book.Transliterate(s)) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func suffix(n int) string {
+	s := ""
+	for n > 0 {
+		n--
+		s = string(rune('a'+n%26)) + s
+		n /= 26
+	}
+
+	return s
+}
+
+func writeEntry(w *bufio.Writer, key string, b book.Book) {
+	// "Last, First" keeps BibTeX from splitting a capitalised particle
+	// such as "Le" off the surname.
+	authors := make([]string, len(b.Authors))
+	for i, author := range b.Authors {
+		authors[i] = escape(author.SortName())
+	}
+
+	fields := [][2]string{
+		{"author", strings.Join(authors, " and ")},
+		// Double braces keep BibTeX styles from lower-casing the title.
+		{"title", "{" + escape(b.Title) + "}"},
+	}
+
+	var publisher, isbn string
+	if d := b.Details; d != nil {
+		publisher = d.Publisher
+		isbn = firstNonEmpty(d.ISBN13, d.ISBN10)
+	}
+
+	if year := Year(b); year != 0 {
+		fields = append(fields, [2]string{"year", strconv.Itoa(year)})
+	}
+
+	fields = append(fields,
+		[2]string{"publisher", escape(publisher)},
+		[2]string{"isbn", firstNonEmpty(isbn, b.ISBN)},
+		[2]string{"url", b.URL},
+	)
+
+	w.WriteString("@book{" + key)
+
+	for _, f := range fields {
+		if f[1] == "" || f[1] == "{}" {
+			continue
+		}
+
+		w.WriteString(",\n  " + f[0] + " = {" + f[1] + "}")
+	}
+
+	w.WriteString("\n}\n\n")
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
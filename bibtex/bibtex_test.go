@@ -0,0 +1,51 @@
+package bibtex_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/bibtex"
+)
+
+func TestEncodeRejectsMissingTitle(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := bibtex.Encode(&buf, []book.Book{
+		{Title: "Dune", ID: "234225", Authors: book.AuthorRefs("Frank Herbert")},
+		{ID: "2", URL: "https://www.goodreads.com/book/show/2"},
+	})
+	if !errors.Is(err, bibtex.ErrNoTitle) {
+		t.Fatalf("err = %v, want ErrNoTitle", err)
+	}
+
+	if !strings.Contains(buf.String(), "@book{herbertdune,") {
+		t.Errorf("books before the untitled one were not written:\n%s", buf.String())
+	}
+
+	if strings.Contains(buf.String(), "goodreads2") {
+		t.Errorf("untitled book was written:\n%s", buf.String())
+	}
+}
+
+func TestEncodeKeepsSurnameFirstNames(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := bibtex.Encode(&buf, []book.Book{
+		{Title: "Dune", Authors: book.AuthorRefs("Herbert, Frank", "Ursula K. Le Guin")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "author = {Herbert, Frank and Le Guin, Ursula K.}"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("entry has no %q:\n%s", want, buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "@book{herbertdune,") {
+		t.Errorf("key not built from the surname before the comma:\n%s", buf.String())
+	}
+}
@@ -1,9 +1,8 @@
 package book
 
 import (
-	"fmt"
+	"errors"
 	"io"
-	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
@@ -18,22 +17,45 @@ const (
 	BookGenresIndicator  = "/genres/"
 	BookRatingIndicator  = "RatingStatistics__rating"
 	BookStatsIndicator   = "RatingStatistics__meta"
+	BookTitleClass       = "Text Text__title1"
+	BookTitleTestID      = "bookTitle"
+	BookCoverImageClass  = "ResponsiveImage"
 )
 
 type Books struct {
-	Books []Book `json:"books"`
+	Books []Book `json:"books" yaml:"books" xml:"book"`
 }
 
 type Book struct {
-	Title    string   `json:"title"`
-	URL      string   `json:"url"`
-	ID       string   `json:"id"`
-	CoverUrl string   `json:"cover_url"`
-	Authors  []string `json:"authors"`
-	Genres   []string `json:"genres"`
-	Rating   float64  `json:"rating"`
-	Ratings  int      `json:"ratings"`
-	Reviews  int      `json:"reviews"`
+	Title    string       `json:"title" yaml:"title" xml:"title"`
+	URL      string       `json:"url" yaml:"url" xml:"url"`
+	ID       string       `json:"id" yaml:"id" xml:"id"`
+	CoverUrl string       `json:"cover_url" yaml:"cover_url" xml:"cover_url"`
+	Authors  []AuthorRef  `json:"authors" yaml:"authors" xml:"authors>author"`
+	Genres   []Genre      `json:"genres" yaml:"genres" xml:"genres>genre"`
+	Rating   float64      `json:"rating" yaml:"rating" xml:"rating"`
+	Ratings  int          `json:"ratings" yaml:"ratings" xml:"ratings"`
+	Reviews  int          `json:"reviews" yaml:"reviews" xml:"reviews"`
+	ISBN     string       `json:"isbn,omitempty" yaml:"isbn,omitempty" xml:"isbn,omitempty"`
+	Details  *BookDetails `json:"details,omitempty" yaml:"details,omitempty" xml:"details,omitempty"`
+}
+
+func (b Book) AuthorNames() []string {
+	names := make([]string, len(b.Authors))
+	for i, author := range b.Authors {
+		names[i] = author.Name()
+	}
+
+	return names
+}
+
+func (b Book) GenreSlugs() []string {
+	slugs := make([]string, len(b.Genres))
+	for i, genre := range b.Genres {
+		slugs[i] = genre.Slug()
+	}
+
+	return slugs
 }
 
 func GetBookURLs(r io.Reader) ([]string, error) {
@@ -69,109 +91,124 @@ func extractURLs(n *html.Node, urls *[]string) {
 	}
 }
 
-func GetBook(r io.Reader) (*Book, error) {
-	doc, err := html.Parse(r)
-	if err != nil {
-		return nil, err
-	}
-
-	book := &Book{}
-
-	extractBookInfo(doc, book)
-
-	return book, nil
+// GetBook parses a book page. A captcha or block page served in its place
+// is reported as a *BlockedError rather than an empty Book, and a sign-in
+// wall or age check as a *GatedError. Fields that
+// are present but can't be parsed are reported together in the returned
+// error, alongside the Book with everything else that did parse, unless
+// WithParseMode(ParseStrict) asks for all or nothing.
+func GetBook(r io.Reader, opts ...ParseOption) (*Book, error) {
+	book, _, err := GetBookWithReport(r, opts...)
+	return book, err
 }
 
-func extractBookInfo(n *html.Node, curBook *Book) {
+// extractBookInfo walks the modern layout. It records a source only for
+// the numeric fields, whose zero value is also a real value, so the
+// report can tell a book without ratings from a page without the stats.
+func extractBookInfo(n *html.Node, curBook *Book, cfg *parseConfig, sources map[string]string, errs *[]error) {
 	if n.Type == html.ElementNode && n.Data == "a" {
-		extractID(n, curBook)
-		extractGenres(n, curBook)
+		extractID(n, curBook, cfg)
+		extractGenres(n, curBook, cfg)
 	}
 
 	if n.Type == html.ElementNode && n.Data == "div" {
-		extractCover(n, curBook)
-		extractRating(n, curBook)
-		extractStats(n, curBook)
-		extractAuthors(n, curBook)
+		extractCover(n, curBook, cfg)
+
+		if err := extractRating(n, curBook, cfg, sources); err != nil {
+			*errs = append(*errs, err)
+		}
+
+		if err := extractStats(n, curBook, cfg, sources); err != nil {
+			*errs = append(*errs, err)
+		}
+
+		extractAuthors(n, curBook, cfg)
 	}
 
 	if n.Type == html.ElementNode && n.Data == "h1" {
-		extractTitle(n, curBook)
+		if err := extractTitle(n, curBook, cfg); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+
+	if n.Type == html.ElementNode && n.Data == "link" {
+		extractURL(n, curBook, cfg)
 	}
 
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		extractBookInfo(c, curBook)
+		extractBookInfo(c, curBook, cfg, sources, errs)
 	}
 }
 
-func extractRating(n *html.Node, curBook *Book) {
-	for _, attr := range n.Attr {
-		if attr.Key == "class" && attr.Val == BookRatingIndicator {
-			textNode := n.FirstChild
-
-			if textNode != nil {
-				val, err := strconv.ParseFloat(textNode.Data, 64)
-				if err != nil {
-					fmt.Println(err)
-				}
+func extractRating(n *html.Node, curBook *Book, cfg *parseConfig, sources map[string]string) error {
+	if !hasClass(n, cfg.selectors.Rating) {
+		return nil
+	}
 
-				curBook.Rating = val
-			}
+	textNode := n.FirstChild
 
-			break
+	if textNode != nil {
+		val, err := parseLocaleFloat(textNode.Data)
+		if err != nil {
+			return newFieldError(FieldRating, err, textNode.Data)
 		}
+
+		curBook.Rating = val
+		sources[FieldRating] = `div.` + cfg.selectors.Rating
 	}
-}
 
-func extractStats(n *html.Node, curBook *Book) {
-	correctClass, val := false, ""
+	return nil
+}
 
-	for _, attr := range n.Attr {
-		if attr.Key == "class" && attr.Val == BookStatsIndicator {
-			correctClass = true
-		}
+func extractStats(n *html.Node, curBook *Book, cfg *parseConfig, sources map[string]string) error {
+	if !hasClass(n, cfg.selectors.Stats) {
+		return nil
+	}
 
-		if attr.Key == "aria-label" {
-			val = attr.Val
-		}
+	val := getAttr(n, "aria-label")
 
-		if correctClass && val != "" {
-			break
-		}
+	// The label reads "1,234 ratings and 56 reviews" in English; other
+	// languages change the words and the grouping marks.
+	ratings, reviews, ok := statsCounts(val)
+	if !ok {
+		return newFieldError(FieldStats, ErrUnexpectedFormat, val)
 	}
 
-	if correctClass {
-		parts := strings.Split(val, " ")
-		ratings := parts[0]
-		reviews := parts[3]
-		ratings = strings.Join(strings.Split(ratings, ","), "")
-		reviews = strings.Join(strings.Split(reviews, ","), "")
+	var errs []error
 
-		ratingsVal, err := strconv.Atoi(ratings)
-		if err != nil {
-			fmt.Println(err)
-		}
+	source := `div.` + cfg.selectors.Stats + `@aria-label`
 
+	ratingsVal, err := parseStatCount(ratings)
+	if err != nil {
+		errs = append(errs, newFieldError(FieldRatings, err, ratings))
+	} else {
 		curBook.Ratings = ratingsVal
+		sources[FieldRatings] = source
+	}
 
-		reviewsVal, err := strconv.Atoi(reviews)
-		if err != nil {
-			fmt.Println(err)
-		}
-
+	reviewsVal, err := parseStatCount(reviews)
+	if err != nil {
+		errs = append(errs, newFieldError(FieldReviews, err, reviews))
+	} else {
 		curBook.Reviews = reviewsVal
+		sources[FieldReviews] = source
 	}
+
+	return errors.Join(errs...)
 }
 
-func extractGenres(n *html.Node, curBook *Book) {
+func extractGenres(n *html.Node, curBook *Book, cfg *parseConfig) {
 	for _, attr := range n.Attr {
 		if attr.Key == "href" {
 			url := attr.Val
 
-			if strings.Contains(url, BookGenresIndicator) {
+			if strings.Contains(url, cfg.selectors.Genres) {
 				parts := strings.Split(url, "/")
 				genre := parts[len(parts)-1]
-				curBook.Genres = append(curBook.Genres, genre)
+
+				if !cfg.excludesGenre(genre) {
+					curBook.Genres = append(curBook.Genres, Genre(genre))
+				}
 			}
 
 			break
@@ -179,85 +216,85 @@ func extractGenres(n *html.Node, curBook *Book) {
 	}
 }
 
-func extractAuthors(n *html.Node, curBook *Book) {
-	for _, attr := range n.Attr {
-		if attr.Key == "class" && attr.Val == BookAuthorsIndicator {
-			authors := []string{}
+// extractAuthors reads each author link anywhere under the contributor
+// list, profile links as well as the author searches AuthorRef.URL
+// writes for authors without an ID. The name comes from the link's name
+// span when it has one, so role labels such as "(Translator)" stay out
+// of it, and otherwise from all the link's text.
+func extractAuthors(n *html.Node, curBook *Book, cfg *parseConfig) {
+	if !hasClass(n, cfg.selectors.Authors) {
+		return
+	}
 
-			for c := n.FirstChild; c != nil; c = c.NextSibling {
-				aNode := c.FirstChild
-				if aNode == nil || aNode.Data != "a" {
-					continue
-				}
+	authors := []AuthorRef{}
+	seen := map[AuthorRef]bool{}
 
-				spanNode := aNode.FirstChild
-				if spanNode == nil || spanNode.Data != "span" {
-					continue
-				}
+	isAuthorLink := func(n *html.Node) bool {
+		return byHref(AuthorURLIndicator)(n) || byHref(AuthorSearchURLPrefix)(n)
+	}
 
-				name := spanNode.FirstChild
-				if name.Type != html.TextNode {
-					continue
-				}
+	for _, link := range findAll(n, isAuthorLink) {
+		nameNode := findFirst(link, byAttr("", "data-testid", "name"))
+		if nameNode == nil {
+			nameNode = link
+		}
 
-				authors = append(authors, name.Data)
-			}
+		name := textContent(nameNode)
+		if name == "" {
+			continue
+		}
 
-			curBook.Authors = authors
-			break
+		id := ""
+		if href := getAttr(link, "href"); strings.Contains(href, AuthorURLIndicator) {
+			id = leadingDigits(lastPathSegment(href))
 		}
-	}
-}
 
-func extractCover(n *html.Node, curBook *Book) {
-	for _, attr := range n.Attr {
-		if attr.Key == "class" && attr.Val == BookCoverIndicator {
-			targetDiv := n.FirstChild
-			if targetDiv == nil {
-				continue
-			}
+		author := NewAuthorRef(name, id)
 
-			imageNode := targetDiv.FirstChild
-			if imageNode == nil || imageNode.Data != "img" {
-				continue
-			}
+		// The list repeats contributors it collapses behind "...more".
+		if seen[author] {
+			continue
+		}
 
-			correctClass, correctRole, imgSRC := false, false, ""
+		seen[author] = true
+		authors = append(authors, author)
+	}
 
-			for _, attr := range imageNode.Attr {
-				if attr.Key == "class" && attr.Val == "ResponsiveImage" {
-					correctClass = true
-				}
+	curBook.Authors = authors
+}
 
-				if attr.Key == "role" && attr.Val == "presentation" {
-					correctRole = true
-				}
+func extractCover(n *html.Node, curBook *Book, cfg *parseConfig) {
+	if !hasClass(n, cfg.selectors.Cover) {
+		return
+	}
 
-				if attr.Key == "src" {
-					imgSRC = attr.Val
-				}
+	targetDiv := n.FirstChild
+	if targetDiv == nil {
+		return
+	}
 
-				if correctClass && correctRole && imgSRC != "" {
-					break
-				}
-			}
+	imageNode := targetDiv.FirstChild
+	if !isElement(imageNode, "img") {
+		return
+	}
 
-			if correctClass && correctRole {
-				curBook.CoverUrl = imgSRC
-			}
-		}
+	if hasClass(imageNode, cfg.selectors.CoverImage) && getAttr(imageNode, "role") == "presentation" {
+		curBook.CoverUrl = getAttr(imageNode, "src")
 	}
 }
 
-func extractID(n *html.Node, curBook *Book) {
+func extractID(n *html.Node, curBook *Book, cfg *parseConfig) {
 	for _, attr := range n.Attr {
 		if attr.Key == "href" {
 			url := attr.Val
 
-			if strings.Contains(url, BookIDIndicator) {
-				parts := strings.Split(url, "/")
-				id := parts[len(parts)-1]
-				curBook.ID = id
+			// The work ID is kept numeric, as the legacy, mobile and
+			// __NEXT_DATA__ parsers give it, so "3634639-dune" and
+			// "3634639" name the same book.
+			if strings.Contains(url, cfg.selectors.ID) {
+				if id := leadingDigits(lastPathSegment(url)); id != "" {
+					curBook.ID = id
+				}
 			}
 
 			break
@@ -265,28 +302,37 @@ func extractID(n *html.Node, curBook *Book) {
 	}
 }
 
-func extractTitle(n *html.Node, curBook *Book) {
-	correctClass, correctData, title := false, false, ""
+func extractTitle(n *html.Node, curBook *Book, cfg *parseConfig) error {
+	if !hasClass(n, cfg.selectors.TitleClass) || getAttr(n, "data-testid") != cfg.selectors.TitleTestID {
+		return nil
+	}
 
-	for _, attr := range n.Attr {
-		if attr.Key == "class" && attr.Val == "Text Text__title1" {
-			correctClass = true
-		}
+	label := getAttr(n, "aria-label")
 
-		if attr.Key == "data-testid" && attr.Val == "bookTitle" {
-			correctData = true
-		}
+	title, ok := strings.CutPrefix(label, cfg.selectors.TitlePrefix)
+	if !ok {
+		return newFieldError(FieldTitle, ErrUnexpectedFormat, label)
+	}
+
+	curBook.Title = title
 
-		if attr.Key == "aria-label" {
-			title = attr.Val[len(BookTitlePrefix):]
+	return nil
+}
+
+func extractURL(n *html.Node, curBook *Book, cfg *parseConfig) {
+	correctRel, url := false, ""
+
+	for _, attr := range n.Attr {
+		if attr.Key == "rel" && attr.Val == "canonical" {
+			correctRel = true
 		}
 
-		if correctClass && correctData && title != "" {
-			break
+		if attr.Key == "href" {
+			url = attr.Val
 		}
 	}
 
-	if correctClass && correctData {
-		curBook.Title = title
+	if correctRel && strings.Contains(url, cfg.selectors.URL) {
+		curBook.URL = url
 	}
 }
@@ -0,0 +1,376 @@
+// Package bookdb stores scraped books in a SQLite database and queries
+// them by author, genre and rating.
+//
+// Each book is kept whole as JSON next to the columns it is queried by,
+// so fields added to Book later survive a round trip without a schema
+// change.
+package bookdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dchooyc/book"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var (
+	ErrNotFound = errors.New("book not found")
+	ErrNoID     = errors.New("book has no ID")
+
+	// ErrSchemaTooNew is returned when the database was migrated by a newer
+	// version of this package than the one opening it.
+	ErrSchemaTooNew = errors.New("database schema is newer than this package")
+)
+
+// migrations are applied in order and never edited once released; the
+// database's user_version records how many have run.
+var migrations = []string{
+	`CREATE TABLE books (
+		id            TEXT PRIMARY KEY,
+		url           TEXT NOT NULL DEFAULT '',
+		title         TEXT NOT NULL DEFAULT '',
+		rating        REAL NOT NULL DEFAULT 0,
+		ratings_count INTEGER NOT NULL DEFAULT 0,
+		reviews_count INTEGER NOT NULL DEFAULT 0,
+		data          TEXT NOT NULL,
+		updated_at    TEXT NOT NULL
+	);
+	CREATE TABLE book_authors (
+		book_id   TEXT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+		position  INTEGER NOT NULL,
+		author_id TEXT NOT NULL DEFAULT '',
+		name      TEXT NOT NULL COLLATE NOCASE,
+		PRIMARY KEY (book_id, position)
+	);
+	CREATE TABLE book_genres (
+		book_id TEXT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+		genre   TEXT NOT NULL,
+		PRIMARY KEY (book_id, genre)
+	);
+	CREATE INDEX books_rating ON books(rating);
+	CREATE INDEX book_authors_name ON book_authors(name);
+	CREATE INDEX book_authors_author_id ON book_authors(author_id);
+	CREATE INDEX book_genres_genre ON book_genres(genre);`,
+}
+
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens or creates the SQLite database at path and migrates it to
+// the current schema.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY
+	// between the pool's own connections.
+	db.SetMaxOpenConns(1)
+
+	s, err := New(context.Background(), db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// New wraps an already open SQLite handle, such as one from a different
+// driver, and migrates it to the current schema.
+func New(ctx context.Context, db *sql.DB) (*Store, error) {
+	s := &Store{db: db}
+	if err := s.Migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// DB returns the underlying handle for queries this package doesn't cover.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Version is the number of migrations the database has had applied.
+func (s *Store) Version(ctx context.Context) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version)
+
+	return version, err
+}
+
+// Migrate applies any migrations the database hasn't had yet, each in its
+// own transaction. Open and New call it, so it only needs calling
+// directly on a handle shared with other code.
+func (s *Store) Migrate(ctx context.Context) error {
+	version, err := s.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("%w: version %d, latest known %d", ErrSchemaTooNew, version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		err := s.tx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+				return err
+			}
+
+			_, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1))
+
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("bookdb: migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) tx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Upsert inserts books, replacing any already stored under the same ID,
+// in a single transaction. A book without an ID fails the whole batch
+// with ErrNoID.
+func (s *Store) Upsert(ctx context.Context, books ...book.Book) error {
+	return s.upsertAt(ctx, time.Now(), books...)
+}
+
+// upsertAt is Upsert with the time recorded as the books' updated_at.
+func (s *Store) upsertAt(ctx context.Context, at time.Time, books ...book.Book) error {
+	now := at.UTC().Format(time.RFC3339)
+
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		for _, b := range books {
+			if b.ID == "" {
+				return fmt.Errorf("%w: %s", ErrNoID, b.URL)
+			}
+
+			if err := upsert(ctx, tx, b, now); err != nil {
+				return fmt.Errorf("%s: %w", b.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func upsert(ctx context.Context, tx *sql.Tx, b book.Book, now string) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO books (id, url, title, rating, ratings_count, reviews_count, data, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			url = excluded.url,
+			title = excluded.title,
+			rating = excluded.rating,
+			ratings_count = excluded.ratings_count,
+			reviews_count = excluded.reviews_count,
+			data = excluded.data,
+			updated_at = excluded.updated_at`,
+		b.ID, b.URL, b.Title, b.Rating, b.Ratings, b.Reviews, string(data), now)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"book_authors", "book_genres"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE book_id = ?", b.ID); err != nil {
+			return err
+		}
+	}
+
+	for i, a := range b.Authors {
+		_, err := tx.ExecContext(ctx, "INSERT INTO book_authors (book_id, position, author_id, name) VALUES (?, ?, ?, ?)",
+			b.ID, i, a.ID(), a.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, g := range b.Genres {
+		_, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO book_genres (book_id, genre) VALUES (?, ?)", b.ID, string(g))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*book.Book, error) {
+	var data string
+
+	err := s.db.QueryRowContext(ctx, "SELECT data FROM books WHERE id = ?", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	b := &book.Book{}
+	if err := json.Unmarshal([]byte(data), b); err != nil {
+		return nil, fmt.Errorf("%s: %w", id, err)
+	}
+
+	return b, nil
+}
+
+// Delete removes the book stored under id, returning ErrNotFound if there
+// was none.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM books WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+func (s *Store) Count(ctx context.Context) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM books").Scan(&n)
+
+	return n, err
+}
+
+// Query filters the books Find returns. Zero fields don't filter.
+type Query struct {
+	// Author matches an author's name, ignoring case, or their Goodreads
+	// ID.
+	Author string
+	Genre  book.Genre
+
+	MinRating float64
+	MaxRating float64
+
+	// MinRatings drops books with fewer ratings, whose average says
+	// little.
+	MinRatings int
+
+	// Filter is a book.ParseFilter expression, such as
+	// "rating>4.2 AND genres~fantasy", matched against each book the
+	// other fields let through.
+	Filter book.Filter
+
+	// Limit caps the results, which come highest rated first. With a
+	// Filter set it counts matching books only.
+	Limit int
+}
+
+func (q Query) sql() (string, []interface{}) {
+	where, args := []string{}, []interface{}{}
+
+	if q.Author != "" {
+		where = append(where, "id IN (SELECT book_id FROM book_authors WHERE name = ? OR author_id = ?)")
+		args = append(args, q.Author, q.Author)
+	}
+
+	if q.Genre != "" {
+		where = append(where, "id IN (SELECT book_id FROM book_genres WHERE genre = ?)")
+		args = append(args, string(q.Genre))
+	}
+
+	if q.MinRating > 0 {
+		where = append(where, "rating >= ?")
+		args = append(args, q.MinRating)
+	}
+
+	if q.MaxRating > 0 {
+		where = append(where, "rating <= ?")
+		args = append(args, q.MaxRating)
+	}
+
+	if q.MinRatings > 0 {
+		where = append(where, "ratings_count >= ?")
+		args = append(args, q.MinRatings)
+	}
+
+	stmt := "SELECT data FROM books"
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	stmt += " ORDER BY rating DESC, ratings_count DESC, id"
+
+	if q.Limit > 0 && q.Filter == nil {
+		stmt += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+
+	return stmt, args
+}
+
+func (s *Store) Find(ctx context.Context, q Query) ([]book.Book, error) {
+	stmt, args := q.sql()
+
+	rows, err := s.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	books := []book.Book{}
+
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var b book.Book
+		if err := json.Unmarshal([]byte(data), &b); err != nil {
+			return nil, err
+		}
+
+		if q.Filter != nil && !q.Filter.Match(b) {
+			continue
+		}
+
+		books = append(books, b)
+
+		if q.Limit > 0 && len(books) == q.Limit {
+			break
+		}
+	}
+
+	return books, rows.Err()
+}
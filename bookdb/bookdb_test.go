@@ -0,0 +1,70 @@
+package bookdb_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/bookdb"
+)
+
+func TestGetAfterUpsert(t *testing.T) {
+	want := book.Book{
+		Title:    "Good Omens",
+		URL:      "https://www.goodreads.com/book/show/12067",
+		ID:       "12067",
+		CoverUrl: "https://images.gr-assets.com/books/12067.jpg",
+		Authors: []book.AuthorRef{
+			book.NewAuthorRef("Terry Pratchett", "1654"),
+			book.NewAuthorRef("Neil Gaiman", "1221698"),
+		},
+		Genres:  []book.Genre{"fantasy", "humor"},
+		Rating:  4.25,
+		Ratings: 712345,
+		Reviews: 23456,
+		ISBN:    "9780060853983",
+		Details: &book.BookDetails{
+			Pages:            432,
+			Format:           "Paperback",
+			Publisher:        "William Morrow",
+			Published:        "2006-11-28",
+			FirstPublished:   "1990-05-01",
+			Language:         "English",
+			ISBN10:           "0060853980",
+			ISBN13:           "9780060853983",
+			RatingsHistogram: []int{5000, 12000, 90000, 250000, 355345},
+		},
+	}
+
+	ctx := context.Background()
+
+	store, err := bookdb.Open(filepath.Join(t.TempDir(), "books.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.Upsert(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Get(ctx, want.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("Get = %+v\nwant %+v", *got, want)
+	}
+
+	found, err := store.Find(ctx, bookdb.Query{Author: "1221698"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(found) != 1 || !reflect.DeepEqual(found[0], want) {
+		t.Errorf("Find by author ID = %+v, want [%+v]", found, want)
+	}
+}
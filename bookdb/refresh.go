@@ -0,0 +1,47 @@
+package bookdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/dchooyc/book"
+)
+
+// Store is the corpus for a book.Refresher, with each book's updated_at
+// as the time it was last fetched.
+var _ book.RefreshStore = (*Store)(nil)
+
+// RefreshItems lists every stored book that has a URL to fetch it from.
+func (s *Store) RefreshItems() ([]book.RefreshItem, error) {
+	ctx := context.Background()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT url, ratings_count, updated_at FROM books WHERE url != '' ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []book.RefreshItem{}
+
+	for rows.Next() {
+		var item book.RefreshItem
+		var updated string
+
+		if err := rows.Scan(&item.URL, &item.Ratings, &updated); err != nil {
+			return nil, err
+		}
+
+		// A timestamp that doesn't parse leaves LastFetched zero, which
+		// makes the book due straight away.
+		item.LastFetched, _ = time.Parse(time.RFC3339, updated)
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// SaveRefreshed upserts b with fetched as its updated_at.
+func (s *Store) SaveRefreshed(b *book.Book, fetched time.Time) error {
+	return s.upsertAt(context.Background(), fetched, *b)
+}
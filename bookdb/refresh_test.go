@@ -0,0 +1,71 @@
+package bookdb_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/bookdb"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestStoreAsRefreshCorpus(t *testing.T) {
+	dune := book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225", Rating: 4.27}
+	emma := book.Book{Title: "Emma", ID: "6969", URL: "https://www.goodreads.com/book/show/6969", Rating: 4.02}
+
+	fake := testutil.NewFakeGoodreads(dune, emma)
+	defer fake.Close()
+
+	store, err := bookdb.Open(filepath.Join(t.TempDir(), "books.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	stale := dune
+	stale.URL, stale.Rating = fake.BookURL(dune), 4.1
+
+	fresh := emma
+	fresh.URL = fake.BookURL(emma)
+
+	if err := store.SaveRefreshed(&stale, time.Now().Add(-60*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Upsert(context.Background(), fresh); err != nil {
+		t.Fatal(err)
+	}
+
+	client := book.NewClient(book.WithBaseURL(fake.URL))
+
+	report, err := book.NewRefresher(client, store).RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Planned != 1 || report.Refreshed != 1 {
+		t.Errorf("planned %d, refreshed %d; want only the stale book", report.Planned, report.Refreshed)
+	}
+
+	got, err := store.Get(context.Background(), dune.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Rating != dune.Rating {
+		t.Errorf("stored rating = %v, want refreshed %v", got.Rating, dune.Rating)
+	}
+
+	items, err := store.RefreshItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range items {
+		if time.Since(item.LastFetched) > time.Minute {
+			t.Errorf("%s last fetched %v, want just now", item.URL, item.LastFetched)
+		}
+	}
+}
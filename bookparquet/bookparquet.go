@@ -0,0 +1,253 @@
+// Package bookparquet writes books as Parquet files with typed columns,
+// so a scraped dataset loads straight into DuckDB, Spark or pandas.
+package bookparquet
+
+import (
+	"io"
+	"time"
+
+	"github.com/dchooyc/book"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// Row is the Parquet schema, one row per book. Authors and genres are
+// LIST columns, with AuthorIDs parallel to Authors; optional columns are
+// null rather than empty when the page didn't have the value. The detail
+// columns hold the book's details as they are, so ISBN10 and ISBN13 are
+// null for a book whose only ISBN is the top-level ISBN column.
+type Row struct {
+	ID        string   `parquet:"id"`
+	URL       string   `parquet:"url"`
+	Title     string   `parquet:"title"`
+	Authors   []string `parquet:"authors,list"`
+	AuthorIDs []string `parquet:"author_ids,list"`
+	Genres    []string `parquet:"genres,list"`
+	Rating    float64  `parquet:"rating"`
+	Ratings   int64    `parquet:"ratings"`
+	Reviews   int64    `parquet:"reviews"`
+	CoverURL  string   `parquet:"cover_url,optional"`
+	ISBN      string   `parquet:"isbn,optional"`
+
+	// HasDetails tells a book without details from one whose details are
+	// all empty.
+	HasDetails    bool   `parquet:"has_details"`
+	TitleComplete string `parquet:"title_complete,optional"`
+	OriginalTitle string `parquet:"original_title,optional"`
+	Description   string `parquet:"description,optional"`
+	ISBN10        string `parquet:"isbn10,optional"`
+	ISBN13        string `parquet:"isbn13,optional"`
+	ASIN          string `parquet:"asin,optional"`
+	Pages         int32  `parquet:"pages,optional"`
+	Format        string `parquet:"format,optional"`
+	Publisher     string `parquet:"publisher,optional"`
+	Language      string `parquet:"language,optional"`
+
+	// Published and FirstPublished are DATE columns, days since the Unix
+	// epoch. Zero is written as null. PublishedText and
+	// FirstPublishedText keep the dates as the page gave them, which Book
+	// reads back.
+	Published          int32  `parquet:"published,optional,date"`
+	FirstPublished     int32  `parquet:"first_published,optional,date"`
+	PublishedText      string `parquet:"published_text,optional"`
+	FirstPublishedText string `parquet:"first_published_text,optional"`
+
+	// RatingsHistogram counts one- to five-star ratings, in that order.
+	RatingsHistogram []int64 `parquet:"ratings_histogram,list"`
+}
+
+// FromBook flattens b into a Row. Publication dates known only to the
+// month or year are stored as the first day of that period. The details'
+// reviews are not stored.
+func FromBook(b book.Book) Row {
+	r := Row{
+		ID:       b.ID,
+		URL:      b.URL,
+		Title:    b.Title,
+		Authors:  b.AuthorNames(),
+		Genres:   b.GenreSlugs(),
+		Rating:   b.Rating,
+		Ratings:  int64(b.Ratings),
+		Reviews:  int64(b.Reviews),
+		CoverURL: b.CoverUrl,
+		ISBN:     b.ISBN,
+	}
+
+	for _, author := range b.Authors {
+		r.AuthorIDs = append(r.AuthorIDs, author.ID())
+	}
+
+	d := b.Details
+	if d == nil {
+		return r
+	}
+
+	r.HasDetails = true
+	r.TitleComplete, r.OriginalTitle, r.Description = d.TitleComplete, d.OriginalTitle, d.Description
+	r.ISBN10, r.ISBN13, r.ASIN = d.ISBN10, d.ISBN13, d.ASIN
+	r.Pages = int32(d.Pages)
+	r.Format, r.Publisher, r.Language = d.Format, d.Publisher, d.Language
+	r.PublishedText, r.FirstPublishedText = d.Published, d.FirstPublished
+
+	if date, err := d.PublishedDate(); err == nil {
+		r.Published = epochDays(date.Time)
+	}
+
+	if date, err := d.FirstPublishedDate(); err == nil {
+		r.FirstPublished = epochDays(date.Time)
+	}
+
+	for _, n := range d.RatingsHistogram {
+		r.RatingsHistogram = append(r.RatingsHistogram, int64(n))
+	}
+
+	return r
+}
+
+// Book turns r back into a book.
+func (r Row) Book() book.Book {
+	b := book.Book{
+		Title:    r.Title,
+		URL:      r.URL,
+		ID:       r.ID,
+		CoverUrl: r.CoverURL,
+		Rating:   r.Rating,
+		Ratings:  int(r.Ratings),
+		Reviews:  int(r.Reviews),
+		ISBN:     r.ISBN,
+	}
+
+	for i, name := range r.Authors {
+		var id string
+		if i < len(r.AuthorIDs) {
+			id = r.AuthorIDs[i]
+		}
+
+		b.Authors = append(b.Authors, book.NewAuthorRef(name, id))
+	}
+
+	for _, slug := range r.Genres {
+		b.Genres = append(b.Genres, book.Genre(slug))
+	}
+
+	if !r.HasDetails {
+		return b
+	}
+
+	b.Details = &book.BookDetails{
+		TitleComplete:  r.TitleComplete,
+		OriginalTitle:  r.OriginalTitle,
+		Description:    r.Description,
+		Pages:          int(r.Pages),
+		Format:         r.Format,
+		Publisher:      r.Publisher,
+		Published:      r.PublishedText,
+		FirstPublished: r.FirstPublishedText,
+		Language:       r.Language,
+		ISBN10:         r.ISBN10,
+		ISBN13:         r.ISBN13,
+		ASIN:           r.ASIN,
+	}
+
+	for _, n := range r.RatingsHistogram {
+		b.Details.RatingsHistogram = append(b.Details.RatingsHistogram, int(n))
+	}
+
+	return b
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+func epochDays(t time.Time) int32 {
+	// ParseDate returns midnight UTC, so this divides exactly.
+	return int32(t.Unix() / secondsPerDay)
+}
+
+type Writer struct {
+	w *parquet.GenericWriter[Row]
+}
+
+type Option func(*config)
+
+type config struct {
+	codec        compress.Codec
+	rowGroupSize int64
+}
+
+// WithCompression sets the column codec, such as &parquet.Zstd. The
+// default is Snappy, which every Parquet reader supports.
+func WithCompression(codec compress.Codec) Option {
+	return func(c *config) {
+		c.codec = codec
+	}
+}
+
+// WithRowGroupSize caps how many rows go in each row group. Readers
+// parallelise over row groups, so large datasets scan faster with more
+// of them.
+func WithRowGroupSize(rows int64) Option {
+	return func(c *config) {
+		c.rowGroupSize = rows
+	}
+}
+
+func NewWriter(w io.Writer, opts ...Option) *Writer {
+	cfg := config{codec: &parquet.Snappy}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	options := []parquet.WriterOption{parquet.Compression(cfg.codec)}
+	if cfg.rowGroupSize > 0 {
+		options = append(options, parquet.MaxRowsPerRowGroup(cfg.rowGroupSize))
+	}
+
+	return &Writer{w: parquet.NewGenericWriter[Row](w, options...)}
+}
+
+func (w *Writer) Write(books ...book.Book) error {
+	rows := make([]Row, len(books))
+	for i, b := range books {
+		rows[i] = FromBook(b)
+	}
+
+	_, err := w.w.Write(rows)
+
+	return err
+}
+
+// Close flushes the last row group and writes the file footer. The file
+// is unreadable until it has been called.
+func (w *Writer) Close() error {
+	return w.w.Close()
+}
+
+// Write writes books to w as a complete Parquet file.
+func Write(w io.Writer, books []book.Book, opts ...Option) error {
+	pw := NewWriter(w, opts...)
+	if err := pw.Write(books...); err != nil {
+		return err
+	}
+
+	return pw.Close()
+}
+
+// Read reads the rows of a file written by Write.
+func Read(r io.ReaderAt, size int64) ([]Row, error) {
+	return parquet.Read[Row](r, size)
+}
+
+// ReadBooks reads the books in a file written by Write.
+func ReadBooks(r io.ReaderAt, size int64) ([]book.Book, error) {
+	rows, err := Read(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make([]book.Book, len(rows))
+	for i, row := range rows {
+		books[i] = row.Book()
+	}
+
+	return books, nil
+}
@@ -0,0 +1,58 @@
+package bookparquet_test
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/bookparquet"
+	"github.com/dchooyc/book/testutil"
+)
+
+type randomBooks []book.Book
+
+func (randomBooks) Generate(r *rand.Rand, size int) reflect.Value {
+	books := make(randomBooks, r.Intn(size+1))
+	for i := range books {
+		books[i] = testutil.RandomBook(r)
+	}
+
+	return reflect.ValueOf(books)
+}
+
+func TestRoundTrip(t *testing.T) {
+	property := func(books randomBooks) bool {
+		var buf bytes.Buffer
+		if err := bookparquet.Write(&buf, books); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		got, err := bookparquet.ReadBooks(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if len(got) != len(books) {
+			t.Logf("read %d books, wrote %d", len(got), len(books))
+			return false
+		}
+
+		for i := range got {
+			if !reflect.DeepEqual(got[i], books[i]) {
+				t.Logf("book %d: got %+v\nwant %+v", i, got[i], books[i])
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,131 @@
+package book
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	BookRefRowType         = "http://schema.org/Book"
+	BookRefTitleIndicator  = "bookTitle"
+	BookRefAuthorIndicator = "authorName"
+	BookRefCoverIndicator  = "bookCover"
+	NextPageIndicator      = "next_page"
+)
+
+var (
+	avgRatingPattern = regexp.MustCompile(`([\d.,]+)\s+avg rating`)
+	ratingsPattern   = regexp.MustCompile(`(?i)([\d,.]+)\s+ratings?\b`)
+	publishedPattern = regexp.MustCompile(`(?i)published\s+(\d{4})`)
+)
+
+type BookRef struct {
+	Title    string   `json:"title" yaml:"title" xml:"title"`
+	URL      string   `json:"url" yaml:"url" xml:"url"`
+	ID       string   `json:"id" yaml:"id" xml:"id"`
+	CoverUrl string   `json:"cover_url,omitempty" yaml:"cover_url,omitempty" xml:"cover_url,omitempty"`
+	Authors  []string `json:"authors,omitempty" yaml:"authors,omitempty" xml:"authors>author,omitempty"`
+	Rating   float64  `json:"rating,omitempty" yaml:"rating,omitempty" xml:"rating,omitempty"`
+	Ratings  int      `json:"ratings,omitempty" yaml:"ratings,omitempty" xml:"ratings,omitempty"`
+	Year     int      `json:"year,omitempty" yaml:"year,omitempty" xml:"year,omitempty"`
+}
+
+func extractBookRefRows(doc *html.Node) []BookRef {
+	refs := []BookRef{}
+
+	for _, row := range findAll(doc, byAttr("tr", "itemtype", BookRefRowType)) {
+		ref, ok := extractBookRef(row)
+		if ok {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+func extractBookRef(n *html.Node) (BookRef, bool) {
+	ref := BookRef{}
+
+	titleLink := findFirst(n, byClass("a", BookRefTitleIndicator))
+	if titleLink == nil {
+		return ref, false
+	}
+
+	ref.Title = textContent(titleLink)
+	ref.URL = getAttr(titleLink, "href")
+	ref.ID = bookIDFromURL(ref.URL)
+
+	if cover := findFirst(n, byClass("img", BookRefCoverIndicator)); cover != nil {
+		ref.CoverUrl = getAttr(cover, "src")
+	}
+
+	for _, a := range findAll(n, byClass("a", BookRefAuthorIndicator)) {
+		ref.Authors = append(ref.Authors, textContent(a))
+	}
+
+	text := textContent(n)
+	ref.Rating = matchFloat(avgRatingPattern, text)
+	ref.Ratings = matchCount(ratingsPattern, text)
+
+	if m := publishedPattern.FindStringSubmatch(text); m != nil {
+		ref.Year, _ = strconv.Atoi(m[1])
+	}
+
+	return ref, true
+}
+
+func extractNextPage(doc *html.Node) string {
+	next := findFirst(doc, func(n *html.Node) bool {
+		return isElement(n, "a") && (hasClass(n, NextPageIndicator) || getAttr(n, "rel") == "next")
+	})
+	if next == nil {
+		return ""
+	}
+
+	return getAttr(next, "href")
+}
+
+func bookIDFromURL(url string) string {
+	if !strings.Contains(url, BookURLIndicator) {
+		return ""
+	}
+
+	return leadingDigits(lastPathSegment(url))
+}
+
+func matchFloat(pattern *regexp.Regexp, text string) float64 {
+	m := pattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+
+	val, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+
+	return val
+}
+
+func matchCount(pattern *regexp.Regexp, text string) int {
+	m := pattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+
+	return parseCount(m[1])
+}
+
+func (ref BookRef) Book() Book {
+	return Book{
+		Title:    ref.Title,
+		URL:      ref.URL,
+		CoverUrl: ref.CoverUrl,
+		Authors:  AuthorRefs(ref.Authors...),
+		Rating:   ref.Rating,
+		Ratings:  ref.Ratings,
+	}
+}
@@ -0,0 +1,205 @@
+package book
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache stores raw response bodies keyed by absolute URL. Client checks
+// the stored time against its TTL, so implementations only need to keep
+// what they are given.
+type Cache interface {
+	Get(url string) (CacheEntry, bool)
+	Set(url string, entry CacheEntry) error
+}
+
+// CacheEntry is a cached page. ETag and LastModified are the response's
+// validators; once the entry expires they let the client revalidate it
+// with a conditional GET instead of downloading it again.
+type CacheEntry struct {
+	Body         []byte    `json:"-"`
+	Stored       time.Time `json:"-"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+}
+
+// WithCache sets where the client caches pages. Combined with
+// WithCacheTTL entries expire; without it they are kept until the cache
+// itself drops them.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+const diskCacheMetaSuffix = ".meta"
+
+type bypassCacheKey struct{}
+
+// BypassCache returns a context under which Client skips cache lookups and
+// always fetches, while still storing the fresh response.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+type MemoryCache struct {
+	max int
+
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+}
+
+// NewMemoryCache keeps up to maxEntries pages in memory, evicting the
+// oldest when full. A maxEntries of zero or less means no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		max:     maxEntries,
+		entries: map[string]CacheEntry{},
+	}
+}
+
+func (m *MemoryCache) Get(url string) (CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[url]
+
+	return entry, ok
+}
+
+func (m *MemoryCache) Set(url string, entry CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries[url]; !ok && m.max > 0 && len(m.entries) >= m.max {
+		oldest, oldestTime := "", entry.Stored
+
+		for key, e := range m.entries {
+			if e.Stored.Before(oldestTime) {
+				oldest, oldestTime = key, e.Stored
+			}
+		}
+
+		delete(m.entries, oldest)
+	}
+
+	m.entries[url] = entry
+
+	return nil
+}
+
+// DiskCache keeps one file per URL under a directory, named by the URL's
+// SHA-256 and fanned out over 256 subdirectories. The file's modification
+// time is the stored time, so entries survive restarts. Validators live in
+// a .meta sidecar next to the body.
+type DiskCache struct {
+	dir string
+}
+
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &DiskCache{dir: dir}, nil
+}
+
+func (d *DiskCache) Get(url string) (CacheEntry, bool) {
+	path := d.path(url)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return CacheEntry{}, false
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return CacheEntry{}, false
+	}
+
+	entry := CacheEntry{}
+	if meta, err := os.ReadFile(path + diskCacheMetaSuffix); err == nil {
+		json.Unmarshal(meta, &entry)
+	}
+
+	entry.Body = body
+	entry.Stored = info.ModTime()
+
+	return entry, true
+}
+
+func (d *DiskCache) Set(url string, entry CacheEntry) error {
+	path := d.path(url)
+
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(path+diskCacheMetaSuffix, meta, entry.Stored); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, entry.Body, entry.Stored)
+}
+
+func writeFileAtomic(path string, data []byte, mtime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chtimes(tmp.Name(), mtime, mtime)
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+
+	return nil
+}
+
+func (d *DiskCache) Delete(url string) error {
+	path := d.path(url)
+
+	for _, p := range []string{path, path + diskCacheMetaSuffix} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DiskCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	name := hex.EncodeToString(sum[:])
+
+	return filepath.Join(d.dir, name[:2], name)
+}
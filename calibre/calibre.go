@@ -0,0 +1,334 @@
+// Package calibre writes books as the metadata.opf files Calibre keeps
+// beside each book in its library, so a scraped collection can seed one.
+package calibre
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dchooyc/book"
+)
+
+const (
+	opfNamespace = "http://www.idpf.org/2007/opf"
+	dcNamespace  = "http://purl.org/dc/elements/1.1/"
+
+	// MetadataFile and CoverFile are the names Calibre gives the files in
+	// each book's folder.
+	MetadataFile = "metadata.opf"
+	CoverFile    = "cover.jpg"
+
+	// maxNameLen keeps folder names well inside filesystem limits; Calibre
+	// truncates its own similarly.
+	maxNameLen = 100
+)
+
+type Identifier struct {
+	ID     string `xml:"id,attr,omitempty"`
+	Scheme string `xml:"opf:scheme,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type Creator struct {
+	Role   string `xml:"opf:role,attr"`
+	FileAs string `xml:"opf:file-as,attr,omitempty"`
+	Name   string `xml:",chardata"`
+}
+
+type Meta struct {
+	Name    string `xml:"name,attr"`
+	Content string `xml:"content,attr"`
+}
+
+type Metadata struct {
+	XmlnsDC     string       `xml:"xmlns:dc,attr"`
+	XmlnsOPF    string       `xml:"xmlns:opf,attr"`
+	Identifiers []Identifier `xml:"dc:identifier"`
+	Title       string       `xml:"dc:title"`
+	Creators    []Creator    `xml:"dc:creator"`
+	Date        string       `xml:"dc:date,omitempty"`
+	Description string       `xml:"dc:description,omitempty"`
+	Publisher   string       `xml:"dc:publisher,omitempty"`
+	Language    string       `xml:"dc:language,omitempty"`
+	Subjects    []string     `xml:"dc:subject"`
+	Meta        []Meta       `xml:"meta"`
+}
+
+type Reference struct {
+	Type  string `xml:"type,attr"`
+	Title string `xml:"title,attr"`
+	Href  string `xml:"href,attr"`
+}
+
+type Guide struct {
+	References []Reference `xml:"reference"`
+}
+
+// Package is an OPF 2.0 package document as Calibre writes it, without a
+// manifest or spine since there is no book file to describe.
+type Package struct {
+	XMLName          xml.Name `xml:"package"`
+	Xmlns            string   `xml:"xmlns,attr"`
+	UniqueIdentifier string   `xml:"unique-identifier,attr"`
+	Version          string   `xml:"version,attr"`
+	Metadata         Metadata `xml:"metadata"`
+	Guide            *Guide   `xml:"guide,omitempty"`
+}
+
+// FromBook maps b onto a package. Authors become creators filed under
+// their sort names, genres become tags, and the Goodreads ID and ISBN
+// become identifiers Calibre's metadata download can match on. The UUID
+// is derived from the Goodreads ID, so regenerating a book's OPF doesn't
+// make Calibre treat it as a new one.
+func FromBook(b book.Book) *Package {
+	p := &Package{
+		Xmlns:            opfNamespace,
+		UniqueIdentifier: "uuid_id",
+		Version:          "2.0",
+		Metadata: Metadata{
+			XmlnsDC:  dcNamespace,
+			XmlnsOPF: opfNamespace,
+			Identifiers: []Identifier{
+				{ID: "uuid_id", Scheme: "uuid", Value: uuid(b)},
+			},
+			Title: b.Title,
+			Meta:  []Meta{{Name: "calibre:title_sort", Content: TitleSort(b.Title)}},
+		},
+	}
+
+	m := &p.Metadata
+
+	for _, author := range b.Authors {
+		m.Creators = append(m.Creators, Creator{Role: "aut", FileAs: author.SortName(), Name: author.Name()})
+	}
+
+	for _, genre := range b.Genres {
+		m.Subjects = append(m.Subjects, genre.Name())
+	}
+
+	isbn := b.ISBN
+
+	if d := b.Details; d != nil {
+		m.Description, m.Publisher, m.Language = d.Description, d.Publisher, d.Language
+
+		if d.ISBN13 != "" {
+			isbn = d.ISBN13
+		} else if d.ISBN10 != "" {
+			isbn = d.ISBN10
+		}
+
+		if date, err := d.PublishedDate(); err == nil {
+			m.Date = date.Time.Format("2006-01-02T15:04:05+00:00")
+		}
+	}
+
+	if isbn != "" {
+		m.Identifiers = append(m.Identifiers, Identifier{Scheme: "ISBN", Value: isbn})
+	}
+
+	if b.ID != "" {
+		m.Identifiers = append(m.Identifiers, Identifier{Scheme: "GOODREADS", Value: b.ID})
+	}
+
+	return p
+}
+
+// SetCover points the package at a cover image, relative to the OPF file.
+func (p *Package) SetCover(href string) {
+	p.Guide = &Guide{References: []Reference{{Type: "cover", Title: "Cover", Href: href}}}
+}
+
+func (p *Package) Encode(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "    ")
+
+	if err := enc.Encode(p); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// Encode writes b's metadata.opf to w.
+func Encode(w io.Writer, b book.Book) error {
+	return FromBook(b).Encode(w)
+}
+
+// TitleSort moves a leading article to the end, as Calibre's title sort
+// does: "The Hobbit" sorts as "Hobbit, The".
+func TitleSort(title string) string {
+	for _, article := range []string{"The ", "An ", "A "} {
+		if rest, ok := strings.CutPrefix(title, article); ok && rest != "" {
+			return rest + ", " + strings.TrimSpace(article)
+		}
+	}
+
+	return title
+}
+
+// uuid is a name-based (version 5 style) UUID over the book's Goodreads
+// ID, falling back to its URL and then its title.
+func uuid(b book.Book) string {
+	name := b.ID
+	if name == "" {
+		name = b.URL
+	}
+
+	if name == "" {
+		name = b.Title
+	}
+
+	sum := sha1.Sum([]byte("goodreads:" + name))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// Dir is where a book goes in a Calibre-style library, relative to its
+// root: "<first author>/<title> (<Goodreads ID>)".
+func Dir(b book.Book) string {
+	author := "Unknown"
+	if len(b.Authors) > 0 {
+		author = b.Authors[0].Name()
+	}
+
+	title := b.Title
+	if b.ID != "" {
+		title = fmt.Sprintf("%s (%s)", truncate(safeName(title), maxNameLen-len(b.ID)-3), b.ID)
+	}
+
+	return filepath.Join(safeName(author), safeName(title))
+}
+
+// safeName replaces the characters Windows and macOS refuse in file names,
+// as Calibre does, so a library written on Linux can be copied anywhere.
+func safeName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return '_'
+		}
+
+		return r
+	}, s)
+
+	s = strings.Trim(truncate(s, maxNameLen), " .")
+	if s == "" {
+		return "Unknown"
+	}
+
+	return s
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	return string([]rune(s)[:max])
+}
+
+type Option func(*config)
+
+type config struct {
+	client *book.Client
+	covers bool
+}
+
+// WithCovers downloads each book's cover into its folder as cover.jpg,
+// skipping covers already there.
+func WithCovers() Option {
+	return func(c *config) {
+		c.covers = true
+	}
+}
+
+// WithClient sets the client covers are downloaded through. It defaults
+// to book.DefaultClient.
+func WithClient(client *book.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WriteLibrary writes a metadata.opf for each book under dir, in the
+// folder Dir gives it. A failed cover download doesn't stop the export;
+// the book is written without a cover and the errors are returned joined
+// together at the end.
+func WriteLibrary(ctx context.Context, dir string, books []book.Book, opts ...Option) error {
+	cfg := config{client: book.DefaultClient}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	errs := []error{}
+
+	for _, b := range books {
+		bookDir := filepath.Join(dir, Dir(b))
+		if err := os.MkdirAll(bookDir, 0o755); err != nil {
+			return err
+		}
+
+		p := FromBook(b)
+
+		if cfg.covers && b.CoverUrl != "" {
+			err := downloadCover(ctx, cfg.client, b.CoverUrl, filepath.Join(bookDir, CoverFile))
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: cover: %w", b.Title, err))
+			} else {
+				p.SetCover(CoverFile)
+			}
+		}
+
+		if err := writeOPF(filepath.Join(bookDir, MetadataFile), p); err != nil {
+			return err
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func downloadCover(ctx context.Context, client *book.Client, url, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return nil
+	}
+
+	body, err := client.Get(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dest, body, 0o644)
+}
+
+func writeOPF(path string, p *Package) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Encode(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
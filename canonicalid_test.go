@@ -0,0 +1,27 @@
+package book_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestBookIDIsNumericInEveryLayout(t *testing.T) {
+	pages := map[string]string{
+		"modern": string(testutil.RenderFixture(book.Book{Title: "Dune", ID: "3634639-dune"})),
+		"legacy": `<html><body><h1 id="bookTitle">Dune</h1>
+<a href="https://www.goodreads.com/work/editions/3634639-dune">All editions</a></body></html>`,
+		"mobile": `<html><body><div class="mobileBookPage"><h1 class="bookTitle">Dune</h1>
+<a href="/work/quotes/3634639-dune">Quotes</a></div></body></html>`,
+	}
+
+	for layout, page := range pages {
+		// The stub pages leave most fields out, so only the ID is checked.
+		b, _ := book.GetBook(strings.NewReader(page))
+		if b.ID != "3634639" {
+			t.Errorf("%s: ID = %q, want %q", layout, b.ID, "3634639")
+		}
+	}
+}
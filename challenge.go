@@ -0,0 +1,104 @@
+package book
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrBlocked   = errors.New("blocked by goodreads")
+	ErrChallenge = errors.New("goodreads served a challenge page")
+)
+
+// challengeMarkers are fragments only found in captcha and bot-check
+// pages, mostly the scripts those pages load, so they are safe to look
+// for anywhere in the body.
+var challengeMarkers = [][]byte{
+	[]byte("cf_chl_"),
+	[]byte("/cdn-cgi/challenge-platform/"),
+	[]byte("g-recaptcha"),
+	[]byte("h-captcha"),
+	[]byte("awswafintegration"),
+	[]byte("captcha-delivery.com"),
+}
+
+// challengeTitles and blockedTitles are matched against the page title
+// only, since a review or description could contain the same words.
+var challengeTitles = [][]byte{
+	[]byte("just a moment"),
+	[]byte("attention required"),
+	[]byte("captcha"),
+	[]byte("verify you are human"),
+	[]byte("are you a robot"),
+}
+
+var blockedTitles = [][]byte{
+	[]byte("access denied"),
+	[]byte("request blocked"),
+	[]byte("you have been blocked"),
+	[]byte("too many requests"),
+	[]byte("rate limit"),
+	[]byte("unusual traffic"),
+}
+
+// BlockedError reports a page Goodreads, or a CDN in front of it, served
+// instead of the one asked for. It matches ErrBlocked with errors.Is, and
+// ErrChallenge too when the page wants a captcha or browser check solved,
+// which retrying on its own won't get past.
+type BlockedError struct {
+	URL        string
+	StatusCode int
+	Reason     string
+	Challenge  bool
+}
+
+func (e *BlockedError) Error() string {
+	if e.URL == "" {
+		return e.Reason
+	}
+
+	return fmt.Sprintf("fetching %s: %s", e.URL, e.Reason)
+}
+
+func (e *BlockedError) Is(target error) bool {
+	return target == ErrBlocked || (target == ErrChallenge && e.Challenge)
+}
+
+// DetectBlock reports whether body is a captcha, bot check or block page
+// rather than real content. header may be nil and statusCode zero when
+// only the body is at hand.
+func DetectBlock(statusCode int, header http.Header, body []byte) (*BlockedError, bool) {
+	if strings.EqualFold(header.Get("Cf-Mitigated"), "challenge") {
+		return &BlockedError{StatusCode: statusCode, Reason: "cloudflare challenge", Challenge: true}, true
+	}
+
+	lower := bytes.ToLower(body)
+	title := pageTitle(lower)
+
+	for _, marker := range challengeMarkers {
+		if bytes.Contains(lower, marker) {
+			return &BlockedError{StatusCode: statusCode, Reason: "challenge page", Challenge: true}, true
+		}
+	}
+
+	for _, marker := range challengeTitles {
+		if bytes.Contains(title, marker) {
+			return &BlockedError{StatusCode: statusCode, Reason: "challenge page", Challenge: true}, true
+		}
+	}
+
+	for _, marker := range blockedTitles {
+		if bytes.Contains(title, marker) {
+			return &BlockedError{StatusCode: statusCode, Reason: "block page"}, true
+		}
+	}
+
+	if statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests {
+		return &BlockedError{StatusCode: statusCode, Reason: http.StatusText(statusCode)}, true
+	}
+
+	return nil, false
+}
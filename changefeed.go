@@ -0,0 +1,221 @@
+package book
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+type ChangeOp string
+
+const (
+	ChangeCreate ChangeOp = "create"
+	ChangeUpdate ChangeOp = "update"
+	ChangeDelete ChangeOp = "delete"
+)
+
+// ChangeEvent is one line of a changefeed. Seq increases by one per event,
+// so a consumer that records the last Seq it applied can replay the feed
+// from any point without applying an event twice. Hash is the content hash
+// of Book after the change and PrevHash the hash it replaced.
+type ChangeEvent struct {
+	Seq      uint64    `json:"seq"`
+	Op       ChangeOp  `json:"op"`
+	Key      string    `json:"key"`
+	Time     time.Time `json:"time"`
+	Hash     string    `json:"hash,omitempty"`
+	PrevHash string    `json:"prev_hash,omitempty"`
+	Book     *Book     `json:"book,omitempty"`
+}
+
+// ChangefeedWriter appends change events as JSONL. It remembers the hash
+// of every key it has seen so that Observe only emits real changes.
+type ChangefeedWriter struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	seq    uint64
+	hashes map[string]string
+	now    func() time.Time
+}
+
+func NewChangefeedWriter(w io.Writer) *ChangefeedWriter {
+	return &ChangefeedWriter{
+		enc:    json.NewEncoder(w),
+		hashes: map[string]string{},
+		now:    time.Now,
+	}
+}
+
+// ResumeChangefeedWriter replays an existing feed from r to restore the
+// sequence number and known hashes, then appends to w.
+func ResumeChangefeedWriter(w io.Writer, r io.Reader) (*ChangefeedWriter, error) {
+	cw := NewChangefeedWriter(w)
+
+	err := ReplayChangefeed(r, 0, func(e ChangeEvent) error {
+		cw.seq = e.Seq
+
+		if e.Op == ChangeDelete {
+			delete(cw.hashes, e.Key)
+		} else {
+			cw.hashes[e.Key] = e.Hash
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cw, nil
+}
+
+// Observe records the current state of b, emitting a create or update
+// event if it differs from what the feed last saw. It reports whether an
+// event was written.
+func (w *ChangefeedWriter) Observe(b Book) (bool, error) {
+	key := bookKey(b)
+	if key == "" {
+		return false, errors.New("changefeed: book has neither id nor url")
+	}
+
+	hash := BookHash(b)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	prev, seen := w.hashes[key]
+	if seen && prev == hash {
+		return false, nil
+	}
+
+	op := ChangeCreate
+	if seen {
+		op = ChangeUpdate
+	}
+
+	if err := w.emit(ChangeEvent{Op: op, Key: key, Hash: hash, PrevHash: prev, Book: &b}); err != nil {
+		return false, err
+	}
+
+	w.hashes[key] = hash
+
+	return true, nil
+}
+
+func (w *ChangefeedWriter) Delete(key string) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	prev, seen := w.hashes[key]
+	if !seen {
+		return false, nil
+	}
+
+	if err := w.emit(ChangeEvent{Op: ChangeDelete, Key: key, PrevHash: prev}); err != nil {
+		return false, err
+	}
+
+	delete(w.hashes, key)
+
+	return true, nil
+}
+
+func (w *ChangefeedWriter) Seq() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.seq
+}
+
+func (w *ChangefeedWriter) emit(e ChangeEvent) error {
+	e.Seq = w.seq + 1
+	e.Time = w.now().UTC()
+
+	if err := w.enc.Encode(e); err != nil {
+		return err
+	}
+
+	w.seq = e.Seq
+
+	return nil
+}
+
+type ChangefeedReader struct {
+	scanner *bufio.Scanner
+}
+
+func NewChangefeedReader(r io.Reader) *ChangefeedReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	return &ChangefeedReader{scanner: scanner}
+}
+
+// Next returns the next event, or io.EOF at the end of the feed.
+func (r *ChangefeedReader) Next() (ChangeEvent, error) {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var e ChangeEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			return e, err
+		}
+
+		return e, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return ChangeEvent{}, err
+	}
+
+	return ChangeEvent{}, io.EOF
+}
+
+// ReplayChangefeed calls fn for every event with a Seq greater than since,
+// in feed order.
+func ReplayChangefeed(r io.Reader, since uint64, fn func(ChangeEvent) error) error {
+	cr := NewChangefeedReader(r)
+
+	for {
+		e, err := cr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if e.Seq <= since {
+			continue
+		}
+
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
+
+// BookHash is a stable content hash of b, used to detect changes.
+func BookHash(b Book) string {
+	data, _ := json.Marshal(b)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func bookKey(b Book) string {
+	if b.ID != "" {
+		return b.ID
+	}
+
+	return b.URL
+}
@@ -0,0 +1,230 @@
+package book
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	defaultCheckpointInterval = time.Minute
+	CheckpointResultsSuffix   = ".results.jsonl"
+)
+
+// CrawlCheckpoint is the persisted state of a crawl. Queue holds the URLs
+// still to visit, including any that were in flight when it was written,
+// and Seen every frontier key ever queued. Fetched books are appended as
+// JSONL to the checkpoint path plus CheckpointResultsSuffix as they
+// arrive, so they survive even between checkpoints; each line is a
+// CrawlRecord.
+type CrawlCheckpoint struct {
+	Saved    time.Time      `json:"saved"`
+	Complete bool           `json:"complete"`
+	Queue    []FrontierItem `json:"queue"`
+	Seen     []string       `json:"seen"`
+}
+
+// WithCheckpoint saves the crawl state to path every interval and when the
+// crawl stops, so Resume can pick it up after an interruption.
+func WithCheckpoint(path string, every time.Duration) CrawlerOption {
+	return func(c *Crawler) {
+		if every <= 0 {
+			every = defaultCheckpointInterval
+		}
+
+		c.checkpoint = &crawlCheckpointer{path: path, every: every}
+	}
+}
+
+// CrawlRecord is one line of a checkpoint's results file. Key is the
+// frontier key of the URL that was fetched, which can differ from the
+// key of the book's canonical URL.
+type CrawlRecord struct {
+	Key  string `json:"key"`
+	Book *Book  `json:"book"`
+}
+
+type crawlCheckpointer struct {
+	path    string
+	every   time.Duration
+	results *os.File
+	err     error
+}
+
+func (cp *crawlCheckpointer) open() {
+	cp.results, cp.err = os.OpenFile(cp.path+CheckpointResultsSuffix, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+}
+
+func (cp *crawlCheckpointer) record(item FrontierItem, result CrawlResult) {
+	if cp.results == nil || result.Err != nil || result.Book == nil {
+		return
+	}
+
+	line, err := json.Marshal(CrawlRecord{Key: item.Key, Book: result.Book})
+	if err == nil {
+		_, err = cp.results.Write(append(line, '\n'))
+	}
+
+	if err != nil && cp.err == nil {
+		cp.err = err
+	}
+}
+
+func (cp *crawlCheckpointer) save(frontier *Frontier, active []FrontierItem, complete bool) {
+	queue, seen := frontier.snapshot()
+
+	state := CrawlCheckpoint{
+		Saved:    time.Now().UTC(),
+		Complete: complete,
+		Queue:    append(active, queue...),
+		Seen:     seen,
+	}
+
+	data, err := json.Marshal(state)
+	if err == nil {
+		err = writeFileAtomic(cp.path, data, state.Saved)
+	}
+
+	if err != nil && cp.err == nil {
+		cp.err = err
+	}
+}
+
+func (cp *crawlCheckpointer) close() {
+	if cp.results != nil {
+		cp.results.Close()
+	}
+}
+
+// Err reports the first error hit while writing checkpoints or results.
+// Checkpointing never stops a crawl, so check it once the results channel
+// is closed.
+func (c *Crawler) Err() error {
+	if c.checkpoint == nil {
+		return nil
+	}
+
+	return c.checkpoint.err
+}
+
+func LoadCrawlCheckpoint(path string) (*CrawlCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &CrawlCheckpoint{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// LoadCrawlResults reads the records a checkpointed crawl has written so
+// far.
+func LoadCrawlResults(path string) ([]CrawlRecord, error) {
+	f, err := os.Open(path + CheckpointResultsSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return []CrawlRecord{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records := []CrawlRecord{}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	for scanner.Scan() {
+		var rec CrawlRecord
+
+		// A crash can leave a torn final line; skip it rather than lose
+		// everything before it.
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Book == nil {
+			continue
+		}
+
+		records = append(records, rec)
+	}
+
+	return records, scanner.Err()
+}
+
+// Resume restarts the crawl checkpointed at path. Queued URLs whose books
+// already made it into the results file are not fetched again. The
+// crawler must have been built WithCheckpoint for the same path to keep
+// checkpointing.
+func (c *Crawler) Resume(ctx context.Context, path string) (<-chan CrawlResult, error) {
+	state, err := LoadCrawlCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := LoadCrawlResults(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := map[string]bool{}
+	for _, rec := range records {
+		fetched[rec.Key] = true
+	}
+
+	queue := []FrontierItem{}
+	for _, item := range state.Queue {
+		if !fetched[item.Key] {
+			queue = append(queue, item)
+		}
+	}
+
+	frontier := c.newFrontier()
+	frontier.restore(queue, state.Seen)
+
+	return c.CrawlFrontier(ctx, frontier), nil
+}
+
+// snapshot returns the queue in the order it would be popped, and the
+// seen keys sorted.
+func (f *Frontier) snapshot() ([]FrontierItem, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	queue := append(frontierHeap{}, f.queue...)
+	sort.Slice(queue, queue.Less)
+
+	seen := make([]string, 0, len(f.seen))
+	for key := range f.seen {
+		seen = append(seen, key)
+	}
+
+	sort.Strings(seen)
+
+	return queue, seen
+}
+
+// restore loads a snapshot. Items are re-sequenced in the order given,
+// which keeps their relative order among equal priorities.
+func (f *Frontier) restore(queue []FrontierItem, seen []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, key := range seen {
+		f.seen[key] = true
+	}
+
+	for _, item := range queue {
+		f.seen[item.Key] = true
+		f.seq++
+		item.seq = f.seq
+		heap.Push(&f.queue, item)
+	}
+}
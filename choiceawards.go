@@ -0,0 +1,132 @@
+package book
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	ChoiceAwardsURLIndicator    = "/choiceawards/"
+	ChoiceCategoryIndicator     = "gcaCategoryTitle"
+	ChoiceWinnerIndicator       = "category__winnerImageContainer"
+	ChoiceWinnerVotesIndicator  = "gcaNumVotes"
+	ChoiceNomineeIndicator      = "pollAnswer"
+	ChoiceNomineeBookIndicator  = "pollAnswer__bookLink"
+	ChoiceNomineeVotesIndicator = "result"
+)
+
+type ChoiceCategory struct {
+	Name     string          `json:"name"`
+	Year     int             `json:"year"`
+	Winner   *ChoiceNominee  `json:"winner,omitempty"`
+	Nominees []ChoiceNominee `json:"nominees"`
+}
+
+type ChoiceNominee struct {
+	BookRef
+	Votes  int  `json:"votes"`
+	Winner bool `json:"winner"`
+}
+
+func GetChoiceAwards(r io.Reader) (*ChoiceCategory, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &ChoiceCategory{
+		Nominees: []ChoiceNominee{},
+	}
+
+	if title := findFirst(doc, byClass("", ChoiceCategoryIndicator)); title != nil {
+		category.Name = textContent(title)
+	}
+
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		if m := yearPattern.FindStringSubmatch(lastPathSegment(getAttr(link, "href"))); m != nil {
+			category.Year, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	if category.Year == 0 {
+		if m := yearPattern.FindStringSubmatch(category.Name); m != nil {
+			category.Year, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	if container := findFirst(doc, byClass("div", ChoiceWinnerIndicator)); container != nil {
+		if ref, ok := extractChoiceBook(container); ok {
+			winner := ChoiceNominee{BookRef: ref, Winner: true}
+
+			if votes := findFirst(doc, byClass("", ChoiceWinnerVotesIndicator)); votes != nil {
+				winner.Votes = parseCount(textContent(votes))
+			}
+
+			category.Winner = &winner
+		}
+	}
+
+	for _, answer := range findAll(doc, byClass("div", ChoiceNomineeIndicator)) {
+		link := findFirst(answer, byClass("div", ChoiceNomineeBookIndicator))
+		if link == nil {
+			continue
+		}
+
+		ref, ok := extractChoiceBook(link)
+		if !ok {
+			continue
+		}
+
+		nominee := ChoiceNominee{BookRef: ref}
+
+		if votes := findFirst(answer, byClass("", ChoiceNomineeVotesIndicator)); votes != nil {
+			nominee.Votes = parseCount(textContent(votes))
+		}
+
+		if category.Winner != nil && category.Winner.URL == ref.URL {
+			nominee.Winner = true
+
+			if category.Winner.Votes == 0 {
+				category.Winner.Votes = nominee.Votes
+			}
+		}
+
+		category.Nominees = append(category.Nominees, nominee)
+	}
+
+	return category, nil
+}
+
+func extractChoiceBook(n *html.Node) (BookRef, bool) {
+	a := findFirst(n, func(n *html.Node) bool {
+		return isElement(n, "a") && strings.Contains(getAttr(n, "href"), BookURLIndicator)
+	})
+	if a == nil {
+		return BookRef{}, false
+	}
+
+	ref := BookRef{
+		URL: getAttr(a, "href"),
+	}
+	ref.ID = bookIDFromURL(ref.URL)
+
+	if img := findFirst(a, func(n *html.Node) bool { return isElement(n, "img") }); img != nil {
+		ref.CoverUrl = getAttr(img, "src")
+
+		title, author, ok := strings.Cut(getAttr(img, "alt"), " by ")
+		ref.Title = title
+
+		if ok {
+			ref.Authors = []string{author}
+		}
+	}
+
+	if ref.Title == "" {
+		ref.Title = textContent(a)
+	}
+
+	return ref, true
+}
@@ -0,0 +1,756 @@
+package book
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	GoodreadsBaseURL = "https://www.goodreads.com"
+	DefaultUserAgent = "github.com/dchooyc/book"
+
+	defaultTimeout         = 30 * time.Second
+	defaultMaxBodySize     = 10 << 20
+	defaultMaxCacheEntries = 1000
+)
+
+// ErrBodyTooLarge is returned for a response body larger than the
+// client accepts, rather than parsing a truncated page.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+var DefaultClient = NewClient(
+	WithMinInterval(2*time.Second),
+	WithCacheTTL(time.Hour),
+)
+
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("fetching %s: unexpected status %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+type Client struct {
+	httpClient     *http.Client
+	baseURL        string
+	userAgent      string
+	limiter        *RateLimiter
+	hostLimits     *hostLimits
+	cacheTTL       time.Duration
+	maxBodySize    int64
+	parseOpts      []ParseOption
+	maxPause       time.Duration
+	onPause        func(PauseEvent)
+	onMetrics      func(RequestMetrics)
+	recorder       Recorder
+	renderer       Renderer
+	renderWhen     func(*Book) bool
+	authorCache    *AuthorCache
+	maxRedirects   int
+	retry          RetryPolicy
+	egress         *egress
+	cache          Cache
+	robots         *robotsCache
+	proxies        *ProxyPool
+	userAgents     []string
+	headers        http.Header
+	jar            http.CookieJar
+	sessionCookies []*http.Cookie
+
+	// err is an option that couldn't be applied, returned by every
+	// request.
+	err error
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+	uaNext      atomic.Uint64
+	fetched     atomic.Int64
+}
+
+type ClientOption func(*Client)
+
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+func WithBaseURL(base string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = base
+	}
+}
+
+// WithMinInterval spaces requests at least d apart. It is shorthand for a
+// rate limiter with a burst of one.
+func WithMinInterval(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d <= 0 {
+			c.limiter = nil
+			return
+		}
+
+		c.limiter = NewRateLimiter(float64(time.Second)/float64(d), 1, 0)
+	}
+}
+
+func WithCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cacheTTL = ttl
+	}
+}
+
+func WithParseOptions(opts ...ParseOption) ClientOption {
+	return func(c *Client) {
+		c.parseOpts = append(c.parseOpts, opts...)
+	}
+}
+
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		baseURL:      GoodreadsBaseURL,
+		userAgent:    DefaultUserAgent,
+		maxBodySize:  defaultMaxBodySize,
+		maxPause:     defaultMaxPause,
+		maxRedirects: defaultMaxRedirects,
+		retry:        DefaultRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.cache == nil && c.cacheTTL > 0 {
+		c.cache = NewMemoryCache(defaultMaxCacheEntries)
+	}
+
+	c.sessionPolicy()
+	c.egressPolicy()
+	c.proxyPolicy()
+	c.redirectPolicy()
+
+	return c
+}
+
+// Err reports an option NewClient couldn't apply, such as WithLocalAddrs
+// along with a custom RoundTripper. Every request of such a client fails
+// with the same error.
+func (c *Client) Err() error {
+	return c.err
+}
+
+// BaseURL is the root relative URLs are resolved against, Goodreads unless
+// set with WithBaseURL.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// FetchBook fetches and parses a book page. Like GetBook, it returns the
+// partly parsed Book along with the error when some fields didn't parse.
+func (c *Client) FetchBook(ctx context.Context, url string) (*Book, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	book, parseErr := GetBook(bytes.NewReader(body), c.parseOpts...)
+	if book == nil {
+		return nil, parseErr
+	}
+
+	if book.URL == "" {
+		book.URL, _ = c.resolve(url)
+	}
+
+	if c.renderer != nil && c.renderWhen(book) {
+		// Keep the plain result if rendering fails, returning the render
+		// error alongside it, or if the rendered page yields nothing better.
+		body, err := c.Render(ctx, url)
+		if err != nil {
+			parseErr = errors.Join(parseErr, err)
+		} else if rendered, err := GetBook(bytes.NewReader(body), c.parseOpts...); rendered != nil && rendered.Title != "" {
+			if rendered.URL == "" {
+				rendered.URL = book.URL
+			}
+
+			book, parseErr = rendered, err
+		}
+	}
+
+	for _, author := range book.Authors {
+		c.rememberAuthor(author.Name(), author.ID())
+	}
+
+	return book, parseErr
+}
+
+func (c *Client) FetchList(ctx context.Context, url string) (*List, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := GetList(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if list.URL == "" {
+		list.URL, _ = c.resolve(url)
+		list.ID = leadingDigits(lastPathSegment(list.URL))
+	}
+
+	return list, nil
+}
+
+func (c *Client) FetchBookURLs(ctx context.Context, url string) ([]string, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetBookURLs(bytes.NewReader(body))
+}
+
+func (c *Client) FetchAuthor(ctx context.Context, url string) (*Author, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := GetAuthor(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	c.rememberAuthor(author.Name, author.ID)
+
+	return author, nil
+}
+
+func (c *Client) FetchAuthorBooks(ctx context.Context, url string) (*AuthorBooks, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := GetAuthorBooks(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	c.rememberAuthor(books.Author, leadingDigits(lastPathSegment(url)))
+
+	return books, nil
+}
+
+func (c *Client) FetchSeries(ctx context.Context, url string) (*Series, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetSeries(bytes.NewReader(body))
+}
+
+func (c *Client) FetchGenre(ctx context.Context, url string) (*GenrePage, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetGenre(bytes.NewReader(body))
+}
+
+func (c *Client) FetchShelf(ctx context.Context, url string) (*Shelf, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetShelf(bytes.NewReader(body))
+}
+
+func (c *Client) FetchUser(ctx context.Context, url string) (*User, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetUser(bytes.NewReader(body))
+}
+
+func (c *Client) FetchQuotes(ctx context.Context, url string) (*QuotesPage, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetQuotes(bytes.NewReader(body))
+}
+
+func (c *Client) FetchEditions(ctx context.Context, url string) (*Editions, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetEditions(bytes.NewReader(body))
+}
+
+func (c *Client) FetchChoiceAwards(ctx context.Context, url string) (*ChoiceCategory, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetChoiceAwards(bytes.NewReader(body))
+}
+
+func (c *Client) FetchNewReleases(ctx context.Context, url string) ([]NewRelease, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetNewReleases(bytes.NewReader(body))
+}
+
+func (c *Client) FetchGroup(ctx context.Context, url string) (*Group, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetGroup(bytes.NewReader(body))
+}
+
+func (c *Client) FetchReview(ctx context.Context, url string) (*Review, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetReview(bytes.NewReader(body), c.parseOpts...)
+}
+
+func (c *Client) FetchGiveaways(ctx context.Context, url string) (*GiveawaysPage, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetGiveaways(bytes.NewReader(body))
+}
+
+func (c *Client) FetchMostRead(ctx context.Context, url string) (*MostReadPage, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetMostRead(bytes.NewReader(body))
+}
+
+func (c *Client) FetchYearInBooks(ctx context.Context, url string) (*YearInBooks, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetYearInBooks(bytes.NewReader(body))
+}
+
+func (c *Client) FetchAuthorQuotes(ctx context.Context, url string) (*AuthorQuotes, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := GetAuthorQuotes(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	c.rememberAuthor(quotes.Author, quotes.AuthorID)
+
+	return quotes, nil
+}
+
+func (c *Client) FetchRecommendations(ctx context.Context, url string) (*Recommendations, error) {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRecommendations(bytes.NewReader(body))
+}
+
+func (c *Client) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	if c.onMetrics == nil {
+		return c.get(ctx, rawURL, &RequestMetrics{})
+	}
+
+	m := &RequestMetrics{URL: rawURL, Cache: CacheMiss}
+	start := time.Now()
+
+	body, err := c.get(ctx, rawURL, m)
+
+	m.Duration = time.Since(start)
+	m.Err = err
+	c.onMetrics(*m)
+
+	return body, err
+}
+
+func (c *Client) get(ctx context.Context, rawURL string, m *RequestMetrics) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	target, err := c.resolve(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	m.URL = target
+
+	entry, fresh, cached := c.cached(target)
+	if fresh && !cacheBypassed(ctx) {
+		m.Cache = CacheHit
+		m.Bytes = len(entry.Body)
+		return entry.Body, nil
+	}
+
+	var validators *CacheEntry
+	if cached {
+		validators = &entry
+	}
+
+	for pauses, attempt := 0, 1; ; {
+		if err := c.checkRobots(ctx, target); err != nil {
+			return nil, err
+		}
+
+		waitStart := time.Now()
+		if err := c.wait(ctx, target); err != nil {
+			return nil, err
+		}
+
+		m.Wait += time.Since(waitStart)
+		m.Attempts++
+
+		fetchStart := time.Now()
+		resp, err := c.fetch(ctx, target, validators)
+		m.Latency += time.Since(fetchStart)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, ErrBodyTooLarge) || !c.retry.Allows(attempt) {
+				return nil, err
+			}
+
+			if err := c.retry.Wait(ctx, attempt, 0); err != nil {
+				return nil, err
+			}
+
+			attempt++
+			continue
+		}
+
+		m.StatusCode = resp.statusCode
+		m.Bytes = len(resp.body)
+
+		if until, reason, ok := c.pauseSignal(resp); ok {
+			if pauses >= maxConsecutivePauses || time.Until(until) > c.maxPause {
+				return nil, &MaintenanceError{URL: target, Until: until, Reason: reason}
+			}
+
+			c.pause(PauseEvent{URL: target, Until: until, Reason: reason, StatusCode: resp.statusCode})
+			pauses++
+			continue
+		}
+
+		block, blocked := DetectBlock(resp.statusCode, resp.header, resp.body)
+		if blocked {
+			block.URL = target
+		}
+
+		if blocked && block.Challenge {
+			return nil, block
+		}
+
+		if RetryableStatus(resp.statusCode) && c.retry.Allows(attempt) {
+			if err := c.retry.Wait(ctx, attempt, RetryAfter(resp.header)); err != nil {
+				return nil, err
+			}
+
+			attempt++
+			continue
+		}
+
+		if blocked {
+			return nil, block
+		}
+
+		if resp.statusCode == http.StatusNotModified && validators != nil {
+			c.store(target, validators.Body, resp.header, validators)
+			m.Cache = CacheRevalidated
+			m.Bytes = len(validators.Body)
+			return validators.Body, nil
+		}
+
+		if resp.statusCode != http.StatusOK {
+			return nil, &StatusError{URL: target, StatusCode: resp.statusCode}
+		}
+
+		// Only the redirect is checked here: a sign-in wall's title could
+		// also be a real book's, which GetBook tells apart.
+		if gate, ok := DetectGate(resp.finalURL, nil); ok {
+			gate.URL = target
+			return nil, gate
+		}
+
+		if c.recorder != nil {
+			if err := c.recorder.Record(target, time.Now(), resp.body); err != nil {
+				return nil, fmt.Errorf("recording %s: %w", target, err)
+			}
+		}
+
+		c.store(target, resp.body, resp.header, nil)
+
+		return resp.body, nil
+	}
+}
+
+type response struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	finalURL   string
+}
+
+func (c *Client) fetch(ctx context.Context, target string, validators *CacheEntry) (*response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	if validators != nil {
+		if validators.ETag != "" {
+			req.Header.Set("If-None-Match", validators.ETag)
+		}
+
+		if validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.LastModified)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	decoded, release, err := decodeBody(resp.Header, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// One byte over the limit is enough to know the body doesn't fit.
+	body, err := io.ReadAll(io.LimitReader(decoded, c.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > c.maxBodySize {
+		return nil, fmt.Errorf("%s: %w: over %d bytes", target, ErrBodyTooLarge, c.maxBodySize)
+	}
+
+	c.fetched.Add(int64(len(body)))
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+
+	return &response{statusCode: resp.StatusCode, header: resp.Header, body: body, finalURL: resp.Request.URL.String()}, nil
+}
+
+func (c *Client) resolve(rawURL string) (string, error) {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
+
+func (c *Client) wait(ctx context.Context, target string) error {
+	c.mu.Lock()
+	paused := c.pausedUntil
+	c.mu.Unlock()
+
+	if err := sleepUntil(ctx, paused); err != nil {
+		return err
+	}
+
+	return c.limiterFor(target).Wait(ctx)
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	delay := time.Until(t)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cached returns the stored entry for url, whether it is still within the
+// TTL, and whether there was one at all. Expired entries are still
+// returned so their validators can be used for a conditional request.
+func (c *Client) cached(url string) (CacheEntry, bool, bool) {
+	if c.cache == nil {
+		return CacheEntry{}, false, false
+	}
+
+	entry, ok := c.cache.Get(url)
+	if !ok {
+		return CacheEntry{}, false, false
+	}
+
+	fresh := c.cacheTTL <= 0 || time.Since(entry.Stored) <= c.cacheTTL
+
+	return entry, fresh, true
+}
+
+// store caches body with the response's validators. When revalidating, a
+// 304 may omit them, so the previous entry's validators are kept.
+func (c *Client) store(url string, body []byte, header http.Header, prev *CacheEntry) {
+	if c.cache == nil {
+		return
+	}
+
+	entry := CacheEntry{
+		Body:         body,
+		Stored:       time.Now(),
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+
+	if prev != nil {
+		if entry.ETag == "" {
+			entry.ETag = prev.ETag
+		}
+
+		if entry.LastModified == "" {
+			entry.LastModified = prev.LastModified
+		}
+	}
+
+	c.cache.Set(url, entry)
+}
+
+func FetchBook(ctx context.Context, url string) (*Book, error) {
+	return DefaultClient.FetchBook(ctx, url)
+}
+
+func FetchList(ctx context.Context, url string) (*List, error) {
+	return DefaultClient.FetchList(ctx, url)
+}
+
+func FetchBookURLs(ctx context.Context, url string) ([]string, error) {
+	return DefaultClient.FetchBookURLs(ctx, url)
+}
+
+func FetchAuthor(ctx context.Context, url string) (*Author, error) {
+	return DefaultClient.FetchAuthor(ctx, url)
+}
+
+func FetchAuthorBooks(ctx context.Context, url string) (*AuthorBooks, error) {
+	return DefaultClient.FetchAuthorBooks(ctx, url)
+}
+
+func FetchSeries(ctx context.Context, url string) (*Series, error) {
+	return DefaultClient.FetchSeries(ctx, url)
+}
+
+func FetchGenre(ctx context.Context, url string) (*GenrePage, error) {
+	return DefaultClient.FetchGenre(ctx, url)
+}
+
+func FetchShelf(ctx context.Context, url string) (*Shelf, error) {
+	return DefaultClient.FetchShelf(ctx, url)
+}
+
+func FetchUser(ctx context.Context, url string) (*User, error) {
+	return DefaultClient.FetchUser(ctx, url)
+}
+
+func FetchQuotes(ctx context.Context, url string) (*QuotesPage, error) {
+	return DefaultClient.FetchQuotes(ctx, url)
+}
+
+func FetchEditions(ctx context.Context, url string) (*Editions, error) {
+	return DefaultClient.FetchEditions(ctx, url)
+}
+
+func FetchChoiceAwards(ctx context.Context, url string) (*ChoiceCategory, error) {
+	return DefaultClient.FetchChoiceAwards(ctx, url)
+}
+
+func FetchNewReleases(ctx context.Context, url string) ([]NewRelease, error) {
+	return DefaultClient.FetchNewReleases(ctx, url)
+}
+
+func FetchGroup(ctx context.Context, url string) (*Group, error) {
+	return DefaultClient.FetchGroup(ctx, url)
+}
+
+func FetchReview(ctx context.Context, url string) (*Review, error) {
+	return DefaultClient.FetchReview(ctx, url)
+}
+
+func FetchGiveaways(ctx context.Context, url string) (*GiveawaysPage, error) {
+	return DefaultClient.FetchGiveaways(ctx, url)
+}
+
+func FetchMostRead(ctx context.Context, url string) (*MostReadPage, error) {
+	return DefaultClient.FetchMostRead(ctx, url)
+}
+
+func FetchYearInBooks(ctx context.Context, url string) (*YearInBooks, error) {
+	return DefaultClient.FetchYearInBooks(ctx, url)
+}
+
+func FetchAuthorQuotes(ctx context.Context, url string) (*AuthorQuotes, error) {
+	return DefaultClient.FetchAuthorQuotes(ctx, url)
+}
+
+func FetchRecommendations(ctx context.Context, url string) (*Recommendations, error) {
+	return DefaultClient.FetchRecommendations(ctx, url)
+}
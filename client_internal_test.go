@@ -0,0 +1,39 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchRejectsOversizedBody(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(strings.Repeat("x", 17)))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithRetry(3, time.Millisecond, time.Millisecond))
+	c.maxBodySize = 16
+
+	if _, err := c.Get(context.Background(), srv.URL+"/big"); !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("err = %v, want ErrBodyTooLarge", err)
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests, want 1: an oversized body is not retried", n)
+	}
+
+	c.maxBodySize = 17
+
+	if body, err := c.Get(context.Background(), srv.URL+"/exact"); err != nil || len(body) != 17 {
+		t.Errorf("body at the limit: got %d bytes, %v", len(body), err)
+	}
+}
@@ -0,0 +1,121 @@
+// Command book works with crawled book data from the shell.
+//
+//	book query [-db books.sqlite | -in books.jsonl] [-limit n] 'rating>4.2 AND genres~fantasy'
+//
+// query prints the books matching a filter expression as JSON Lines. It
+// reads a bookdb store with -db, or JSON Lines from -in or standard input.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/bookdb"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("book: ")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "query":
+		if err := query(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: book query [-db file | -in file] [-limit n] expr")
+	os.Exit(2)
+}
+
+func query(args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	db := flags.String("db", "", "bookdb SQLite store to query")
+	in := flags.String("in", "", "JSON Lines file to query (defaults to standard input)")
+	limit := flags.Int("limit", 0, "stop after this many matches")
+	flags.Parse(args)
+
+	filter, err := book.ParseFilter(strings.Join(flags.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	out := book.NewJSONLWriter(os.Stdout)
+
+	if *db != "" {
+		store, err := bookdb.Open(*db)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		books, err := store.Find(ctx, bookdb.Query{Filter: filter, Limit: *limit})
+		if err != nil {
+			return err
+		}
+
+		for i := range books {
+			if err := out.Write(&books[i]); err != nil {
+				return err
+			}
+		}
+
+		return out.Flush()
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	matched := 0
+	err = book.ReadJSONL(r, func(b *book.Book) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if !filter.Match(*b) {
+			return nil
+		}
+
+		matched++
+		if err := out.Write(b); err != nil {
+			return err
+		}
+
+		if *limit > 0 && matched == *limit {
+			return io.EOF
+		}
+
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	return out.Flush()
+}
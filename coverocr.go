@@ -0,0 +1,87 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+const DefaultCoverMatchThreshold = 0.5
+
+// OCREngine extracts visible text from an image. Implementations can wrap
+// Tesseract, a cloud vision API, or anything else; the package ships none.
+type OCREngine interface {
+	RecognizeText(ctx context.Context, image []byte) (string, error)
+}
+
+type OCREngineFunc func(ctx context.Context, image []byte) (string, error)
+
+func (f OCREngineFunc) RecognizeText(ctx context.Context, image []byte) (string, error) {
+	return f(ctx, image)
+}
+
+// CoverCheck is the outcome of comparing a book's parsed title with the
+// text read off its cover. Score is the share of title words found on the
+// cover, and Mismatch is set when it falls below the threshold.
+type CoverCheck struct {
+	Title    string  `json:"title"`
+	Text     string  `json:"text"`
+	Score    float64 `json:"score"`
+	Mismatch bool    `json:"mismatch"`
+}
+
+var ErrNoCover = errors.New("book has no cover url")
+
+// VerifyCover downloads b's cover, runs it through engine and checks that
+// the visible text contains the title. It catches parses that picked up
+// another book's cover, e.g. from a carousel.
+func (c *Client) VerifyCover(ctx context.Context, b Book, engine OCREngine) (*CoverCheck, error) {
+	if b.CoverUrl == "" {
+		return nil, ErrNoCover
+	}
+
+	image, err := c.Get(ctx, b.CoverUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := engine.RecognizeText(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &CoverCheck{
+		Title: b.Title,
+		Text:  text,
+		Score: CoverTitleScore(b.Title, text),
+	}
+	check.Mismatch = check.Score < DefaultCoverMatchThreshold
+
+	return check, nil
+}
+
+// CoverTitleScore is the fraction of the title's words, ignoring any
+// subtitle and series suffix, that appear in the OCR text. OCR output is
+// noisy, so words are matched loosely after normalisation.
+func CoverTitleScore(title, text string) float64 {
+	main, _, _ := strings.Cut(title, ":")
+
+	words := strings.Fields(NormalizeTitle(main))
+	if len(words) == 0 {
+		return 0
+	}
+
+	seen := map[string]bool{}
+	for _, word := range strings.Fields(NormalizeTitle(text)) {
+		seen[word] = true
+	}
+
+	found := 0
+	for _, word := range words {
+		if seen[word] {
+			found++
+		}
+	}
+
+	return float64(found) / float64(len(words))
+}
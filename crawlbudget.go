@@ -0,0 +1,142 @@
+package book
+
+import "time"
+
+// CrawlBudget bounds a crawl. Zero fields mean no limit. When a limit is
+// hit the crawler stops handing out new URLs and lets fetches already in
+// flight finish, so nothing fetched is thrown away; with a checkpoint
+// configured, what was left can be picked up again with Resume.
+type CrawlBudget struct {
+	MaxPages   int
+	MaxDepth   int
+	MaxRuntime time.Duration
+
+	// MaxBytes needs a Fetcher that counts its downloads, as *Client
+	// does; other fetchers are not limited by it.
+	MaxBytes int64
+}
+
+type CrawlStop string
+
+const (
+	CrawlStopPages     CrawlStop = "pages"
+	CrawlStopRuntime   CrawlStop = "runtime"
+	CrawlStopBytes     CrawlStop = "bytes"
+	CrawlStopCancelled CrawlStop = "cancelled"
+)
+
+// CrawlReport summarises a finished crawl and what the budget cut off.
+// Stopped is empty when the crawl ran out of work by itself. Unvisited is
+// how many queued URLs were never fetched, and DepthCut how many
+// discovered URLs were dropped for being past the depth limit.
+type CrawlReport struct {
+	Pages     int
+	Failed    int
+	Bytes     int64
+	Elapsed   time.Duration
+	Depth     int
+	Stopped   CrawlStop
+	Unvisited int
+	DepthCut  int
+}
+
+func (r CrawlReport) Truncated() bool {
+	return r.Stopped != "" || r.DepthCut > 0
+}
+
+func WithCrawlBudget(b CrawlBudget) CrawlerOption {
+	return func(c *Crawler) {
+		c.budget = b
+	}
+}
+
+// WithCrawlReportHandler calls fn once each crawl has finished, just
+// before its results channel is closed.
+func WithCrawlReportHandler(fn func(CrawlReport)) CrawlerOption {
+	return func(c *Crawler) {
+		c.onReport = fn
+	}
+}
+
+// byteCounter is implemented by fetchers that can report how much they
+// have downloaded, which is what MaxBytes is measured against.
+type byteCounter interface {
+	BytesFetched() int64
+}
+
+// depthLimit is the deepest a discovered URL may be queued at, or -1 for
+// no limit, combining the discovery and budget limits.
+func (c *Crawler) depthLimit() int {
+	limit := c.maxDepth
+
+	if c.budget.MaxDepth > 0 && (limit < 0 || c.budget.MaxDepth < limit) {
+		limit = c.budget.MaxDepth
+	}
+
+	return limit
+}
+
+// crawlMeter tracks a crawl against its budget.
+type crawlMeter struct {
+	budget     CrawlBudget
+	counter    byteCounter
+	startBytes int64
+	start      time.Time
+	dispatched int
+	report     CrawlReport
+}
+
+func newCrawlMeter(budget CrawlBudget, fetcher Fetcher) *crawlMeter {
+	m := &crawlMeter{budget: budget, start: time.Now()}
+
+	if counter, ok := fetcher.(byteCounter); ok {
+		m.counter = counter
+		m.startBytes = counter.BytesFetched()
+	}
+
+	return m
+}
+
+func (m *crawlMeter) bytes() int64 {
+	if m.counter == nil {
+		return 0
+	}
+
+	return m.counter.BytesFetched() - m.startBytes
+}
+
+// exhausted reports which limit, if any, rules out dispatching another
+// URL.
+func (m *crawlMeter) exhausted(now time.Time) CrawlStop {
+	switch {
+	case m.budget.MaxPages > 0 && m.dispatched >= m.budget.MaxPages:
+		return CrawlStopPages
+	case m.budget.MaxRuntime > 0 && now.Sub(m.start) >= m.budget.MaxRuntime:
+		return CrawlStopRuntime
+	case m.budget.MaxBytes > 0 && m.bytes() >= m.budget.MaxBytes:
+		return CrawlStopBytes
+	}
+
+	return ""
+}
+
+func (m *crawlMeter) finished(d crawlDone) {
+	m.report.Pages++
+
+	if d.result.Err != nil {
+		m.report.Failed++
+	}
+
+	if d.item.Depth > m.report.Depth {
+		m.report.Depth = d.item.Depth
+	}
+}
+
+func (m *crawlMeter) close(stopped CrawlStop, unvisited int) CrawlReport {
+	m.report.Bytes = m.bytes()
+	m.report.Elapsed = time.Since(m.start)
+	m.report.Stopped = stopped
+	m.report.Unvisited = unvisited
+
+	return m.report
+}
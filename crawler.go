@@ -0,0 +1,288 @@
+package book
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultCrawlWorkers = 4
+
+type CrawlResult struct {
+	URL   string
+	Depth int
+	Book  *Book
+	Err   error
+}
+
+// Crawler fetches book pages over a pool of workers. Politeness is left to
+// the Fetcher: with a *Client every worker shares the client's rate
+// limiter, so adding workers only helps up to the configured rate.
+type Crawler struct {
+	fetcher    Fetcher
+	workers    int
+	windows    []CrawlWindow
+	windowLoc  *time.Location
+	discover   func(CrawlResult) []string
+	maxDepth   int
+	checkpoint *crawlCheckpointer
+	budget     CrawlBudget
+	onReport   func(CrawlReport)
+}
+
+type CrawlerOption func(*Crawler)
+
+func WithWorkers(n int) CrawlerOption {
+	return func(c *Crawler) {
+		c.workers = n
+	}
+}
+
+// WithDiscovery has the crawler queue the URLs fn returns for each result,
+// such as a book's similar-books links, down to maxDepth hops from the
+// seeds. A negative maxDepth means no limit. Discovered URLs go through the
+// frontier, so books already queued are not fetched again.
+func WithDiscovery(maxDepth int, fn func(CrawlResult) []string) CrawlerOption {
+	return func(c *Crawler) {
+		c.discover = fn
+		c.maxDepth = maxDepth
+	}
+}
+
+func NewCrawler(fetcher Fetcher, opts ...CrawlerOption) *Crawler {
+	c := &Crawler{
+		fetcher: fetcher,
+		workers: defaultCrawlWorkers,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.workers < 1 {
+		c.workers = 1
+	}
+
+	return c
+}
+
+// Crawl fetches each distinct seed URL, plus anything the discovery
+// function finds, and streams one result per URL, successful or not, on
+// the returned channel. The channel is closed once the work or the
+// budget runs out, or ctx is cancelled; URLs not reached by then produce
+// no result.
+func (c *Crawler) Crawl(ctx context.Context, seeds []string) <-chan CrawlResult {
+	frontier := c.newFrontier()
+	for _, url := range seeds {
+		frontier.Push(url, 0, 0)
+	}
+
+	return c.CrawlFrontier(ctx, frontier)
+}
+
+type crawlDone struct {
+	item       FrontierItem
+	result     CrawlResult
+	discovered []string
+}
+
+// newFrontier makes a frontier resolving against the fetcher's base URL
+// when it has one, as a *Client does, so relative links found on a test
+// server or mirror stay there.
+func (c *Crawler) newFrontier() *Frontier {
+	if b, ok := c.fetcher.(interface{ BaseURL() string }); ok {
+		if base, err := url.Parse(b.BaseURL()); err == nil {
+			return NewFrontier(base)
+		}
+	}
+
+	return NewFrontier(nil)
+}
+
+// CrawlFrontier crawls until frontier is empty and no fetch in flight can
+// add to it.
+func (c *Crawler) CrawlFrontier(ctx context.Context, frontier *Frontier) <-chan CrawlResult {
+	results := make(chan CrawlResult)
+	jobs := make(chan FrontierItem)
+	done := make(chan crawlDone)
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for item := range jobs {
+				result, ok := c.visit(ctx, item)
+				if !ok {
+					return
+				}
+
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+
+				finished := crawlDone{item: item, result: result}
+				if c.discover != nil {
+					finished.discovered = c.discover(result)
+				}
+
+				select {
+				case done <- finished:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// The dispatcher joins the wait group too, so by the time results is
+	// closed the final checkpoint has been written.
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+
+		// active holds popped items until a worker finishes them, so a
+		// checkpoint can put them back in the queue.
+		active := map[string]FrontierItem{}
+		meter := newCrawlMeter(c.budget, c.fetcher)
+		depthLimit := c.depthLimit()
+
+		var tick <-chan time.Time
+		if c.checkpoint != nil {
+			c.checkpoint.open()
+			defer c.checkpoint.close()
+
+			ticker := time.NewTicker(c.checkpoint.every)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		var deadline <-chan time.Time
+		if c.budget.MaxRuntime > 0 {
+			timer := time.NewTimer(c.budget.MaxRuntime)
+			defer timer.Stop()
+			deadline = timer.C
+		}
+
+		finish := func(d crawlDone) {
+			delete(active, d.item.Key)
+			meter.finished(d)
+
+			if c.checkpoint != nil {
+				c.checkpoint.record(d.item, d.result)
+			}
+
+			depth := d.item.Depth + 1
+			if len(d.discovered) > 0 && depthLimit >= 0 && depth > depthLimit {
+				meter.report.DepthCut += len(d.discovered)
+				return
+			}
+
+			for _, url := range d.discovered {
+				frontier.Push(url, -depth, depth)
+			}
+		}
+
+		save := func(complete bool) {
+			if c.checkpoint == nil {
+				return
+			}
+
+			items := make([]FrontierItem, 0, len(active))
+			for _, item := range active {
+				items = append(items, item)
+			}
+
+			sort.Slice(items, frontierHeap(items).Less)
+			c.checkpoint.save(frontier, items, complete)
+		}
+
+		// pending holds a popped item until a worker takes it, so items
+		// pushed by anyone else meanwhile can't be skipped.
+		var pending *FrontierItem
+		var stopped CrawlStop
+
+		defer func() {
+			if c.onReport == nil {
+				return
+			}
+
+			unvisited := frontier.Len()
+			if pending != nil {
+				unvisited++
+			}
+
+			c.onReport(meter.close(stopped, unvisited))
+		}()
+
+		for {
+			if pending == nil && stopped == "" {
+				if item, ok := frontier.Pop(); ok {
+					pending = &item
+					active[item.Key] = item
+				}
+			}
+
+			// Only a limit that stops real work counts as truncation.
+			if pending != nil && stopped == "" {
+				stopped = meter.exhausted(time.Now())
+			}
+
+			var send chan<- FrontierItem
+			var next FrontierItem
+
+			if pending != nil && stopped == "" {
+				send, next = jobs, *pending
+			} else if len(active) == 0 || (pending != nil && len(active) == 1) {
+				// Nothing in flight: either the work ran out or the
+				// budget did and everything dispatched has come back.
+				save(stopped == "")
+				return
+			}
+
+			select {
+			case send <- next:
+				pending = nil
+				meter.dispatched++
+			case d := <-done:
+				finish(d)
+			case <-tick:
+				save(false)
+			case <-deadline:
+				stopped = CrawlStopRuntime
+			case <-ctx.Done():
+				stopped = CrawlStopCancelled
+				save(false)
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+func (c *Crawler) visit(ctx context.Context, item FrontierItem) (CrawlResult, bool) {
+	if err := c.waitForWindow(ctx); err != nil {
+		return CrawlResult{}, false
+	}
+
+	b, err := c.fetcher.FetchBook(ctx, item.URL)
+	if ctx.Err() != nil {
+		return CrawlResult{}, false
+	}
+
+	return CrawlResult{URL: item.URL, Depth: item.Depth, Book: b, Err: err}, true
+}
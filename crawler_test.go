@@ -0,0 +1,47 @@
+package book_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestCrawlResolvesAgainstClientBaseURL(t *testing.T) {
+	books := []book.Book{}
+	for i := 1; i <= 5; i++ {
+		id := strconv.Itoa(i)
+		books = append(books, book.Book{ID: id, Title: "Book " + id, URL: "https://www.goodreads.com/book/show/" + id})
+	}
+
+	fake := testutil.NewFakeGoodreads(books...)
+	defer fake.Close()
+
+	client := book.NewClient(book.WithBaseURL(fake.URL))
+
+	urls, err := client.FetchBookURLs(context.Background(), fake.ListURL())
+	if err != nil {
+		t.Fatalf("FetchBookURLs: %v", err)
+	}
+
+	fetched := 0
+	for result := range book.NewCrawler(client).Crawl(context.Background(), urls) {
+		if !strings.HasPrefix(result.URL, fake.URL) {
+			t.Errorf("crawled %s, outside the fake server", result.URL)
+		}
+
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.URL, result.Err)
+			continue
+		}
+
+		fetched++
+	}
+
+	if fetched != len(books) {
+		t.Errorf("fetched %d books, want %d", fetched, len(books))
+	}
+}
@@ -0,0 +1,103 @@
+package book
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CrawlWindow is a daily span of wall-clock time, given as offsets from
+// midnight. End before Start wraps past midnight, so 22:00-06:00 covers
+// the night.
+type CrawlWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseCrawlWindow parses "HH:MM-HH:MM".
+func ParseCrawlWindow(s string) (CrawlWindow, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return CrawlWindow{}, fmt.Errorf("crawl window %q: want HH:MM-HH:MM", s)
+	}
+
+	w := CrawlWindow{}
+	var err error
+
+	if w.Start, err = parseClock(start); err != nil {
+		return CrawlWindow{}, fmt.Errorf("crawl window %q: %w", s, err)
+	}
+
+	if w.End, err = parseClock(end); err != nil {
+		return CrawlWindow{}, fmt.Errorf("crawl window %q: %w", s, err)
+	}
+
+	return w, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (w CrawlWindow) contains(offset time.Duration) bool {
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+
+	return offset >= w.Start || offset < w.End
+}
+
+// WithCrawlWindows restricts the crawler to issuing requests inside the
+// given windows, evaluated in loc (for example the site's timezone).
+// Outside them workers hold their queued URLs until the next window opens.
+func WithCrawlWindows(loc *time.Location, windows ...CrawlWindow) CrawlerOption {
+	return func(c *Crawler) {
+		if loc == nil {
+			loc = time.UTC
+		}
+
+		c.windowLoc = loc
+		c.windows = windows
+	}
+}
+
+// nextWindow returns now if it falls inside a window, and otherwise the
+// time the next window opens.
+func (c *Crawler) nextWindow(now time.Time) time.Time {
+	if len(c.windows) == 0 {
+		return now
+	}
+
+	local := now.In(c.windowLoc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.windowLoc)
+	offset := local.Sub(midnight)
+
+	var next time.Time
+
+	for _, w := range c.windows {
+		if w.contains(offset) {
+			return now
+		}
+
+		start := midnight.Add(w.Start)
+		if !start.After(local) {
+			start = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, c.windowLoc).Add(w.Start)
+		}
+
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+
+	return next
+}
+
+func (c *Crawler) waitForWindow(ctx context.Context) error {
+	return sleepUntil(ctx, c.nextWindow(time.Now()))
+}
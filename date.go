@@ -0,0 +1,155 @@
+package book
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DatePrecision says how much of a PartialDate the source actually gave.
+type DatePrecision int
+
+const (
+	DateUnknown DatePrecision = iota
+	DateYear
+	DateMonth
+	DateDay
+)
+
+func (p DatePrecision) String() string {
+	switch p {
+	case DateYear:
+		return "year"
+	case DateMonth:
+		return "month"
+	case DateDay:
+		return "day"
+	}
+
+	return "unknown"
+}
+
+// PartialDate is a date that may only be known to the month or year, as
+// Goodreads often gives publication dates. Time holds the first instant
+// of the period, in UTC.
+type PartialDate struct {
+	Time      time.Time
+	Precision DatePrecision
+}
+
+var dateLayouts = []struct {
+	layout    string
+	precision DatePrecision
+}{
+	{time.DateOnly, DateDay},
+	{"January 2, 2006", DateDay},
+	{"January 2 2006", DateDay},
+	{"Jan 2, 2006", DateDay},
+	{"Jan 2 2006", DateDay},
+	{"2 January 2006", DateDay},
+	{"2 Jan 2006", DateDay},
+	{"1/2/2006", DateDay},
+	{time.RFC3339, DateDay},
+	{time.RFC1123Z, DateDay},
+	{time.RFC1123, DateDay},
+	{"2006-01", DateMonth},
+	{"January 2006", DateMonth},
+	{"January, 2006", DateMonth},
+	{"Jan 2006", DateMonth},
+	{"Jan, 2006", DateMonth},
+	{"2006", DateYear},
+}
+
+var (
+	datePrefixPattern  = regexp.MustCompile(`(?i)^(?:first published|published|expected publication|released)\s*:?\s*`)
+	dateOrdinalPattern = regexp.MustCompile(`(\d)(?:st|nd|rd|th)\b`)
+	dateAbbrevPattern  = regexp.MustCompile(`\b(Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sept?|Oct|Nov|Dec)\.`)
+)
+
+// ParseDate reads the date formats Goodreads and its feeds use, such as
+// "January 1, 2020", "Jan 1st 2020", "2020-01-01", "Jan 2020" and "2020",
+// with or without a leading "Published". The result's Precision records
+// whether the day or month was given.
+func ParseDate(s string) (PartialDate, error) {
+	clean := strings.Join(strings.Fields(s), " ")
+	clean = datePrefixPattern.ReplaceAllString(clean, "")
+	clean = dateOrdinalPattern.ReplaceAllString(clean, "$1")
+	clean = dateAbbrevPattern.ReplaceAllString(clean, "$1")
+	clean = strings.Replace(clean, "Sept ", "Sep ", 1)
+
+	for _, l := range dateLayouts {
+		t, err := time.Parse(l.layout, clean)
+		if err != nil {
+			continue
+		}
+
+		if l.precision == DateDay {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		return PartialDate{Time: t, Precision: l.precision}, nil
+	}
+
+	return PartialDate{}, fmt.Errorf("parsing date %q: %w", s, ErrUnexpectedFormat)
+}
+
+func (d PartialDate) IsZero() bool {
+	return d.Precision == DateUnknown
+}
+
+// String writes the date to its precision: "2020", "2020-01" or
+// "2020-01-02".
+func (d PartialDate) String() string {
+	switch d.Precision {
+	case DateYear:
+		return d.Time.Format("2006")
+	case DateMonth:
+		return d.Time.Format("2006-01")
+	case DateDay:
+		return d.Time.Format(time.DateOnly)
+	}
+
+	return ""
+}
+
+func (d PartialDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *PartialDate) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if s == "" {
+		*d = PartialDate{}
+		return nil
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// PublishedDate parses Published.
+func (d BookDetails) PublishedDate() (PartialDate, error) {
+	return ParseDate(d.Published)
+}
+
+// FirstPublishedDate parses FirstPublished.
+func (d BookDetails) FirstPublishedDate() (PartialDate, error) {
+	return ParseDate(d.FirstPublished)
+}
+
+// PublishedDate parses Published, which reads like "March 3rd 2020".
+func (e Edition) PublishedDate() (PartialDate, error) {
+	return ParseDate(e.Published)
+}
@@ -0,0 +1,90 @@
+package book_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dchooyc/book"
+)
+
+func TestParseDate(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		in        string
+		want      time.Time
+		precision book.DatePrecision
+		str       string
+	}{
+		{"January 1, 2020", day(2020, time.January, 1), book.DateDay, "2020-01-01"},
+		{"2020-01-02", day(2020, time.January, 2), book.DateDay, "2020-01-02"},
+		{"Jan 1st 2020", day(2020, time.January, 1), book.DateDay, "2020-01-01"},
+		{"March 3rd, 2020", day(2020, time.March, 3), book.DateDay, "2020-03-03"},
+		{"Sept. 22nd 1991", day(1991, time.September, 22), book.DateDay, "1991-09-22"},
+		{"2 February 1990", day(1990, time.February, 2), book.DateDay, "1990-02-02"},
+		{"Published  July 4th   1976", day(1976, time.July, 4), book.DateDay, "1976-07-04"},
+		{"First published: May 1, 1965", day(1965, time.May, 1), book.DateDay, "1965-05-01"},
+		{"Expected publication June 10, 2030", day(2030, time.June, 10), book.DateDay, "2030-06-10"},
+		{"Tue, 10 Nov 2020 23:15:00 +0900", day(2020, time.November, 10), book.DateDay, "2020-11-10"},
+		{"Jan 2020", day(2020, time.January, 1), book.DateMonth, "2020-01"},
+		{"August, 1999", day(1999, time.August, 1), book.DateMonth, "1999-08"},
+		{"2020-05", day(2020, time.May, 1), book.DateMonth, "2020-05"},
+		{"2020", day(2020, time.January, 1), book.DateYear, "2020"},
+		{"published 1818", day(1818, time.January, 1), book.DateYear, "1818"},
+	}
+
+	for _, tt := range tests {
+		got, err := book.ParseDate(tt.in)
+		if err != nil {
+			t.Errorf("ParseDate(%q): %v", tt.in, err)
+			continue
+		}
+
+		if !got.Time.Equal(tt.want) || got.Time.Location() != time.UTC || got.Precision != tt.precision {
+			t.Errorf("ParseDate(%q) = %v at %s precision, want %v at %s", tt.in, got.Time, got.Precision, tt.want, tt.precision)
+		}
+
+		if got.String() != tt.str {
+			t.Errorf("ParseDate(%q).String() = %q, want %q", tt.in, got.String(), tt.str)
+		}
+	}
+}
+
+func TestParseDateErrors(t *testing.T) {
+	for _, in := range []string{"", "soon", "Smarch 2020", "2020-13-01", "31/31/2020"} {
+		got, err := book.ParseDate(in)
+		if !errors.Is(err, book.ErrUnexpectedFormat) {
+			t.Errorf("ParseDate(%q) error = %v, want ErrUnexpectedFormat", in, err)
+		}
+
+		if !got.IsZero() {
+			t.Errorf("ParseDate(%q) = %+v, want the zero date", in, got)
+		}
+	}
+}
+
+func TestPartialDateJSON(t *testing.T) {
+	for _, in := range []string{`"1999-08"`, `"2020"`, `"2020-01-02"`, `""`} {
+		var d book.PartialDate
+		if err := json.Unmarshal([]byte(in), &d); err != nil {
+			t.Errorf("Unmarshal(%s): %v", in, err)
+			continue
+		}
+
+		out, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(out) != in {
+			t.Errorf("%s round-tripped to %s", in, out)
+		}
+	}
+
+	var d book.PartialDate
+	if err := json.Unmarshal([]byte(`"someday"`), &d); !errors.Is(err, book.ErrUnexpectedFormat) {
+		t.Errorf("Unmarshal of a bad date: %v, want ErrUnexpectedFormat", err)
+	}
+}
@@ -0,0 +1,165 @@
+package book
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const maxDriftCandidates = 5
+
+// DriftCandidate is an element that looks like it could hold a field whose
+// selector stopped matching.
+type DriftCandidate struct {
+	Path  string `json:"path"`
+	Attr  string `json:"attr"`
+	Value string `json:"value"`
+	Text  string `json:"text,omitempty"`
+}
+
+// SelectorMiss is a selector that matched nothing, with the elements most
+// likely to have replaced it.
+type SelectorMiss struct {
+	Field      string           `json:"field"`
+	Selector   string           `json:"selector"`
+	Candidates []DriftCandidate `json:"candidates"`
+}
+
+// LayoutDiagnosis says which of the parser's selectors a book page still
+// satisfies.
+type LayoutDiagnosis struct {
+	Layout  BookLayout     `json:"layout"`
+	Matched []string       `json:"matched"`
+	Missing []SelectorMiss `json:"missing"`
+}
+
+func (d *LayoutDiagnosis) Drifted() bool {
+	return len(d.Missing) > 0
+}
+
+func (d *LayoutDiagnosis) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "layout %s: %d selectors matched, %d missing\n", d.Layout, len(d.Matched), len(d.Missing))
+
+	for _, miss := range d.Missing {
+		fmt.Fprintf(&sb, "%s: no match for %s\n", miss.Field, miss.Selector)
+
+		for _, c := range miss.Candidates {
+			fmt.Fprintf(&sb, "  %s [%s=%q]", c.Path, c.Attr, c.Value)
+			if c.Text != "" {
+				fmt.Fprintf(&sb, " %q", c.Text)
+			}
+
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+// driftProbe pairs what a field's selector matches with the words
+// elements that took its place are likely to carry.
+type driftProbe struct {
+	field    string
+	match    func(*html.Node) bool
+	keywords []string
+}
+
+func driftProbes(s Selectors) []driftProbe {
+	return []driftProbe{
+		{FieldTitle, byAttr("h1", "data-testid", s.TitleTestID), []string{"title"}},
+		{FieldURL, byCanonical(s.URL), []string{"canonical", "og:url"}},
+		{FieldID, byHref(s.ID), []string{"/work/"}},
+		{FieldCover, byClass("", s.Cover), []string{"cover"}},
+		{FieldAuthors, byClass("div", s.Authors), []string{"contributor", "author"}},
+		{FieldGenres, byHref(s.Genres), []string{"genre"}},
+		{FieldRating, byClass("div", s.Rating), []string{"rating", "average"}},
+		{FieldStats, byClass("div", s.Stats), []string{"ratings", "reviews", "meta"}},
+	}
+}
+
+func byCanonical(indicator string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return isElement(n, "link") && getAttr(n, "rel") == "canonical" && strings.Contains(getAttr(n, "href"), indicator)
+	}
+}
+
+// DiagnoseLayout checks a book page against the selectors GetBook would
+// use with the same options, and for each that matches nothing suggests
+// elements whose class, id, test id, href or label mentions the field,
+// such as divs with "Rating" in their class. It is meant for repairing
+// the selectors after a redesign, via WithSelectors.
+func DiagnoseLayout(r io.Reader, opts ...ParseOption) (*LayoutDiagnosis, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := newParseConfig(opts)
+	selectors := cfg.selectors.describe()
+	selectors[FieldStats] = selectors[FieldRatings]
+
+	d := &LayoutDiagnosis{
+		Layout:  detectBookLayout(doc, cfg),
+		Matched: []string{},
+		Missing: []SelectorMiss{},
+	}
+
+	for _, probe := range driftProbes(cfg.selectors) {
+		if findFirst(doc, probe.match) != nil {
+			d.Matched = append(d.Matched, probe.field)
+			continue
+		}
+
+		d.Missing = append(d.Missing, SelectorMiss{
+			Field:      probe.field,
+			Selector:   selectors[probe.field],
+			Candidates: driftCandidates(doc, probe.keywords),
+		})
+	}
+
+	return d, nil
+}
+
+var driftAttrs = []string{"class", "id", "data-testid", "itemprop", "property", "rel", "href", "aria-label"}
+
+func driftCandidates(doc *html.Node, keywords []string) []DriftCandidate {
+	candidates := []DriftCandidate{}
+
+	findFirst(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data == "script" || n.Data == "style" {
+			return false
+		}
+
+		if attr, val, ok := driftMatch(n, keywords); ok {
+			candidates = append(candidates, DriftCandidate{
+				Path:  nodePath(n),
+				Attr:  attr,
+				Value: val,
+				Text:  truncateRunes(textContent(n), 60),
+			})
+		}
+
+		return len(candidates) == maxDriftCandidates
+	})
+
+	return candidates
+}
+
+func driftMatch(n *html.Node, keywords []string) (string, string, bool) {
+	for _, key := range driftAttrs {
+		val := getAttr(n, key)
+		lower := strings.ToLower(val)
+
+		for _, keyword := range keywords {
+			if strings.Contains(lower, keyword) {
+				return key, val, true
+			}
+		}
+	}
+
+	return "", "", false
+}
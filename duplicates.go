@@ -0,0 +1,103 @@
+package book
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var seriesSuffixPattern = regexp.MustCompile(`\s*\([^()]*#[\d.]+[^()]*\)\s*$`)
+
+type DuplicateGroup struct {
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Books  []Book `json:"books"`
+}
+
+func FindDuplicateTitles(books []Book) []DuplicateGroup {
+	groups := map[string]*DuplicateGroup{}
+	works := map[string]map[string]bool{}
+
+	for _, b := range books {
+		workID := b.ID
+		if workID == "" {
+			workID = b.URL
+		}
+
+		author := ""
+		if len(b.Authors) > 0 {
+			author = b.Authors[0].Name()
+		}
+
+		key := NormalizeTitle(b.Title) + "\x00" + NormalizeTitle(author)
+
+		group, ok := groups[key]
+		if !ok {
+			group = &DuplicateGroup{Title: b.Title, Author: author}
+			groups[key] = group
+			works[key] = map[string]bool{}
+		}
+
+		if works[key][workID] {
+			continue
+		}
+
+		works[key][workID] = true
+		group.Books = append(group.Books, b)
+	}
+
+	duplicates := []DuplicateGroup{}
+
+	for _, group := range groups {
+		if len(group.Books) > 1 {
+			duplicates = append(duplicates, *group)
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Title != duplicates[j].Title {
+			return duplicates[i].Title < duplicates[j].Title
+		}
+
+		return duplicates[i].Author < duplicates[j].Author
+	})
+
+	return duplicates
+}
+
+func NormalizeTitle(title string) string {
+	title = seriesSuffixPattern.ReplaceAllString(title, "")
+
+	var sb strings.Builder
+	for _, r := range strings.ToLower(norm.NFC.String(title)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func WriteDuplicateReport(w io.Writer, groups []DuplicateGroup) error {
+	for _, group := range groups {
+		if _, err := fmt.Fprintf(w, "%q by %s: %d distinct works\n", group.Title, group.Author, len(group.Books)); err != nil {
+			return err
+		}
+
+		for _, b := range group.Books {
+			if _, err := fmt.Fprintf(w, "\t%s\t%s\t%d ratings\n", b.ID, b.URL, b.Ratings); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package book
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	EditionsURLIndicator  = "/work/editions/"
+	EditionIndicator      = "elementList"
+	EditionDataIndicator  = "editionData"
+	EditionRowIndicator   = "dataRow"
+	EditionTitleIndicator = "dataTitle"
+	EditionValueIndicator = "dataValue"
+)
+
+var (
+	editionPublishedPattern = regexp.MustCompile(`^(?:Published|Expected publication)\s*(.*?)(?:\s+by\s+(.+))?$`)
+	editionFormatPattern    = regexp.MustCompile(`^([^,]+?)(?:,\s*(\d+)\s+pages)?$`)
+	yearPattern             = regexp.MustCompile(`\b(\d{4})\b`)
+	isbn13Pattern           = regexp.MustCompile(`\b(97[89]\d{10})\b`)
+	isbn10Pattern           = regexp.MustCompile(`\b(\d{9}[\dXx])\b`)
+)
+
+type Editions struct {
+	Title    string    `json:"title"`
+	Editions []Edition `json:"editions"`
+	NextPage string    `json:"next_page,omitempty"`
+}
+
+type Edition struct {
+	Title     string  `json:"title"`
+	URL       string  `json:"url"`
+	ID        string  `json:"id"`
+	CoverUrl  string  `json:"cover_url"`
+	Format    string  `json:"format"`
+	Pages     int     `json:"pages"`
+	ISBN      string  `json:"isbn"`
+	ISBN13    string  `json:"isbn13"`
+	ASIN      string  `json:"asin"`
+	Language  string  `json:"language"`
+	Publisher string  `json:"publisher"`
+	Published string  `json:"published"`
+	Year      int     `json:"year"`
+	Rating    float64 `json:"rating"`
+}
+
+func GetEditions(r io.Reader) (*Editions, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	editions := &Editions{
+		Editions: []Edition{},
+		NextPage: extractNextPage(doc),
+	}
+
+	if h1 := findFirst(doc, func(n *html.Node) bool { return isElement(n, "h1") }); h1 != nil {
+		if a := findFirst(h1, func(n *html.Node) bool { return isElement(n, "a") }); a != nil {
+			editions.Title = textContent(a)
+		}
+	}
+
+	for _, n := range findAll(doc, byClass("div", EditionIndicator)) {
+		edition, ok := extractEdition(n)
+		if ok {
+			editions.Editions = append(editions.Editions, edition)
+		}
+	}
+
+	return editions, nil
+}
+
+func extractEdition(n *html.Node) (Edition, bool) {
+	edition := Edition{}
+
+	data := findFirst(n, byClass("div", EditionDataIndicator))
+	if data == nil {
+		return edition, false
+	}
+
+	if img := findFirst(n, func(n *html.Node) bool { return isElement(n, "img") }); img != nil {
+		edition.CoverUrl = getAttr(img, "src")
+	}
+
+	for _, row := range findAll(data, byClass("div", EditionRowIndicator)) {
+		if title := findFirst(row, byClass("div", EditionTitleIndicator)); title != nil {
+			value := findFirst(row, byClass("div", EditionValueIndicator))
+			if value != nil {
+				extractEditionDetail(strings.TrimSuffix(textContent(title), ":"), textContent(value), &edition)
+			}
+
+			continue
+		}
+
+		if a := findFirst(row, byClass("a", BookRefTitleIndicator)); a != nil {
+			edition.Title = textContent(a)
+			edition.URL = getAttr(a, "href")
+			edition.ID = bookIDFromURL(edition.URL)
+			continue
+		}
+
+		text := textContent(row)
+
+		if m := editionPublishedPattern.FindStringSubmatch(text); m != nil {
+			edition.Published = m[1]
+			edition.Publisher = m[2]
+
+			if y := yearPattern.FindStringSubmatch(m[1]); y != nil {
+				edition.Year, _ = strconv.Atoi(y[1])
+			}
+
+			continue
+		}
+
+		if m := editionFormatPattern.FindStringSubmatch(text); m != nil && edition.Format == "" {
+			edition.Format = m[1]
+			edition.Pages, _ = strconv.Atoi(m[2])
+		}
+	}
+
+	return edition, edition.URL != ""
+}
+
+func extractEditionDetail(title, value string, edition *Edition) {
+	switch title {
+	case "ISBN", "ISBN13", "ISBN10":
+		if m := isbn13Pattern.FindStringSubmatch(value); m != nil {
+			edition.ISBN13 = m[1]
+		}
+
+		if m := isbn10Pattern.FindStringSubmatch(value); m != nil {
+			edition.ISBN = strings.ToUpper(m[1])
+		}
+	case "ASIN":
+		edition.ASIN = strings.Fields(value + " ")[0]
+	case "Edition language":
+		edition.Language = value
+	case "Average rating":
+		if fields := strings.Fields(value); len(fields) > 0 {
+			edition.Rating, _ = strconv.ParseFloat(fields[0], 64)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+var ErrNoEgressAddr = errors.New("no local address for the target's address family")
+
+// ErrCustomTransport is returned by every request of a client that was
+// given a custom RoundTripper along with an option, such as WithLocalAddrs
+// or WithProxyPool, that can only be applied to an *http.Transport.
+var ErrCustomTransport = errors.New("option needs an *http.Transport")
+
+type egress struct {
+	addrs      []net.IP
+	ifaces     []string
+	preferIPv6 bool
+	next       atomic.Uint64
+	dialer     net.Dialer
+}
+
+// WithLocalAddrs binds outgoing connections to the given local IPs,
+// rotating through them per connection so load is spread across egress
+// addresses. Like the other egress options, it needs the client's
+// transport to be an *http.Transport; see Client.Err.
+func WithLocalAddrs(addrs ...string) ClientOption {
+	return func(c *Client) {
+		e := c.egressConfig()
+
+		for _, addr := range addrs {
+			if ip := net.ParseIP(addr); ip != nil {
+				e.addrs = append(e.addrs, ip)
+			} else {
+				e.ifaces = append(e.ifaces, addr)
+			}
+		}
+	}
+}
+
+// WithInterface binds outgoing connections to the addresses of a network
+// interface such as "eth1".
+func WithInterface(name string) ClientOption {
+	return func(c *Client) {
+		e := c.egressConfig()
+		e.ifaces = append(e.ifaces, name)
+	}
+}
+
+// WithPreferIPv6 dials a host's IPv6 addresses before its IPv4 ones.
+func WithPreferIPv6(prefer bool) ClientOption {
+	return func(c *Client) {
+		c.egressConfig().preferIPv6 = prefer
+	}
+}
+
+func (c *Client) egressConfig() *egress {
+	if c.egress == nil {
+		c.egress = &egress{dialer: net.Dialer{Timeout: defaultTimeout, KeepAlive: 30 * time.Second}}
+	}
+
+	return c.egress
+}
+
+// egressPolicy installs the egress dialer on the client's transport.
+func (c *Client) egressPolicy() {
+	if c.egress == nil {
+		return
+	}
+
+	c.configureTransport("egress address options", func(t *http.Transport) {
+		t.DialContext = c.egress.dialContext
+	})
+}
+
+// configureTransport applies fn to a clone of the client's transport, so a
+// caller-provided http.Client is never mutated. A custom RoundTripper that
+// isn't an *http.Transport can't be configured: it is left alone, the
+// client is failed with ErrCustomTransport, and false is returned.
+func (c *Client) configureTransport(option string, fn func(*http.Transport)) bool {
+	var transport *http.Transport
+
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		c.err = errors.Join(c.err, fmt.Errorf("%s with a %T: %w", option, t, ErrCustomTransport))
+		return false
+	}
+
+	fn(transport)
+
+	hc := *c.httpClient
+	hc.Transport = transport
+	c.httpClient = &hc
+
+	return true
+}
+
+func (e *egress) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	locals, err := e.localAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	remotes, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(remotes, func(i, j int) bool {
+		return (remotes[i].IP.To4() == nil) == e.preferIPv6 && (remotes[j].IP.To4() == nil) != e.preferIPv6
+	})
+
+	errs := []error{}
+
+	for _, remote := range remotes {
+		dialer := e.dialer
+
+		if len(locals) > 0 {
+			local := e.pick(locals, remote.IP.To4() == nil)
+			if local == nil {
+				continue
+			}
+
+			dialer.LocalAddr = &net.TCPAddr{IP: local}
+		}
+
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(remote.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("dialing %s: %w", address, ErrNoEgressAddr)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// pick returns the next local address of the wanted family in round-robin
+// order, or nil if there is none.
+func (e *egress) pick(locals []net.IP, ipv6 bool) net.IP {
+	family := []net.IP{}
+	for _, ip := range locals {
+		if (ip.To4() == nil) == ipv6 {
+			family = append(family, ip)
+		}
+	}
+
+	if len(family) == 0 {
+		return nil
+	}
+
+	return family[(e.next.Add(1)-1)%uint64(len(family))]
+}
+
+func (e *egress) localAddrs() ([]net.IP, error) {
+	locals := append([]net.IP{}, e.addrs...)
+
+	for _, name := range e.ifaces {
+		iface, err := net.InterfaceByName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.IsGlobalUnicast() {
+				locals = append(locals, ipnet.IP)
+			}
+		}
+	}
+
+	return locals, nil
+}
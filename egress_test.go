@@ -0,0 +1,53 @@
+package book_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestEgressWithCustomTransportFails(t *testing.T) {
+	called := false
+	hc := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return nil, errors.New("unreachable")
+	})}
+
+	for name, opt := range map[string]book.ClientOption{
+		"local addrs": book.WithLocalAddrs("127.0.0.1"),
+		"prefer IPv6": book.WithPreferIPv6(true),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := book.NewClient(book.WithHTTPClient(hc), opt)
+
+			if err := c.Err(); !errors.Is(err, book.ErrCustomTransport) {
+				t.Errorf("Err() = %v, want ErrCustomTransport", err)
+			}
+
+			if _, err := c.Get(context.Background(), "/book/show/1"); !errors.Is(err, book.ErrCustomTransport) {
+				t.Errorf("Get error = %v, want ErrCustomTransport", err)
+			}
+
+			if called {
+				t.Error("request reached the transport the option couldn't configure")
+			}
+		})
+	}
+}
+
+func TestEgressWithHTTPTransport(t *testing.T) {
+	hc := &http.Client{Transport: &http.Transport{}}
+
+	if err := book.NewClient(book.WithHTTPClient(hc), book.WithLocalAddrs("127.0.0.1")).Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
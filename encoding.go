@@ -0,0 +1,72 @@
+package book
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// acceptEncoding is sent on every request unless the caller sets its own
+// Accept-Encoding. Setting the header turns off the transport's own gzip
+// handling, so fetch decodes everything listed here itself.
+const acceptEncoding = "br, gzip, deflate, zstd"
+
+// decodeBody undoes the Content-Encoding of a response, outermost coding
+// last in the header and so first to be removed. Parsers always see plain
+// HTML, whatever the server chose to send.
+func decodeBody(header http.Header, body io.Reader) (io.Reader, func(), error) {
+	closers := []func(){}
+	release := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	codings := strings.Split(header.Get("Content-Encoding"), ",")
+
+	for i := len(codings) - 1; i >= 0; i-- {
+		switch coding := strings.ToLower(strings.TrimSpace(codings[i])); coding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			gz, err := gzip.NewReader(body)
+			if err != nil {
+				release()
+				return nil, nil, err
+			}
+
+			closers = append(closers, func() { gz.Close() })
+			body = gz
+		case "deflate":
+			zr, err := zlib.NewReader(body)
+			if err != nil {
+				release()
+				return nil, nil, err
+			}
+
+			closers = append(closers, func() { zr.Close() })
+			body = zr
+		case "br":
+			body = brotli.NewReader(body)
+		case "zstd":
+			zr, err := zstd.NewReader(body)
+			if err != nil {
+				release()
+				return nil, nil, err
+			}
+
+			closers = append(closers, zr.Close)
+			body = zr
+		default:
+			release()
+			return nil, nil, fmt.Errorf("unsupported content encoding %q", coding)
+		}
+	}
+
+	return body, release, nil
+}
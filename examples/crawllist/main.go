@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/dchooyc/book"
+)
+
+func main() {
+	listURL := flag.String("list", "https://www.goodreads.com/list/show/1.Best_Books_Ever", "list page to crawl")
+	outDir := flag.String("out", "out", "directory for books.json and relational CSV tables")
+	limit := flag.Int("limit", 10, "maximum number of books to fetch")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := crawl(ctx, book.DefaultClient, *listURL, *outDir, *limit); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// crawl fetches up to limit books from the list page and writes them to
+// outDir as books.json and the relational CSV tables.
+func crawl(ctx context.Context, client *book.Client, listURL, outDir string, limit int) error {
+	urls, err := client.FetchBookURLs(ctx, listURL)
+	if err != nil {
+		return err
+	}
+
+	if len(urls) > limit {
+		urls = urls[:limit]
+	}
+
+	books := book.Books{Books: []book.Book{}}
+
+	for _, url := range urls {
+		b, err := client.FetchBook(ctx, url)
+		if err != nil {
+			log.Printf("%s: %v", url, err)
+			continue
+		}
+
+		log.Printf("fetched %q", b.Title)
+		books.Books = append(books.Books, *b)
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(books, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(outDir, "books.json"), data, 0o644); err != nil {
+		return err
+	}
+
+	return book.ExportRelational(outDir, books.Books)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestCrawl(t *testing.T) {
+	fake := testutil.NewFakeGoodreads(
+		book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225", Authors: book.AuthorRefs("Frank Herbert")},
+		book.Book{Title: "Emma", ID: "6969", URL: "https://www.goodreads.com/book/show/6969", Authors: book.AuthorRefs("Jane Austen")},
+		book.Book{Title: "Ulysses", ID: "338798", URL: "https://www.goodreads.com/book/show/338798", Authors: book.AuthorRefs("James Joyce")},
+	)
+	defer fake.Close()
+
+	out := t.TempDir()
+	client := book.NewClient(book.WithBaseURL(fake.URL))
+
+	if err := crawl(context.Background(), client, fake.ListURL(), out, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "books.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var books book.Books
+	if err := json.Unmarshal(data, &books); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(books.Books) != 2 || books.Books[0].Title != "Dune" || books.Books[1].Title != "Emma" {
+		t.Errorf("books.json = %+v, want Dune and Emma", books.Books)
+	}
+
+	f, err := os.Open(filepath.Join(out, "authors.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Errorf("authors.csv has %d records, want a header and two authors", len(records))
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/dchooyc/book"
+)
+
+// layoutdrift reports which of the parser's selectors a book page no
+// longer matches, with candidate replacements, from a saved file or a
+// live URL.
+func main() {
+	selectorsPath := flag.String("selectors", "", "selector pack to check instead of the defaults")
+	asJSON := flag.Bool("json", false, "print the diagnosis as JSON")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		log.Fatal("usage: layoutdrift [-selectors pack.json] [-json] <file or URL>")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := []book.ParseOption{}
+	if *selectorsPath != "" {
+		f, err := os.Open(*selectorsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pack, err := book.LoadSelectorPack(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts = append(opts, book.WithSelectorPack(pack))
+	}
+
+	page, err := read(ctx, book.NewClient(), flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	diagnosis, err := book.DiagnoseLayout(bytes.NewReader(page), opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diagnosis); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		fmt.Print(diagnosis)
+	}
+
+	if diagnosis.Drifted() {
+		os.Exit(1)
+	}
+}
+
+// read loads the page from a file, or through client if target is a URL.
+func read(ctx context.Context, client *book.Client, target string) ([]byte, error) {
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		return os.ReadFile(target)
+	}
+
+	return client.Get(ctx, target)
+}
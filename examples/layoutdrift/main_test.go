@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestReadURLAndFile(t *testing.T) {
+	dune := book.Book{
+		Title:   "Dune",
+		ID:      "234225",
+		URL:     "https://www.goodreads.com/book/show/234225",
+		Authors: []book.AuthorRef{book.NewAuthorRef("Frank Herbert", "58")},
+		Genres:  []book.Genre{"science-fiction"},
+		Rating:  4.27,
+	}
+
+	fake := testutil.NewFakeGoodreads(dune)
+	defer fake.Close()
+
+	ctx := context.Background()
+	client := book.NewClient(book.WithBaseURL(fake.URL))
+
+	fetched, err := read(ctx, client, fake.BookURL(dune))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "dune.html")
+	if err := os.WriteFile(path, fetched, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := read(ctx, client, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(saved, fetched) {
+		t.Error("the saved page differs from the fetched one")
+	}
+
+	diagnosis, err := book.DiagnoseLayout(bytes.NewReader(fetched))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diagnosis.Drifted() {
+		t.Errorf("fixture page drifted:\n%s", diagnosis)
+	}
+}
+
+func TestReadDriftedPage(t *testing.T) {
+	dune := book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225", Rating: 4.27}
+	page := strings.Replace(string(testutil.RenderFixture(dune)), "RatingStatistics__rating", "RatingSummary__value", 1)
+
+	path := filepath.Join(t.TempDir(), "dune.html")
+	if err := os.WriteFile(path, []byte(page), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := read(context.Background(), book.NewClient(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diagnosis, err := book.DiagnoseLayout(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !diagnosis.Drifted() {
+		t.Errorf("renamed rating class not reported:\n%s", diagnosis)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/opds"
+)
+
+func main() {
+	in := flag.String("in", "out/books.json", "books.json written by the crawllist example")
+	addr := flag.String("addr", "localhost:8080", "address to serve the catalog on")
+	title := flag.String("title", "My Books", "catalog title")
+	flag.Parse()
+
+	catalog, err := load(*in, *title)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Point an e-reader's OPDS catalog setting at http://<addr>/opds/.
+	http.Handle("/opds/", catalog)
+
+	log.Printf("serving %s on http://%s/opds/", *in, *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+func load(path, title string) (*opds.Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	books := book.Books{}
+	if err := json.Unmarshal(data, &books); err != nil {
+		return nil, err
+	}
+
+	return opds.NewCatalog(title, books.Books, opds.WithBasePath("/opds/")), nil
+}
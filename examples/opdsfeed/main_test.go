@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+func TestCatalog(t *testing.T) {
+	books := book.Books{Books: []book.Book{
+		{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225", Genres: []book.Genre{"science-fiction"}},
+		{Title: "Emma", ID: "6969", URL: "https://www.goodreads.com/book/show/6969", Genres: []book.Genre{"classics"}},
+	}}
+
+	data, err := json.Marshal(books)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "books.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog, err := load(path, "Test Catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/opds/", []string{"Test Catalog", "/opds/all", "/opds/genre/classics"}},
+		{"/opds/all", []string{"Dune", "Emma"}},
+		{"/opds/genre/classics", []string{"Emma"}},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		catalog.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d", tt.path, rec.Code)
+			continue
+		}
+
+		for _, want := range tt.want {
+			if !strings.Contains(rec.Body.String(), want) {
+				t.Errorf("%s: feed has no %q", tt.path, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/dchooyc/book"
+)
+
+func main() {
+	in := flag.String("in", "out/books.json", "books.json written by a previous crawl")
+	out := flag.String("out", "", "where to write the refreshed books (defaults to -in)")
+	flag.Parse()
+
+	if *out == "" {
+		*out = *in
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := refresh(ctx, book.DefaultClient, *in, *out, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// refresh refetches every book in the books.json at in, prints what
+// changed to w and writes the refreshed books to out. Books that fail to
+// fetch are kept as they were.
+func refresh(ctx context.Context, client *book.Client, in, out string, w io.Writer) error {
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+
+	books := book.Books{}
+	if err := json.Unmarshal(data, &books); err != nil {
+		return err
+	}
+
+	for i, old := range books.Books {
+		fresh, err := client.FetchBook(ctx, old.URL)
+		if err != nil {
+			log.Printf("%s: %v", old.URL, err)
+			continue
+		}
+
+		for _, change := range diff(old, *fresh) {
+			fmt.Fprintf(w, "%s: %s\n", old.Title, change)
+		}
+
+		books.Books[i] = *fresh
+	}
+
+	data, err = json.MarshalIndent(books, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(out, data, 0o644)
+}
+
+func diff(old, fresh book.Book) []string {
+	changes := []string{}
+
+	if old.Title != fresh.Title {
+		changes = append(changes, fmt.Sprintf("title %q -> %q", old.Title, fresh.Title))
+	}
+
+	if old.Rating != fresh.Rating {
+		changes = append(changes, fmt.Sprintf("rating %.2f -> %.2f", old.Rating, fresh.Rating))
+	}
+
+	if old.Ratings != fresh.Ratings {
+		changes = append(changes, fmt.Sprintf("ratings %d -> %d", old.Ratings, fresh.Ratings))
+	}
+
+	if old.Reviews != fresh.Reviews {
+		changes = append(changes, fmt.Sprintf("reviews %d -> %d", old.Reviews, fresh.Reviews))
+	}
+
+	if old.CoverUrl != fresh.CoverUrl {
+		changes = append(changes, "cover changed")
+	}
+
+	return changes
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestRefresh(t *testing.T) {
+	dune := book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225", Rating: 4.27, Ratings: 1431283}
+	emma := book.Book{Title: "Emma", ID: "6969", URL: "https://www.goodreads.com/book/show/6969", Rating: 4.02, Ratings: 800000}
+
+	fake := testutil.NewFakeGoodreads(dune, emma)
+	defer fake.Close()
+
+	staleDune := dune
+	staleDune.URL, staleDune.Rating, staleDune.Ratings = fake.BookURL(dune), 4.25, 1400000
+
+	freshEmma := emma
+	freshEmma.URL = fake.BookURL(emma)
+
+	data, err := json.Marshal(book.Books{Books: []book.Book{staleDune, freshEmma}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	in, out := filepath.Join(dir, "in.json"), filepath.Join(dir, "out.json")
+
+	if err := os.WriteFile(in, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var changes bytes.Buffer
+	client := book.NewClient(book.WithBaseURL(fake.URL))
+
+	if err := refresh(context.Background(), client, in, out, &changes); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Dune: rating 4.25 -> 4.27\nDune: ratings 1400000 -> 1431283\n"
+	if changes.String() != want {
+		t.Errorf("changes = %q, want %q", changes.String(), want)
+	}
+
+	data, err = os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var refreshed book.Books
+	if err := json.Unmarshal(data, &refreshed); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(refreshed.Books) != 2 || refreshed.Books[0].Rating != dune.Rating {
+		t.Errorf("refreshed = %+v, want Dune at %v", refreshed.Books, dune.Rating)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/bookdb"
+)
+
+func main() {
+	listURL := flag.String("list", "https://www.goodreads.com/list/show/1.Best_Books_Ever", "list page to crawl")
+	dbPath := flag.String("db", "books.sqlite", "SQLite store to add the books to")
+	limit := flag.Int("limit", 10, "maximum number of books to fetch")
+	filter := flag.String("filter", "rating>4", "filter expression for the books printed afterwards")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store, err := bookdb.Open(*dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := crawl(ctx, book.DefaultClient, store, *listURL, *limit); err != nil {
+		log.Fatal(err)
+	}
+
+	f, err := book.ParseFilter(*filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	books, err := store.Find(ctx, bookdb.Query{Filter: f})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, b := range books {
+		fmt.Printf("%.2f  %s\n", b.Rating, b.Title)
+	}
+}
+
+// crawl fetches up to limit books from the list page and upserts them, so
+// running it again refreshes the books already stored.
+func crawl(ctx context.Context, client *book.Client, store *bookdb.Store, listURL string, limit int) error {
+	if err := store.Migrate(ctx); err != nil {
+		return err
+	}
+
+	urls, err := client.FetchBookURLs(ctx, listURL)
+	if err != nil {
+		return err
+	}
+
+	if len(urls) > limit {
+		urls = urls[:limit]
+	}
+
+	for _, url := range urls {
+		b, err := client.FetchBook(ctx, url)
+		if err != nil {
+			log.Printf("%s: %v", url, err)
+			continue
+		}
+
+		if err := store.Upsert(ctx, *b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
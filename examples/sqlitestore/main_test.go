@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/bookdb"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestCrawl(t *testing.T) {
+	fake := testutil.NewFakeGoodreads(
+		book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225", Rating: 4.27},
+		book.Book{Title: "Emma", ID: "6969", URL: "https://www.goodreads.com/book/show/6969", Rating: 4.02},
+		book.Book{Title: "Ulysses", ID: "338798", URL: "https://www.goodreads.com/book/show/338798", Rating: 3.74},
+	)
+	defer fake.Close()
+
+	store, err := bookdb.Open(filepath.Join(t.TempDir(), "books.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	client := book.NewClient(book.WithBaseURL(fake.URL))
+
+	if err := crawl(ctx, client, store, fake.ListURL(), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := store.Count(ctx); err != nil || n != 2 {
+		t.Fatalf("Count = %d, %v; want 2", n, err)
+	}
+
+	books, err := store.Find(ctx, bookdb.Query{Filter: book.MustParseFilter("rating>4.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(books) != 1 || books[0].Title != "Dune" {
+		t.Errorf("Find = %+v, want only Dune", books)
+	}
+}
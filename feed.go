@@ -0,0 +1,139 @@
+package book
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const SearchURLPrefix = "/search?q="
+
+var goodreadsBookURLPattern = regexp.MustCompile(`https?://(?:www\.)?goodreads\.com/book/show/[^\s"'<>]+`)
+
+type FeedItem struct {
+	Title       string `json:"title"`
+	Link        string `json:"link"`
+	Description string `json:"description"`
+	Published   string `json:"published"`
+}
+
+type feedDocument struct {
+	Channel struct {
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+			PubDate     string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+func GetFeedItems(r io.Reader) ([]FeedItem, error) {
+	doc := feedDocument{}
+
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	items := []FeedItem{}
+
+	for _, item := range doc.Channel.Items {
+		items = append(items, FeedItem{
+			Title:       strings.TrimSpace(item.Title),
+			Link:        strings.TrimSpace(item.Link),
+			Description: item.Description,
+			Published:   strings.TrimSpace(item.PubDate),
+		})
+	}
+
+	for _, entry := range doc.Entries {
+		item := FeedItem{
+			Title:       strings.TrimSpace(entry.Title),
+			Description: strings.TrimSpace(entry.Summary + " " + entry.Content),
+			Published:   strings.TrimSpace(entry.Published),
+		}
+
+		if item.Published == "" {
+			item.Published = strings.TrimSpace(entry.Updated)
+		}
+
+		for _, link := range entry.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				item.Link = link.Href
+				break
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (item FeedItem) BookURLs() []string {
+	if strings.Contains(item.Link, BookURLIndicator) {
+		return []string{item.Link}
+	}
+
+	return goodreadsBookURLPattern.FindAllString(item.Description, -1)
+}
+
+func (c *Client) SearchBookURLs(ctx context.Context, query string) ([]string, error) {
+	return c.FetchBookURLs(ctx, SearchURLPrefix+url.QueryEscape(query))
+}
+
+func (c *Client) FeedSeeds(ctx context.Context, feedURL string) ([]string, error) {
+	body, err := c.Get(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := GetFeedItems(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	seeds, seen := []string{}, map[string]bool{}
+
+	for _, item := range items {
+		urls := item.BookURLs()
+
+		if len(urls) == 0 && item.Title != "" {
+			found, err := c.SearchBookURLs(ctx, item.Title)
+			if err != nil {
+				return seeds, err
+			}
+
+			if len(found) > 0 {
+				urls = found[:1]
+			}
+		}
+
+		for _, u := range urls {
+			if !seen[u] {
+				seen[u] = true
+				seeds = append(seeds, u)
+			}
+		}
+	}
+
+	return seeds, nil
+}
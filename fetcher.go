@@ -0,0 +1,51 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const defaultMaxRedirects = 10
+
+// Fetcher retrieves and parses Goodreads pages. *Client is the built-in
+// implementation; callers can substitute their own, for example to serve
+// pages from an archive.
+type Fetcher interface {
+	FetchBook(ctx context.Context, url string) (*Book, error)
+	FetchList(ctx context.Context, url string) (*List, error)
+}
+
+var _ Fetcher = (*Client)(nil)
+
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+func WithMaxRedirects(n int) ClientOption {
+	return func(c *Client) {
+		c.maxRedirects = n
+	}
+}
+
+// redirectPolicy installs the client's redirect handling on a copy of the
+// underlying http.Client, so a caller-provided client is never mutated and
+// any CheckRedirect it already has is left in charge. Each hop keeps the
+// User-Agent the request started with, even when rotating agents.
+func (c *Client) redirectPolicy() {
+	if c.httpClient.CheckRedirect != nil {
+		return
+	}
+
+	hc := *c.httpClient
+	hc.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= c.maxRedirects {
+			return fmt.Errorf("fetching %s: %w", via[0].URL, ErrTooManyRedirects)
+		}
+
+		req.Header.Set("User-Agent", via[len(via)-1].Header.Get("User-Agent"))
+
+		return nil
+	}
+
+	c.httpClient = &hc
+}
@@ -0,0 +1,82 @@
+package book
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	FieldTitle   = "title"
+	FieldURL     = "url"
+	FieldID      = "id"
+	FieldCover   = "cover_url"
+	FieldAuthors = "authors"
+	FieldGenres  = "genres"
+	FieldRating  = "rating"
+	FieldRatings = "ratings"
+	FieldReviews = "reviews"
+	FieldISBN    = "isbn"
+	FieldDetails = "details"
+	FieldStats   = "stats"
+)
+
+const maxSnippetLen = 80
+
+// ErrMissingField is the Cause of a FieldError for a required field that
+// wasn't on the page at all, reported in strict mode.
+var ErrMissingField = errors.New("missing required field")
+
+// ErrUnexpectedFormat is the Cause of a FieldError whose source text
+// didn't have the shape the parser expects, as opposed to a number that
+// failed to convert.
+var ErrUnexpectedFormat = errors.New("unexpected format")
+
+// FieldError reports a field that was present on the page but couldn't
+// be parsed, or in strict mode a required field that was missing. Snippet
+// is the text it was parsed from, cut to a readable length.
+type FieldError struct {
+	Field   string
+	Cause   error
+	Snippet string
+}
+
+func newFieldError(field string, cause error, source string) *FieldError {
+	return &FieldError{Field: field, Cause: cause, Snippet: truncateRunes(source, maxSnippetLen)}
+}
+
+func (e *FieldError) Error() string {
+	if e.Snippet == "" {
+		return fmt.Sprintf("parsing %s: %v", e.Field, e.Cause)
+	}
+
+	return fmt.Sprintf("parsing %s from %q: %v", e.Field, e.Snippet, e.Cause)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Cause
+}
+
+// FieldErrors picks the FieldErrors out of an error returned by GetBook,
+// which joins one per failed field.
+func FieldErrors(err error) []*FieldError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *FieldError:
+		return []*FieldError{e}
+	case interface{ Unwrap() []error }:
+		fields := []*FieldError{}
+		for _, inner := range e.Unwrap() {
+			fields = append(fields, FieldErrors(inner)...)
+		}
+
+		return fields
+	}
+
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return []*FieldError{fe}
+	}
+
+	return nil
+}
@@ -0,0 +1,391 @@
+package book
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Filter interface {
+	Match(b Book) bool
+	String() string
+}
+
+type FilterSyntaxError struct {
+	Expr string
+	// Pos is where in Expr the error is, counted in runes.
+	Pos int
+	Msg string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("filter %q: position %d: %s", e.Expr, e.Pos, e.Msg)
+}
+
+var filterFields = map[string]func(Book) interface{}{
+	"title":     func(b Book) interface{} { return b.Title },
+	"url":       func(b Book) interface{} { return b.URL },
+	"id":        func(b Book) interface{} { return b.ID },
+	"cover_url": func(b Book) interface{} { return b.CoverUrl },
+	"authors":   func(b Book) interface{} { return b.AuthorNames() },
+	"genres":    func(b Book) interface{} { return b.GenreSlugs() },
+	"rating":    func(b Book) interface{} { return b.Rating },
+	"ratings":   func(b Book) interface{} { return float64(b.Ratings) },
+	"reviews":   func(b Book) interface{} { return float64(b.Reviews) },
+}
+
+var filterOperators = []string{">=", "<=", "!=", ">", "<", "=", "~"}
+
+func ParseFilter(expr string) (Filter, error) {
+	p := &filterParser{expr: expr, tokens: tokenizeFilter(expr)}
+
+	if len(p.tokens) == 0 {
+		return matchAll{}, nil
+	}
+
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, ok := p.peek(); ok {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+
+	return f, nil
+}
+
+func MustParseFilter(expr string) Filter {
+	f, err := ParseFilter(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return f
+}
+
+type filterToken struct {
+	text   string
+	pos    int
+	quoted bool
+}
+
+func tokenizeFilter(expr string) []filterToken {
+	tokens := []filterToken{}
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, filterToken{text: string(r), pos: i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			i++
+
+			var sb strings.Builder
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+
+				sb.WriteRune(runes[i])
+				i++
+			}
+
+			tokens = append(tokens, filterToken{text: sb.String(), pos: start, quoted: true})
+			i++
+		case strings.ContainsRune("<>=!~", r):
+			start := i
+			for i < len(runes) && strings.ContainsRune("<>=!~", runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, filterToken{text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()<>=!~\"'", runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, filterToken{text: string(runes[start:i]), pos: start})
+		}
+	}
+
+	return tokens
+}
+
+type filterParser struct {
+	expr   string
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) next() (filterToken, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return tok, &FilterSyntaxError{Expr: p.expr, Pos: utf8.RuneCountInString(p.expr), Msg: "unexpected end of expression"}
+	}
+
+	p.pos++
+
+	return tok, nil
+}
+
+func (p *filterParser) keyword(word string) bool {
+	tok, ok := p.peek()
+	if ok && !tok.quoted && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *filterParser) errorf(tok filterToken, format string, args ...interface{}) error {
+	return &FilterSyntaxError{Expr: p.expr, Pos: tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orFilter{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andFilter{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (Filter, error) {
+	if p.keyword("NOT") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notFilter{inner}, nil
+	}
+
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok.text == "(" && !tok.quoted {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		closing, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing.text != ")" {
+			return nil, p.errorf(closing, "expected ), got %q", closing.text)
+		}
+
+		return inner, nil
+	}
+
+	return p.parseComparison(tok)
+}
+
+func (p *filterParser) parseComparison(field filterToken) (Filter, error) {
+	name := strings.ToLower(field.text)
+
+	get, ok := filterFields[name]
+	if !ok {
+		return nil, p.errorf(field, "unknown field %q", field.text)
+	}
+
+	opTok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	op := ""
+	for _, candidate := range filterOperators {
+		if opTok.text == candidate {
+			op = candidate
+		}
+	}
+
+	if op == "" || opTok.quoted {
+		return nil, p.errorf(opTok, "expected operator after %s, got %q", field.text, opTok.text)
+	}
+
+	valTok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := comparison{field: name, get: get, op: op, value: valTok.text}
+
+	if _, numeric := get(Book{}).(float64); numeric {
+		if op == "~" {
+			return nil, p.errorf(opTok, "operator ~ is not supported on numeric field %s", name)
+		}
+
+		num, err := strconv.ParseFloat(valTok.text, 64)
+		if err != nil {
+			return nil, p.errorf(valTok, "field %s needs a number, got %q", name, valTok.text)
+		}
+
+		cmp.number = num
+		cmp.numeric = true
+	} else if op != "=" && op != "!=" && op != "~" {
+		return nil, p.errorf(opTok, "operator %s is only supported on numeric fields", op)
+	}
+
+	return cmp, nil
+}
+
+func FilterBooks(books []Book, f Filter) []Book {
+	matched := []Book{}
+
+	for _, b := range books {
+		if f.Match(b) {
+			matched = append(matched, b)
+		}
+	}
+
+	return matched
+}
+
+type matchAll struct{}
+
+func (matchAll) Match(Book) bool { return true }
+func (matchAll) String() string  { return "" }
+
+type andFilter struct{ left, right Filter }
+
+func (f andFilter) Match(b Book) bool { return f.left.Match(b) && f.right.Match(b) }
+func (f andFilter) String() string    { return "(" + f.left.String() + " AND " + f.right.String() + ")" }
+
+type orFilter struct{ left, right Filter }
+
+func (f orFilter) Match(b Book) bool { return f.left.Match(b) || f.right.Match(b) }
+func (f orFilter) String() string    { return "(" + f.left.String() + " OR " + f.right.String() + ")" }
+
+type notFilter struct{ inner Filter }
+
+func (f notFilter) Match(b Book) bool { return !f.inner.Match(b) }
+func (f notFilter) String() string    { return "NOT " + f.inner.String() }
+
+type comparison struct {
+	field   string
+	get     func(Book) interface{}
+	op      string
+	value   string
+	number  float64
+	numeric bool
+}
+
+func (c comparison) String() string {
+	if c.numeric {
+		return c.field + c.op + c.value
+	}
+
+	return c.field + c.op + strconv.Quote(c.value)
+}
+
+func (c comparison) Match(b Book) bool {
+	switch val := c.get(b).(type) {
+	case float64:
+		return compareNumbers(val, c.op, c.number)
+	case string:
+		return c.matchString(val)
+	case []string:
+		if c.op == "!=" {
+			for _, s := range val {
+				if strings.EqualFold(s, c.value) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		for _, s := range val {
+			if c.matchString(s) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c comparison) matchString(s string) bool {
+	switch c.op {
+	case "=":
+		return strings.EqualFold(s, c.value)
+	case "!=":
+		return !strings.EqualFold(s, c.value)
+	case "~":
+		return strings.Contains(strings.ToLower(s), strings.ToLower(c.value))
+	}
+
+	return false
+}
+
+func compareNumbers(a float64, op string, b float64) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	}
+
+	return false
+}
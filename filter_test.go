@@ -0,0 +1,139 @@
+package book_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+func TestParseFilterString(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", ""},
+		{"rating>4", "rating>4"},
+		{"rating>4 OR ratings>10 AND reviews<5", "(rating>4 OR (ratings>10 AND reviews<5))"},
+		{"rating>4 AND ratings>10 OR reviews<5", "((rating>4 AND ratings>10) OR reviews<5)"},
+		{"(rating>4 OR ratings>10) AND reviews<5", "((rating>4 OR ratings>10) AND reviews<5)"},
+		{"NOT rating>4 AND reviews<5", "(NOT rating>4 AND reviews<5)"},
+		{"NOT (rating>4 AND reviews<5)", "NOT (rating>4 AND reviews<5)"},
+		{"NOT NOT rating>4", "NOT NOT rating>4"},
+		{"rating>4 and not genres~horror or Title=Dune", "((rating>4 AND NOT genres~\"horror\") OR title=\"Dune\")"},
+		{`title="The \"Best\" Book"`, `title="The \"Best\" Book"`},
+		{`authors='O\'Brien'`, `authors="O'Brien"`},
+		{`title="back\\slash"`, `title="back\\slash"`},
+		{`title = "AND"`, `title="AND"`},
+		{"rating>=4.5 AND rating<=5 AND ratings!=0 AND reviews=3", "(((rating>=4.5 AND rating<=5) AND ratings!=0) AND reviews=3)"},
+	}
+
+	for _, tt := range tests {
+		f, err := book.ParseFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.expr, err)
+			continue
+		}
+
+		if got := f.String(); got != tt.want {
+			t.Errorf("ParseFilter(%q) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	dune := book.Book{
+		Title:   "Dune",
+		Authors: book.AuthorRefs("Frank Herbert"),
+		Genres:  []book.Genre{"science-fiction", "classics"},
+		Rating:  4.27,
+		Ratings: 1431283,
+		Reviews: 53209,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{"rating>4.2", true},
+		{"rating>=4.27", true},
+		{"rating<4.27", false},
+		{"ratings=1431283", true},
+		{"reviews!=53209", false},
+		{"title=dune", true},
+		{"title!=Dune", false},
+		{"title~UN", true},
+		{`authors="frank herbert"`, true},
+		{"authors~herb", true},
+		{"genres=classics", true},
+		{"genres!=classics", false},
+		{"genres!=horror", true},
+		{"rating>4.5 OR genres~science", true},
+		{"rating>4.5 OR genres~fantasy AND reviews>0", false},
+		{"rating>4.5 OR genres~fantasy OR reviews>0", true},
+		{"NOT rating>4.5 AND title=Dune", true},
+		{"NOT (rating>4 AND title=Dune)", false},
+	}
+
+	for _, tt := range tests {
+		if got := book.MustParseFilter(tt.expr).Match(dune); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"rating>4 rating<5", 9, `unexpected "rating"`},
+		{"rating>4)", 8, `unexpected ")"`},
+		{"rating>", 7, "unexpected end of expression"},
+		{"(rating>4", 9, "unexpected end of expression"},
+		{"NOT", 3, "unexpected end of expression"},
+		{"rating>4 AND", 12, "unexpected end of expression"},
+		{"(rating>4 title=x", 10, `expected ), got "title"`},
+		{"pages>100", 0, `unknown field "pages"`},
+		{"rating 4", 7, `expected operator after rating, got "4"`},
+		{`rating "=" 4`, 7, `expected operator after rating, got "="`},
+		{"rating=>4", 6, `expected operator after rating, got "=>"`},
+		{"rating~4", 6, "operator ~ is not supported on numeric field rating"},
+		{"ratings>many", 8, `field ratings needs a number, got "many"`},
+		{"title>Dune", 5, "operator > is only supported on numeric fields"},
+		{"genres<=x", 6, "operator <= is only supported on numeric fields"},
+		{"title=Émile rating", 12, `unexpected "rating"`},
+		{"title=Émile AND", 15, "unexpected end of expression"},
+	}
+
+	for _, tt := range tests {
+		_, err := book.ParseFilter(tt.expr)
+
+		var syntaxErr *book.FilterSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseFilter(%q) error = %v, want a FilterSyntaxError", tt.expr, err)
+			continue
+		}
+
+		if syntaxErr.Pos != tt.pos || !strings.Contains(syntaxErr.Msg, tt.msg) {
+			t.Errorf("ParseFilter(%q) = position %d: %s; want position %d: %s", tt.expr, syntaxErr.Pos, syntaxErr.Msg, tt.pos, tt.msg)
+		}
+
+		if syntaxErr.Expr != tt.expr {
+			t.Errorf("ParseFilter(%q) error Expr = %q", tt.expr, syntaxErr.Expr)
+		}
+	}
+}
+
+func TestMustParseFilterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParseFilter didn't panic on a bad expression")
+		}
+	}()
+
+	book.MustParseFilter("rating>")
+}
@@ -0,0 +1,158 @@
+package book
+
+import (
+	"container/heap"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type FrontierItem struct {
+	URL      string `json:"url"`
+	Key      string `json:"key"`
+	Priority int    `json:"priority"`
+	Depth    int    `json:"depth"`
+	seq      uint64
+}
+
+// Frontier is the queue of URLs a crawl has yet to visit. URLs are
+// normalised on the way in and deduplicated by book ID, so the different
+// slugs and query strings Goodreads links the same book with are only
+// queued once. Higher priorities come out first; equal priorities come
+// out in insertion order, which makes discovery breadth-first.
+type Frontier struct {
+	base *url.URL
+
+	mu    sync.Mutex
+	queue frontierHeap
+	seen  map[string]bool
+	seq   uint64
+}
+
+// NewFrontier resolves relative URLs against base, which should be the
+// base URL of the client that will fetch them. A nil base means
+// Goodreads.
+func NewFrontier(base *url.URL) *Frontier {
+	if base == nil {
+		base, _ = url.Parse(GoodreadsBaseURL)
+	}
+
+	return &Frontier{
+		base: base,
+		seen: map[string]bool{},
+	}
+}
+
+// Push queues rawURL unless it is invalid or its key has been pushed
+// before. It reports whether the URL was queued.
+func (f *Frontier) Push(rawURL string, priority, depth int) bool {
+	normalized, ok := f.normalize(rawURL)
+	if !ok {
+		return false
+	}
+
+	key := FrontierKey(normalized)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.seen[key] {
+		return false
+	}
+
+	f.seen[key] = true
+	f.seq++
+	heap.Push(&f.queue, FrontierItem{URL: normalized, Key: key, Priority: priority, Depth: depth, seq: f.seq})
+
+	return true
+}
+
+func (f *Frontier) Pop() (FrontierItem, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.queue) == 0 {
+		return FrontierItem{}, false
+	}
+
+	return heap.Pop(&f.queue).(FrontierItem), true
+}
+
+func (f *Frontier) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.queue)
+}
+
+// Seen reports whether rawURL, or another URL for the same book, has
+// already been pushed.
+func (f *Frontier) Seen(rawURL string) bool {
+	normalized, ok := f.normalize(rawURL)
+	if !ok {
+		return false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.seen[FrontierKey(normalized)]
+}
+
+// normalize resolves rawURL against the frontier's base, lower-cases the host and
+// drops the fragment. Book URLs also lose their query string, which only
+// carries tracking parameters such as from_search.
+func (f *Frontier) normalize(rawURL string) (string, bool) {
+	ref, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", false
+	}
+
+	u := f.base.ResolveReference(ref)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", false
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+
+	if strings.Contains(u.Path, BookURLIndicator) {
+		u.RawQuery = ""
+	}
+
+	return u.String(), true
+}
+
+// FrontierKey is the identity a frontier deduplicates on: "book:" plus the
+// ID for book pages, and the URL itself for anything else.
+func FrontierKey(normalizedURL string) string {
+	if id := bookIDFromURL(normalizedURL); id != "" {
+		return "book:" + id
+	}
+
+	return normalizedURL
+}
+
+type frontierHeap []FrontierItem
+
+func (h frontierHeap) Len() int { return len(h) }
+
+func (h frontierHeap) Less(i, j int) bool {
+	if h[i].Priority != h[j].Priority {
+		return h[i].Priority > h[j].Priority
+	}
+
+	return h[i].seq < h[j].seq
+}
+
+func (h frontierHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *frontierHeap) Push(x interface{}) { *h = append(*h, x.(FrontierItem)) }
+
+func (h *frontierHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return item
+}
@@ -0,0 +1,102 @@
+package book
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrSignInRequired = errors.New("goodreads requires signing in to view this page")
+	ErrAgeGated       = errors.New("goodreads requires age verification to view this page")
+)
+
+// signInPaths are where Goodreads, and the Amazon login behind it, send a
+// request for a page that needs an account.
+var signInPaths = []string{
+	"/user/sign_in",
+	"/user/sign_up",
+	"/ap/signin",
+}
+
+var signInTitles = [][]byte{
+	[]byte("sign in"),
+	[]byte("sign up"),
+	[]byte("log in"),
+}
+
+// ageGateMarkers are the interstitial's element ids and classes, which
+// don't turn up in ordinary page text.
+var ageGateMarkers = [][]byte{
+	[]byte("agegate"),
+	[]byte("age-gate"),
+	[]byte("age_gate"),
+	[]byte("adultcontentwarning"),
+}
+
+var ageGateTitles = [][]byte{
+	[]byte("age verification"),
+	[]byte("verify your age"),
+	[]byte("confirm your age"),
+	[]byte("adult content"),
+}
+
+// GatedError reports a sign-in wall or age check served in place of the
+// page asked for. It matches ErrSignInRequired or ErrAgeGated with
+// errors.Is. Unlike a BlockedError, waiting won't help: the page needs a
+// session, such as one set up with WithSessionCookies.
+type GatedError struct {
+	URL    string
+	Reason string
+	Gate   error
+}
+
+func (e *GatedError) Error() string {
+	if e.URL == "" {
+		return e.Gate.Error()
+	}
+
+	return fmt.Sprintf("fetching %s: %v", e.URL, e.Gate)
+}
+
+func (e *GatedError) Is(target error) bool {
+	return target == e.Gate
+}
+
+// DetectGate reports whether body is a sign-in wall or age-verification
+// interstitial. finalURL is where any redirects ended, or empty when only
+// the body is at hand; body may be nil to check the redirect alone.
+func DetectGate(finalURL string, body []byte) (*GatedError, bool) {
+	if u, err := url.Parse(finalURL); err == nil {
+		for _, path := range signInPaths {
+			if strings.HasPrefix(u.Path, path) {
+				return &GatedError{Reason: "redirected to " + path, Gate: ErrSignInRequired}, true
+			}
+		}
+	}
+
+	lower := bytes.ToLower(body)
+	title := pageTitle(lower)
+
+	for _, marker := range ageGateMarkers {
+		if bytes.Contains(lower, marker) {
+			return &GatedError{Reason: "age verification page", Gate: ErrAgeGated}, true
+		}
+	}
+
+	for _, marker := range ageGateTitles {
+		if bytes.Contains(title, marker) {
+			return &GatedError{Reason: "age verification page", Gate: ErrAgeGated}, true
+		}
+	}
+
+	for _, marker := range signInTitles {
+		if bytes.Contains(title, marker) {
+			return &GatedError{Reason: "sign-in page", Gate: ErrSignInRequired}, true
+		}
+	}
+
+	return nil, false
+}
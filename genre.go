@@ -0,0 +1,127 @@
+package book
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	GenreHeaderIndicator      = "genreHeader"
+	GenreDescriptionIndicator = "reviewText"
+	GenreShelfIndicator       = "coverBigBox"
+	GenreNewReleasesIndicator = "/new_releases/"
+	GenreMostReadIndicator    = "/most_read/"
+)
+
+// Genre is a Goodreads genre, held as its URL slug such as
+// "science-fiction". It is a string underneath, so it encodes exactly as
+// the slugs did before.
+type Genre string
+
+func (g Genre) Slug() string {
+	return string(g)
+}
+
+// Name is a display name derived from the slug, e.g. "Science Fiction".
+func (g Genre) Name() string {
+	words := strings.FieldsFunc(string(g), func(r rune) bool { return r == '-' || r == '_' })
+
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + word[size:]
+	}
+
+	return strings.Join(words, " ")
+}
+
+func (g Genre) URL() string {
+	return GoodreadsBaseURL + "/genres/" + url.PathEscape(string(g))
+}
+
+type GenrePage struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	NewReleases []BookRef `json:"new_releases"`
+	MostRead    []BookRef `json:"most_read"`
+}
+
+func GetGenre(r io.Reader) (*GenrePage, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	genre := &GenrePage{
+		NewReleases: []BookRef{},
+		MostRead:    []BookRef{},
+	}
+
+	if header := findFirst(doc, byClass("div", GenreHeaderIndicator)); header != nil {
+		if h1 := findFirst(header, func(n *html.Node) bool { return isElement(n, "h1") }); h1 != nil {
+			genre.Name = textContent(h1)
+		}
+	}
+
+	if desc := findFirst(doc, byClass("div", GenreDescriptionIndicator)); desc != nil {
+		genre.Description = textContent(desc)
+	}
+
+	for _, box := range findAll(doc, byClass("div", GenreShelfIndicator)) {
+		heading := findFirst(box, func(n *html.Node) bool { return isElement(n, "h2") })
+		if heading == nil {
+			continue
+		}
+
+		link := findFirst(heading, func(n *html.Node) bool { return isElement(n, "a") })
+		if link == nil {
+			continue
+		}
+
+		href := getAttr(link, "href")
+
+		switch {
+		case strings.Contains(href, GenreNewReleasesIndicator):
+			genre.NewReleases = append(genre.NewReleases, extractCoverRefs(box)...)
+		case strings.Contains(href, GenreMostReadIndicator):
+			genre.MostRead = append(genre.MostRead, extractCoverRefs(box)...)
+		}
+	}
+
+	return genre, nil
+}
+
+func extractCoverRefs(n *html.Node) []BookRef {
+	refs := []BookRef{}
+	seen := map[string]bool{}
+
+	links := findAll(n, func(n *html.Node) bool {
+		return isElement(n, "a") && strings.Contains(getAttr(n, "href"), BookURLIndicator)
+	})
+
+	for _, a := range links {
+		url := getAttr(a, "href")
+		if seen[url] {
+			continue
+		}
+
+		img := findFirst(a, func(n *html.Node) bool { return isElement(n, "img") })
+		if img == nil {
+			continue
+		}
+
+		seen[url] = true
+		refs = append(refs, BookRef{
+			Title:    getAttr(img, "alt"),
+			URL:      url,
+			ID:       bookIDFromURL(url),
+			CoverUrl: getAttr(img, "src"),
+		})
+	}
+
+	return refs
+}
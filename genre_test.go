@@ -0,0 +1,24 @@
+package book_test
+
+import (
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+func TestGenreName(t *testing.T) {
+	tests := map[book.Genre]string{
+		"science-fiction": "Science Fiction",
+		"young_adult":     "Young Adult",
+		"émigré-fiction":  "Émigré Fiction",
+		"ужасы":           "Ужасы",
+		"漫画":              "漫画",
+		"":                "",
+	}
+
+	for genre, want := range tests {
+		if got := genre.Name(); got != want {
+			t.Errorf("Genre(%q).Name() = %q, want %q", genre, got, want)
+		}
+	}
+}
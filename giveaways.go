@@ -0,0 +1,95 @@
+package book
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	GiveawaysURLIndicator = "/giveaway"
+	GiveawayIndicator     = "giveawayListItem"
+	GiveawayURLIndicator  = "/giveaway/show/"
+)
+
+var (
+	giveawayCopiesPattern  = regexp.MustCompile(`(?i)([\d,]+)\s+cop(?:y|ies)`)
+	giveawayEntriesPattern = regexp.MustCompile(`(?i)([\d,]+)\s+(?:people requested|entrants|entries)`)
+	giveawayEndsPattern    = regexp.MustCompile(`(?i)ends\s+(?:on\s+)?([A-Z][a-z]+\.?\s+\d{1,2}(?:st|nd|rd|th)?,?\s+\d{4})`)
+	giveawayFormatPattern  = regexp.MustCompile(`(?i)\b(kindle|ebook|print|audiobook|hardcover|paperback)\b`)
+)
+
+type Giveaway struct {
+	Book    BookRef `json:"book"`
+	URL     string  `json:"url"`
+	ID      string  `json:"id"`
+	Format  string  `json:"format"`
+	Copies  int     `json:"copies"`
+	Entries int     `json:"entries"`
+	Ends    string  `json:"ends"`
+}
+
+type GiveawaysPage struct {
+	Giveaways []Giveaway `json:"giveaways"`
+	NextPage  string     `json:"next_page,omitempty"`
+}
+
+func GetGiveaways(r io.Reader) (*GiveawaysPage, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &GiveawaysPage{
+		Giveaways: []Giveaway{},
+		NextPage:  extractNextPage(doc),
+	}
+
+	for _, item := range findAll(doc, byClass("", GiveawayIndicator)) {
+		giveaway, ok := extractGiveaway(item)
+		if ok {
+			page.Giveaways = append(page.Giveaways, giveaway)
+		}
+	}
+
+	return page, nil
+}
+
+func extractGiveaway(item *html.Node) (Giveaway, bool) {
+	giveaway := Giveaway{}
+
+	refs := extractSectionRefs(item)
+	if len(refs) == 0 {
+		return giveaway, false
+	}
+
+	giveaway.Book = refs[0]
+
+	link := findFirst(item, func(n *html.Node) bool {
+		return isElement(n, "a") && strings.Contains(getAttr(n, "href"), GiveawayURLIndicator)
+	})
+	if link != nil {
+		giveaway.URL = getAttr(link, "href")
+		giveaway.ID = leadingDigits(lastPathSegment(giveaway.URL))
+	}
+
+	for _, author := range findAll(item, byClass("a", BookRefAuthorIndicator)) {
+		giveaway.Book.Authors = append(giveaway.Book.Authors, textContent(author))
+	}
+
+	text := textContent(item)
+	giveaway.Copies = matchCount(giveawayCopiesPattern, text)
+	giveaway.Entries = matchCount(giveawayEntriesPattern, text)
+
+	if m := giveawayEndsPattern.FindStringSubmatch(text); m != nil {
+		giveaway.Ends = m[1]
+	}
+
+	if m := giveawayFormatPattern.FindStringSubmatch(text); m != nil {
+		giveaway.Format = strings.ToLower(m[1])
+	}
+
+	return giveaway, true
+}
@@ -0,0 +1,109 @@
+package book
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GoodreadsCSVColumns are the columns of the library export Goodreads
+// offers under My Books, in its order, which its importer and the tools
+// built around that file expect.
+var GoodreadsCSVColumns = []string{
+	"Book Id", "Title", "Author", "Author l-f", "Additional Authors",
+	"ISBN", "ISBN13", "My Rating", "Average Rating", "Publisher", "Binding",
+	"Number of Pages", "Year Published", "Original Publication Year",
+	"Date Read", "Date Added", "Bookshelves", "Bookshelves with positions",
+	"Exclusive Shelf", "My Review", "Spoiler", "Private Notes", "Read Count",
+	"Owned Copies",
+}
+
+// GoodreadsTable shapes books like the Goodreads library export. The
+// personal columns, such as My Rating and the shelves, are left at the
+// values the export uses for a book nobody has shelved.
+func GoodreadsTable(books []Book) Table {
+	table := Table{Name: "goodreads_library_export", Columns: GoodreadsCSVColumns}
+
+	for _, b := range books {
+		table.Rows = append(table.Rows, goodreadsRow(b))
+	}
+
+	return table
+}
+
+func WriteGoodreadsCSV(w io.Writer, books []Book) error {
+	return GoodreadsTable(books).WriteCSV(w)
+}
+
+func goodreadsRow(b Book) []string {
+	names := b.AuthorNames()
+
+	author, sortName, additional := "", "", ""
+	if len(names) > 0 {
+		author, sortName, additional = names[0], b.Authors[0].SortName(), strings.Join(names[1:], ", ")
+	}
+
+	isbn10, isbn13 := "", ""
+	switch len(b.ISBN) {
+	case 10:
+		isbn10 = b.ISBN
+	case 13:
+		isbn13 = b.ISBN
+	}
+
+	var publisher, binding, pages, published, firstPublished string
+
+	if d := b.Details; d != nil {
+		if d.ISBN10 != "" {
+			isbn10 = d.ISBN10
+		}
+
+		if d.ISBN13 != "" {
+			isbn13 = d.ISBN13
+		}
+
+		publisher, binding = d.Publisher, d.Format
+
+		if d.Pages > 0 {
+			pages = strconv.Itoa(d.Pages)
+		}
+
+		published = publicationYear(d.Published)
+		firstPublished = publicationYear(d.FirstPublished)
+	}
+
+	return []string{
+		bookIDFromURL(b.URL),
+		b.Title,
+		author,
+		sortName,
+		additional,
+		excelText(isbn10),
+		excelText(isbn13),
+		"0",
+		strconv.FormatFloat(b.Rating, 'f', 2, 64),
+		publisher,
+		binding,
+		pages,
+		published,
+		firstPublished,
+		"", "", "", "", "", "", "", "",
+		"0",
+		"0",
+	}
+}
+
+// excelText wraps an ISBN as ="…", as the export does, so spreadsheets
+// keep its leading zeros.
+func excelText(s string) string {
+	return `="` + s + `"`
+}
+
+func publicationYear(date string) string {
+	d, err := ParseDate(date)
+	if err != nil {
+		return ""
+	}
+
+	return strconv.Itoa(d.Time.Year())
+}
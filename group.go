@@ -0,0 +1,96 @@
+package book
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	GroupURLIndicator              = "/group/show/"
+	GroupMembersIndicator          = "/group/members/"
+	GroupDescriptionIndicator      = "groupDescription"
+	GroupCurrentlyReadingIndicator = "currentlyReading"
+	GroupBookshelfIndicator        = "groupBookshelf"
+)
+
+type Group struct {
+	Name             string    `json:"name"`
+	URL              string    `json:"url"`
+	ID               string    `json:"id"`
+	Members          int       `json:"members"`
+	Description      string    `json:"description"`
+	CurrentlyReading []BookRef `json:"currently_reading"`
+	Bookshelf        []BookRef `json:"bookshelf"`
+}
+
+func GetGroup(r io.Reader) (*Group, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &Group{
+		CurrentlyReading: []BookRef{},
+		Bookshelf:        []BookRef{},
+	}
+
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		group.URL = getAttr(link, "href")
+		group.ID = leadingDigits(lastPathSegment(group.URL))
+	}
+
+	if h1 := findFirst(doc, func(n *html.Node) bool { return isElement(n, "h1") }); h1 != nil {
+		group.Name = textContent(h1)
+	}
+
+	members := findFirst(doc, func(n *html.Node) bool {
+		return isElement(n, "a") && strings.Contains(getAttr(n, "href"), GroupMembersIndicator) && parseCount(textContent(n)) > 0
+	})
+	if members != nil {
+		group.Members = parseCount(textContent(members))
+	}
+
+	if desc := findFirst(doc, byClass("div", GroupDescriptionIndicator)); desc != nil {
+		group.Description = extractFreeText(desc)
+	}
+
+	if section := findFirst(doc, byAttr("div", "id", GroupCurrentlyReadingIndicator)); section != nil {
+		group.CurrentlyReading = extractSectionRefs(section)
+	}
+
+	if section := findFirst(doc, byAttr("div", "id", GroupBookshelfIndicator)); section != nil {
+		group.Bookshelf = extractSectionRefs(section)
+	}
+
+	return group, nil
+}
+
+func extractSectionRefs(section *html.Node) []BookRef {
+	refs := extractCoverRefs(section)
+	seen := map[string]int{}
+
+	for i, ref := range refs {
+		seen[ref.URL] = i
+
+		if title, author, ok := strings.Cut(ref.Title, " by "); ok {
+			refs[i].Title = title
+			refs[i].Authors = []string{author}
+		}
+	}
+
+	for _, a := range findAll(section, byClass("a", BookRefTitleIndicator)) {
+		url := getAttr(a, "href")
+
+		if i, ok := seen[url]; ok {
+			refs[i].Title = textContent(a)
+			continue
+		}
+
+		seen[url] = len(refs)
+		refs = append(refs, BookRef{Title: textContent(a), URL: url, ID: bookIDFromURL(url)})
+	}
+
+	return refs
+}
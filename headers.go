@@ -0,0 +1,82 @@
+package book
+
+import (
+	"context"
+	"net/http"
+)
+
+func WithUserAgent(ua string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithUserAgents rotates requests through a pool of User-Agent strings.
+// The first one is also the agent matched against robots.txt.
+func WithUserAgents(uas ...string) ClientOption {
+	return func(c *Client) {
+		c.userAgents = uas
+
+		if len(uas) > 0 {
+			c.userAgent = uas[0]
+		}
+	}
+}
+
+func WithAcceptLanguage(lang string) ClientOption {
+	return WithHeader("Accept-Language", lang)
+}
+
+// WithHeader sets a header on every request the client sends.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+
+		c.headers.Set(key, value)
+	}
+}
+
+type requestHeadersKey struct{}
+
+// RequestHeaders returns a context whose requests carry h in addition to
+// the client's headers, overriding them where both set the same key.
+func RequestHeaders(ctx context.Context, h http.Header) context.Context {
+	merged := http.Header{}
+
+	if prev, ok := ctx.Value(requestHeadersKey{}).(http.Header); ok {
+		for key, vals := range prev {
+			merged[key] = vals
+		}
+	}
+
+	for key, vals := range h {
+		merged[http.CanonicalHeaderKey(key)] = vals
+	}
+
+	return context.WithValue(ctx, requestHeadersKey{}, merged)
+}
+
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", c.nextUserAgent())
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+
+	for key, vals := range c.headers {
+		req.Header[key] = vals
+	}
+
+	if h, ok := req.Context().Value(requestHeadersKey{}).(http.Header); ok {
+		for key, vals := range h {
+			req.Header[key] = vals
+		}
+	}
+}
+
+func (c *Client) nextUserAgent() string {
+	if len(c.userAgents) == 0 {
+		return c.userAgent
+	}
+
+	return c.userAgents[(c.uaNext.Add(1)-1)%uint64(len(c.userAgents))]
+}
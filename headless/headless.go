@@ -0,0 +1,149 @@
+// Package headless renders Goodreads pages in headless Chrome, for the
+// sections a plain fetch can't see. It implements book.Renderer:
+//
+//	r, err := headless.New()
+//	...
+//	defer r.Close()
+//	client := book.NewClient(book.WithRenderer(r, nil))
+package headless
+
+import (
+	"context"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultWaitSelector is present once a book page has hydrated its
+	// rating statistics.
+	DefaultWaitSelector = ".RatingStatistics__rating"
+)
+
+type Renderer struct {
+	allocCancel context.CancelFunc
+	browserCtx  context.Context
+	cancel      context.CancelFunc
+	timeout     time.Duration
+	waitFor     string
+}
+
+type Option func(*config)
+
+type config struct {
+	allocOpts []chromedp.ExecAllocatorOption
+	timeout   time.Duration
+	waitFor   string
+	userAgent string
+}
+
+// WithTimeout caps how long a single page may take to render.
+func WithTimeout(d time.Duration) Option {
+	return func(cfg *config) {
+		cfg.timeout = d
+	}
+}
+
+// WithWaitSelector makes Render wait until sel is in the DOM before
+// reading it. An empty selector waits only for the page load event.
+func WithWaitSelector(sel string) Option {
+	return func(cfg *config) {
+		cfg.waitFor = sel
+	}
+}
+
+func WithUserAgent(ua string) Option {
+	return func(cfg *config) {
+		cfg.userAgent = ua
+	}
+}
+
+// WithAllocatorOptions passes extra flags to the Chrome process, e.g.
+// chromedp.ExecPath to pick the binary.
+func WithAllocatorOptions(opts ...chromedp.ExecAllocatorOption) Option {
+	return func(cfg *config) {
+		cfg.allocOpts = append(cfg.allocOpts, opts...)
+	}
+}
+
+// New starts a headless Chrome that lives until Close. Each Render opens
+// its own tab in it, so a Renderer is safe for concurrent use.
+func New(opts ...Option) (*Renderer, error) {
+	cfg := &config{
+		timeout: DefaultTimeout,
+		waitFor: DefaultWaitSelector,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	allocOpts := append([]chromedp.ExecAllocatorOption{}, chromedp.DefaultExecAllocatorOptions[:]...)
+	if cfg.userAgent != "" {
+		allocOpts = append(allocOpts, chromedp.UserAgent(cfg.userAgent))
+	}
+
+	allocOpts = append(allocOpts, cfg.allocOpts...)
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), allocOpts...)
+	browserCtx, cancel := chromedp.NewContext(allocCtx)
+
+	// Running an empty task list starts the browser, so a missing Chrome
+	// shows up here rather than on the first Render.
+	if err := chromedp.Run(browserCtx); err != nil {
+		cancel()
+		allocCancel()
+		return nil, err
+	}
+
+	return &Renderer{
+		allocCancel: allocCancel,
+		browserCtx:  browserCtx,
+		cancel:      cancel,
+		timeout:     cfg.timeout,
+		waitFor:     cfg.waitFor,
+	}, nil
+}
+
+// Render loads url in a new tab and returns the rendered document. If the
+// wait selector never appears the page is returned as it stands when the
+// timeout runs out, since a page without ratings still has the rest.
+func (r *Renderer) Render(ctx context.Context, url string) ([]byte, error) {
+	tabCtx, cancelTab := chromedp.NewContext(r.browserCtx)
+	defer cancelTab()
+
+	// Tie the tab to the caller's context as well as the browser's.
+	stop := context.AfterFunc(ctx, cancelTab)
+	defer stop()
+
+	if err := chromedp.Run(tabCtx, chromedp.Navigate(url)); err != nil {
+		return nil, err
+	}
+
+	if r.waitFor != "" {
+		waitCtx, cancelWait := context.WithTimeout(tabCtx, r.timeout)
+		chromedp.Run(waitCtx, chromedp.WaitReady(r.waitFor, chromedp.ByQuery))
+		cancelWait()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var html string
+	if err := chromedp.Run(tabCtx, chromedp.OuterHTML("html", &html, chromedp.ByQuery)); err != nil {
+		return nil, err
+	}
+
+	return []byte(html), nil
+}
+
+// Close shuts the browser down.
+func (r *Renderer) Close() error {
+	r.cancel()
+	r.allocCancel()
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package book
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hostLimits holds the per-host throttling policies. A host uses the
+// limiter registered for it or its closest parent domain; failing that,
+// a bucket made on first use from the per-host default; failing that, the
+// client's global limiter.
+type hostLimits struct {
+	mu       sync.Mutex
+	limiters map[string]*RateLimiter
+	auto     func() *RateLimiter
+	made     map[string]*RateLimiter
+}
+
+// WithHostRateLimiter throttles requests to host, and its subdomains, with
+// l instead of the client's global limiter. Registering "goodreads.com"
+// covers www.goodreads.com too; a more specific host wins.
+func WithHostRateLimiter(host string, l *RateLimiter) ClientOption {
+	return func(c *Client) {
+		limits := c.hostLimitsConfig()
+		limits.limiters[normalizeHost(host)] = l
+	}
+}
+
+func WithHostRateLimit(host string, rps float64, burst int, jitter time.Duration) ClientOption {
+	return WithHostRateLimiter(host, NewRateLimiter(rps, burst, jitter))
+}
+
+// WithPerHostRateLimit gives every host without a policy of its own a
+// separate bucket with these settings, so a slow source never eats into
+// another's budget.
+func WithPerHostRateLimit(rps float64, burst int, jitter time.Duration) ClientOption {
+	return func(c *Client) {
+		limits := c.hostLimitsConfig()
+		limits.auto = func() *RateLimiter { return NewRateLimiter(rps, burst, jitter) }
+	}
+}
+
+func (c *Client) hostLimitsConfig() *hostLimits {
+	if c.hostLimits == nil {
+		c.hostLimits = &hostLimits{
+			limiters: map[string]*RateLimiter{},
+			made:     map[string]*RateLimiter{},
+		}
+	}
+
+	return c.hostLimits
+}
+
+// limiterFor picks the limiter that throttles requests to target.
+func (c *Client) limiterFor(target string) *RateLimiter {
+	if c.hostLimits == nil {
+		return c.limiter
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return c.limiter
+	}
+
+	return c.hostLimits.lookup(normalizeHost(u.Host), c.limiter)
+}
+
+func (h *hostLimits) lookup(host string, fallback *RateLimiter) *RateLimiter {
+	for domain := host; domain != ""; {
+		if l, ok := h.limiters[domain]; ok {
+			return l
+		}
+
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+
+		domain = parent
+	}
+
+	if h.auto == nil {
+		return fallback
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	l, ok := h.made[host]
+	if !ok {
+		l = h.auto()
+		h.made[host] = l
+	}
+
+	return l
+}
+
+// normalizeHost lower-cases host and drops any port, so "Goodreads.com:443"
+// and "goodreads.com" share a policy.
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
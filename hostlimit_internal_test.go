@@ -0,0 +1,85 @@
+package book
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterForHost(t *testing.T) {
+	global := NewRateLimiter(1, 1, 0)
+	goodreads := NewRateLimiter(2, 1, 0)
+	images := NewRateLimiter(5, 1, 0)
+
+	c := NewClient(
+		WithRateLimiter(global),
+		WithHostRateLimiter("Goodreads.com", goodreads),
+		WithHostRateLimiter("images.goodreads.com", images),
+	)
+
+	tests := []struct {
+		target string
+		want   *RateLimiter
+	}{
+		{"https://goodreads.com/book/show/1", goodreads},
+		{"https://www.goodreads.com/book/show/1", goodreads},
+		{"https://WWW.GOODREADS.COM:443/book/show/1", goodreads},
+		{"https://images.goodreads.com/covers/1.jpg", images},
+		{"https://cdn.images.goodreads.com/covers/1.jpg", images},
+		{"https://openlibrary.org/isbn/1", global},
+		{"https://notgoodreads.com/", global},
+	}
+
+	for _, tt := range tests {
+		if got := c.limiterFor(tt.target); got != tt.want {
+			t.Errorf("limiterFor(%q) = %p, want %p", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestPerHostRateLimit(t *testing.T) {
+	goodreads := NewRateLimiter(2, 1, 0)
+
+	c := NewClient(
+		WithHostRateLimiter("goodreads.com", goodreads),
+		WithPerHostRateLimit(1, 1, time.Millisecond),
+	)
+
+	if got := c.limiterFor("https://www.goodreads.com/"); got != goodreads {
+		t.Error("a registered host didn't keep its own limiter")
+	}
+
+	openLibrary := c.limiterFor("https://openlibrary.org/isbn/1")
+	amazon := c.limiterFor("https://www.amazon.com/dp/1")
+
+	if openLibrary == nil || amazon == nil || openLibrary == amazon {
+		t.Fatalf("unregistered hosts share a limiter or have none: %p, %p", openLibrary, amazon)
+	}
+
+	if again := c.limiterFor("https://OpenLibrary.org:443/works/1"); again != openLibrary {
+		t.Error("the same host got a second limiter")
+	}
+}
+
+func TestLimiterForWithoutHostPolicies(t *testing.T) {
+	global := NewRateLimiter(1, 1, 0)
+	c := NewClient(WithRateLimiter(global))
+
+	if got := c.limiterFor("https://openlibrary.org/"); got != global {
+		t.Errorf("limiterFor = %p, want the global limiter %p", got, global)
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	tests := map[string]string{
+		"Goodreads.com":         "goodreads.com",
+		"www.goodreads.com:443": "www.goodreads.com",
+		"goodreads.com.":        "goodreads.com",
+		"[::1]:8080":            "::1",
+	}
+
+	for host, want := range tests {
+		if got := normalizeHost(host); got != want {
+			t.Errorf("normalizeHost(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
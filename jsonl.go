@@ -0,0 +1,132 @@
+package book
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// BookIter yields books one at a time until it runs out or yield returns
+// false. It has the shape of iter.Seq[*Book], so on Go 1.23 and later it
+// can be ranged over directly.
+type BookIter func(yield func(*Book) bool)
+
+// IterBooks iterates over a slice already in memory.
+func IterBooks(books []Book) BookIter {
+	return func(yield func(*Book) bool) {
+		for i := range books {
+			if !yield(&books[i]) {
+				return
+			}
+		}
+	}
+}
+
+// JSONLWriter writes one Book per line, the layout OpenJSONL indexes.
+type JSONLWriter struct {
+	w *bufio.Writer
+}
+
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{w: bufio.NewWriterSize(w, 1<<16)}
+}
+
+func (jw *JSONLWriter) Write(b *Book) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	if _, err := jw.w.Write(data); err != nil {
+		return err
+	}
+
+	return jw.w.WriteByte('\n')
+}
+
+// Flush writes out anything buffered. Call it once the last Book is
+// written.
+func (jw *JSONLWriter) Flush() error {
+	return jw.w.Flush()
+}
+
+// WriteJSONL streams books to w as JSON Lines without holding more than
+// one in memory, and returns how many it wrote.
+func WriteJSONL(w io.Writer, books BookIter) (int, error) {
+	jw := NewJSONLWriter(w)
+	n := 0
+
+	var err error
+	books(func(b *Book) bool {
+		if err = jw.Write(b); err != nil {
+			return false
+		}
+
+		n++
+
+		return true
+	})
+
+	if err != nil {
+		return n, err
+	}
+
+	return n, jw.Flush()
+}
+
+// JSONLReader reads back what a JSONLWriter wrote, one Book at a time.
+// Blank lines are skipped.
+type JSONLReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	return &JSONLReader{r: bufio.NewReaderSize(r, 1<<16)}
+}
+
+// Next returns the next Book, or io.EOF once there are none left.
+func (jr *JSONLReader) Next() (*Book, error) {
+	for {
+		line, err := jr.r.ReadBytes('\n')
+		if len(line) > 0 {
+			jr.line++
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			b := &Book{}
+			if jsonErr := json.Unmarshal(trimmed, b); jsonErr != nil {
+				return nil, fmt.Errorf("decoding line %d: %w", jr.line, jsonErr)
+			}
+
+			return b, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ReadJSONL calls fn for each Book in r, stopping at the first error
+// either returns.
+func ReadJSONL(r io.Reader, fn func(*Book) error) error {
+	jr := NewJSONLReader(r)
+
+	for {
+		b, err := jr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package book
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	SchemaOrgContext = "https://schema.org"
+	JSONLDScriptType = "application/ld+json"
+)
+
+var jsonLDSource = `script[type="` + JSONLDScriptType + `"]`
+
+// JSONLD renders b as a schema.org Book object, ready to be encoded as
+// JSON-LD.
+func (b Book) JSONLD() map[string]interface{} {
+	doc := map[string]interface{}{
+		"@context": SchemaOrgContext,
+		"@type":    "Book",
+		"name":     b.Title,
+	}
+
+	if b.URL != "" {
+		doc["url"] = b.URL
+	}
+
+	if b.ID != "" {
+		doc["identifier"] = b.ID
+	}
+
+	if b.CoverUrl != "" {
+		doc["image"] = b.CoverUrl
+	}
+
+	if b.ISBN != "" {
+		doc["isbn"] = b.ISBN
+	}
+
+	if len(b.Authors) > 0 {
+		authors := make([]map[string]interface{}, len(b.Authors))
+		for i, author := range b.Authors {
+			authors[i] = map[string]interface{}{
+				"@type": "Person",
+				"name":  author.Name(),
+			}
+
+			if author.ID() != "" {
+				authors[i]["url"] = author.URL()
+			}
+		}
+
+		doc["author"] = authors
+	}
+
+	if len(b.Genres) > 0 {
+		genres := make([]string, len(b.Genres))
+		for i, genre := range b.Genres {
+			genres[i] = genre.Name()
+		}
+
+		doc["genre"] = genres
+	}
+
+	if b.Ratings > 0 {
+		doc["aggregateRating"] = map[string]interface{}{
+			"@type":       "AggregateRating",
+			"ratingValue": b.Rating,
+			"ratingCount": b.Ratings,
+			"reviewCount": b.Reviews,
+		}
+	}
+
+	return doc
+}
+
+// jsonLDBook is the part of a schema.org Book that book pages embed.
+// Values are loosely typed because publishers, Goodreads included, write
+// numbers as strings and single authors without the array.
+type jsonLDBook struct {
+	Type            jsonLDStrings   `json:"@type"`
+	Graph           json.RawMessage `json:"@graph"`
+	Name            string          `json:"name"`
+	URL             string          `json:"url"`
+	Image           jsonLDStrings   `json:"image"`
+	ISBN            string          `json:"isbn"`
+	Author          jsonLDPeople    `json:"author"`
+	AggregateRating *struct {
+		RatingValue jsonLDNumber `json:"ratingValue"`
+		RatingCount jsonLDNumber `json:"ratingCount"`
+		ReviewCount jsonLDNumber `json:"reviewCount"`
+	} `json:"aggregateRating"`
+}
+
+type jsonLDPerson struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// jsonLDPeople accepts a single person or an array of them.
+type jsonLDPeople []jsonLDPerson
+
+func (p *jsonLDPeople) UnmarshalJSON(data []byte) error {
+	var many []jsonLDPerson
+	if err := json.Unmarshal(data, &many); err == nil {
+		*p = many
+		return nil
+	}
+
+	var one jsonLDPerson
+	if err := json.Unmarshal(data, &one); err != nil {
+		return nil
+	}
+
+	*p = jsonLDPeople{one}
+	return nil
+}
+
+// jsonLDStrings accepts a string or an array of strings.
+type jsonLDStrings []string
+
+func (s *jsonLDStrings) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*s = jsonLDStrings{one}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return nil
+	}
+
+	*s = many
+	return nil
+}
+
+// jsonLDNumber accepts a number or a numeric string.
+type jsonLDNumber float64
+
+func (n *jsonLDNumber) UnmarshalJSON(data []byte) error {
+	var f float64
+	if err := json.Unmarshal(data, &f); err == nil {
+		*n = jsonLDNumber(f)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+
+	f, err := parseLocaleFloat(s)
+	if err != nil {
+		return nil
+	}
+
+	*n = jsonLDNumber(f)
+	return nil
+}
+
+func (s jsonLDStrings) has(val string) bool {
+	for _, v := range s {
+		if v == val {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findJSONLDBook returns the first schema.org Book among the page's JSON-LD
+// blocks, looking inside arrays and @graph containers.
+func findJSONLDBook(doc *html.Node) *jsonLDBook {
+	for _, script := range findAll(doc, byAttr("script", "type", JSONLDScriptType)) {
+		if b := decodeJSONLDBook([]byte(rawText(script))); b != nil {
+			return b
+		}
+	}
+
+	return nil
+}
+
+func decodeJSONLDBook(data []byte) *jsonLDBook {
+	data = bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(data, []byte("[")) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil
+		}
+
+		for _, item := range items {
+			if b := decodeJSONLDBook(item); b != nil {
+				return b
+			}
+		}
+
+		return nil
+	}
+
+	b := &jsonLDBook{}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil
+	}
+
+	if len(b.Graph) > 0 {
+		if inner := decodeJSONLDBook(b.Graph); inner != nil {
+			return inner
+		}
+	}
+
+	if !b.Type.has("Book") {
+		return nil
+	}
+
+	return b
+}
+
+// fieldFiller decides whether a fallback source may set a field: always
+// when the field is still empty, and over an existing value only when
+// the source is preferred. It records the source of every field it lets
+// through.
+type fieldFiller struct {
+	sources map[string]string
+	source  string
+	prefer  bool
+}
+
+func (f fieldFiller) take(field string, empty bool) bool {
+	if !empty && !f.prefer {
+		return false
+	}
+
+	f.sources[field] = f.source
+	return true
+}
+
+// applyJSONLD fills book from the page's JSON-LD block. Fields already
+// found are kept unless WithPreferJSONLD is set.
+func applyJSONLD(doc *html.Node, book *Book, cfg *parseConfig, sources map[string]string) {
+	ld := findJSONLDBook(doc)
+	if ld == nil {
+		return
+	}
+
+	fill := fieldFiller{sources: sources, source: jsonLDSource, prefer: cfg.preferJSONLD}
+
+	if name := strings.TrimSpace(html.UnescapeString(ld.Name)); name != "" && fill.take(FieldTitle, book.Title == "") {
+		book.Title = name
+	}
+
+	if ld.URL != "" && strings.Contains(ld.URL, BookURLIndicator) && fill.take(FieldURL, book.URL == "") {
+		book.URL = ld.URL
+	}
+
+	if len(ld.Image) > 0 && ld.Image[0] != "" && fill.take(FieldCover, book.CoverUrl == "") {
+		book.CoverUrl = ld.Image[0]
+	}
+
+	if ld.ISBN != "" && fill.take(FieldISBN, book.ISBN == "") {
+		book.ISBN = ld.ISBN
+	}
+
+	if len(ld.Author) > 0 && fill.take(FieldAuthors, len(book.Authors) == 0) {
+		book.Authors = []AuthorRef{}
+
+		for _, p := range ld.Author {
+			name := strings.TrimSpace(html.UnescapeString(p.Name))
+			if name == "" {
+				continue
+			}
+
+			id := ""
+			if strings.Contains(p.URL, AuthorURLIndicator) {
+				id = leadingDigits(lastPathSegment(p.URL))
+			}
+
+			book.Authors = append(book.Authors, NewAuthorRef(name, id))
+		}
+	}
+
+	if r := ld.AggregateRating; r != nil {
+		if r.RatingValue != 0 && fill.take(FieldRating, book.Rating == 0) {
+			book.Rating = float64(r.RatingValue)
+		}
+
+		if r.RatingCount != 0 && fill.take(FieldRatings, book.Ratings == 0) {
+			book.Ratings = int(r.RatingCount)
+		}
+
+		if r.ReviewCount != 0 && fill.take(FieldReviews, book.Reviews == 0) {
+			book.Reviews = int(r.ReviewCount)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package book
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	JSONLIndexSuffix = ".idx"
+	jsonlIndexHeader = "# book jsonl index size="
+	jsonlIndexMTime  = " mtime="
+)
+
+var ErrNotFound = errors.New("book: not found")
+
+// JSONLIndex maps book IDs to line offsets. Size and ModTime are the
+// file's when it was indexed, so a file changed since can be told apart
+// even when its size didn't change.
+type JSONLIndex struct {
+	Size    int64
+	ModTime time.Time
+	Offsets map[string]int64
+}
+
+type JSONLFile struct {
+	f     *os.File
+	index *JSONLIndex
+}
+
+// OpenJSONL opens a JSON Lines file for lookups by ID, using the index
+// beside it if it is still current and rebuilding it otherwise. When the
+// index can't be written, such as in a read-only directory, the rebuilt
+// one is kept in memory only.
+func OpenJSONL(path string) (*JSONLFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	index, err := LoadJSONLIndex(path + JSONLIndexSuffix)
+	if err != nil || !index.current(info) {
+		index, err = buildJSONLIndex(path)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		index.save(path + JSONLIndexSuffix)
+	}
+
+	return &JSONLFile{f: f, index: index}, nil
+}
+
+func (index *JSONLIndex) current(info os.FileInfo) bool {
+	return index.Size == info.Size() && index.ModTime.Equal(info.ModTime())
+}
+
+func (j *JSONLFile) Get(id string) (*Book, error) {
+	offset, ok := j.index.Offsets[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(j.f, offset, math.MaxInt64-offset))
+
+	line, err := reader.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	book := &Book{}
+	if err := json.Unmarshal(line, book); err != nil {
+		return nil, fmt.Errorf("decoding record at offset %d: %w", offset, err)
+	}
+
+	return book, nil
+}
+
+func (j *JSONLFile) IDs() []string {
+	ids := make([]string, 0, len(j.index.Offsets))
+	for id := range j.index.Offsets {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func (j *JSONLFile) Close() error {
+	return j.f.Close()
+}
+
+// BuildJSONLIndex indexes the file at path and writes the index beside
+// it.
+func BuildJSONLIndex(path string) (*JSONLIndex, error) {
+	index, err := buildJSONLIndex(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := index.save(path + JSONLIndexSuffix); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func buildJSONLIndex(path string) (*JSONLIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The modification time is taken before reading, so a write made
+	// while indexing leaves the index stale rather than wrongly current.
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	index := &JSONLIndex{ModTime: info.ModTime(), Offsets: map[string]int64{}}
+	reader := bufio.NewReaderSize(f, 1<<20)
+	offset := int64(0)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var record struct {
+				ID string `json:"id"`
+			}
+
+			if jsonErr := json.Unmarshal(line, &record); jsonErr == nil && record.ID != "" {
+				index.Offsets[record.ID] = offset
+			}
+
+			offset += int64(len(line))
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	index.Size = offset
+
+	return index, nil
+}
+
+func LoadJSONLIndex(path string) (*JSONLIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), jsonlIndexHeader) {
+		return nil, fmt.Errorf("%s: missing index header", path)
+	}
+
+	// Indexes written before the modification time was recorded have
+	// none, and so never match the file.
+	sizeStr, mtimeStr, _ := strings.Cut(strings.TrimPrefix(scanner.Text(), jsonlIndexHeader), jsonlIndexMTime)
+
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	index := &JSONLIndex{Size: size, Offsets: map[string]int64{}}
+
+	if mtimeStr != "" {
+		nanos, err := strconv.ParseInt(mtimeStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		index.ModTime = time.Unix(0, nanos)
+	}
+
+	for scanner.Scan() {
+		id, offsetStr, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			return nil, fmt.Errorf("%s: malformed index line %q", path, scanner.Text())
+		}
+
+		offset, err := strconv.ParseInt(offsetStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		index.Offsets[id] = offset
+	}
+
+	return index, scanner.Err()
+}
+
+func (index *JSONLIndex) save(path string) error {
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "%s%d%s%d\n", jsonlIndexHeader, index.Size, jsonlIndexMTime, index.ModTime.UnixNano())
+
+	for id, offset := range index.Offsets {
+		fmt.Fprintf(w, "%s\t%d\n", id, offset)
+	}
+
+	err = w.Flush()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+
+	if err != nil {
+		os.Remove(tmp)
+	}
+
+	return err
+}
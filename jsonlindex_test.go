@@ -0,0 +1,67 @@
+package book_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dchooyc/book"
+)
+
+func TestOpenJSONLRebuildsAfterSameSizeRewrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "books.jsonl")
+
+	if err := os.WriteFile(path, []byte(`{"id":"1","title":"A"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := book.OpenJSONL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if err := os.WriteFile(path, []byte(`{"id":"2","title":"B"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = book.OpenJSONL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if b, err := f.Get("2"); err != nil || b.Title != "B" {
+		t.Errorf("Get(2) = %+v, %v; want the rewritten book", b, err)
+	}
+}
+
+func TestOpenJSONLWithoutWritableIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "books.jsonl")
+
+	if err := os.WriteFile(path, []byte(`{"id":"1","title":"A"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory where the index belongs makes saving it fail, as a
+	// read-only directory would.
+	if err := os.Mkdir(path+book.JSONLIndexSuffix, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := book.OpenJSONL(path)
+	if err != nil {
+		t.Fatalf("OpenJSONL: %v", err)
+	}
+	defer f.Close()
+
+	if b, err := f.Get("1"); err != nil || b.Title != "A" {
+		t.Errorf("Get(1) = %+v, %v; want the book from the in-memory index", b, err)
+	}
+}
@@ -0,0 +1,180 @@
+package book
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Markers of the book page layout Goodreads served before its 2022
+// redesign, still found in caches and archives.
+const (
+	LegacyTitleID             = "bookTitle"
+	LegacyAuthorsID           = "bookAuthors"
+	LegacyAuthorNameIndicator = "authorName"
+	LegacyCoverID             = "coverImage"
+	LegacyGenreIndicator      = "bookPageGenreLink"
+	LegacyEditionsIndicator   = "/work/editions/"
+)
+
+type BookLayout string
+
+const (
+	LayoutModern BookLayout = "modern"
+	LayoutLegacy BookLayout = "legacy"
+	LayoutMobile BookLayout = "mobile"
+)
+
+// detectBookLayout sniffs which markup a book page uses. Anything that
+// isn't recognisably another layout is treated as modern, the layout
+// GetBook has always parsed.
+func detectBookLayout(doc *html.Node, cfg *parseConfig) BookLayout {
+	if findFirst(doc, byAttr("h1", "data-testid", cfg.selectors.TitleTestID)) != nil {
+		return LayoutModern
+	}
+
+	if isMobilePage(doc) {
+		return LayoutMobile
+	}
+
+	if findFirst(doc, byAttr("h1", "id", LegacyTitleID)) != nil || findFirst(doc, byAttr("div", "id", LegacyAuthorsID)) != nil {
+		return LayoutLegacy
+	}
+
+	return LayoutModern
+}
+
+// extractLegacyBook maps the pre-2022 layout onto Book. That markup
+// carried schema.org microdata, which the counts and rating are read
+// from.
+func extractLegacyBook(doc *html.Node, book *Book, cfg *parseConfig, sources map[string]string, errs *[]error) {
+	found := func(field, source string) {
+		sources[field] = source
+	}
+
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		if href := getAttr(link, "href"); strings.Contains(href, BookURLIndicator) {
+			book.URL = href
+			found(FieldURL, `link[rel=canonical]@href`)
+		}
+	}
+
+	work := findFirst(doc, func(n *html.Node) bool {
+		href := getAttr(n, "href")
+		return isElement(n, "a") && (strings.Contains(href, BookIDIndicator) || strings.Contains(href, LegacyEditionsIndicator))
+	})
+	if work != nil {
+		if id := leadingDigits(lastPathSegment(getAttr(work, "href"))); id != "" {
+			book.ID = id
+			found(FieldID, `a[href*="`+BookIDIndicator+`"]@href`)
+		}
+	}
+
+	if h1 := findFirst(doc, byAttr("h1", "id", LegacyTitleID)); h1 != nil {
+		if title := textContent(h1); title != "" {
+			book.Title = title
+			found(FieldTitle, `h1#`+LegacyTitleID)
+		}
+	}
+
+	if img := findFirst(doc, byAttr("img", "id", LegacyCoverID)); img != nil {
+		if src := getAttr(img, "src"); src != "" {
+			book.CoverUrl = src
+			found(FieldCover, `img#`+LegacyCoverID+`@src`)
+		}
+	}
+
+	if container := findFirst(doc, byAttr("div", "id", LegacyAuthorsID)); container != nil {
+		authors := []AuthorRef{}
+
+		for _, a := range findAll(container, byClass("a", LegacyAuthorNameIndicator)) {
+			name := textContent(a)
+			if span := findFirst(a, byAttr("span", "itemprop", "name")); span != nil {
+				name = textContent(span)
+			}
+
+			if name == "" {
+				continue
+			}
+
+			authors = append(authors, NewAuthorRef(name, leadingDigits(lastPathSegment(getAttr(a, "href")))))
+		}
+
+		if len(authors) > 0 {
+			book.Authors = authors
+			found(FieldAuthors, `div#`+LegacyAuthorsID+` a.`+LegacyAuthorNameIndicator)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, a := range findAll(doc, byClass("a", LegacyGenreIndicator)) {
+		href := getAttr(a, "href")
+		if !strings.Contains(href, BookGenresIndicator) {
+			continue
+		}
+
+		// Nested genres ("Fiction > Fantasy") link each level, so the same
+		// genre can come up more than once.
+		slug := lastPathSegment(href)
+		if seen[slug] || cfg.excludesGenre(slug) {
+			continue
+		}
+
+		seen[slug] = true
+		book.Genres = append(book.Genres, Genre(slug))
+		found(FieldGenres, `a.`+LegacyGenreIndicator+`@href`)
+	}
+
+	extractMicrodata(doc, book, sources, errs)
+}
+
+// extractMicrodata reads the rating, counts and ISBN from schema.org
+// microdata, which the legacy and mobile layouts both carry. Fields that
+// are already set are left alone.
+func extractMicrodata(doc *html.Node, book *Book, sources map[string]string, errs *[]error) {
+	found := func(field, source string) {
+		sources[field] = source
+	}
+
+	if n := findFirst(doc, byAttr("", "itemprop", "ratingValue")); n != nil && book.Rating == 0 {
+		text := textContent(n)
+
+		if val, err := parseLocaleFloat(text); err != nil {
+			*errs = append(*errs, newFieldError(FieldRating, err, text))
+		} else {
+			book.Rating = val
+			found(FieldRating, `[itemprop=ratingValue]`)
+		}
+	}
+
+	for _, count := range []struct {
+		field, prop string
+		dst         *int
+	}{
+		{FieldRatings, "ratingCount", &book.Ratings},
+		{FieldReviews, "reviewCount", &book.Reviews},
+	} {
+		n := findFirst(doc, byAttr("meta", "itemprop", count.prop))
+		if n == nil || *count.dst != 0 {
+			continue
+		}
+
+		content := getAttr(n, "content")
+
+		val, err := parseLocaleInt(content)
+		if err != nil {
+			*errs = append(*errs, newFieldError(count.field, err, content))
+			continue
+		}
+
+		*count.dst = val
+		found(count.field, `meta[itemprop=`+count.prop+`]@content`)
+	}
+
+	if n := findFirst(doc, byAttr("", "itemprop", "isbn")); n != nil && book.ISBN == "" {
+		if isbn := textContent(n); isbn != "" {
+			book.ISBN = isbn
+			found(FieldISBN, `[itemprop=isbn]`)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package book
+
+import (
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	ListURLIndicator   = "/list/show/"
+	ListTitleIndicator = "gr-h1"
+)
+
+type List struct {
+	Title    string    `json:"title"`
+	URL      string    `json:"url"`
+	ID       string    `json:"id"`
+	Books    []BookRef `json:"books"`
+	NextPage string    `json:"next_page,omitempty"`
+}
+
+func GetList(r io.Reader) (*List, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &List{
+		Books:    extractBookRefRows(doc),
+		NextPage: extractNextPage(doc),
+	}
+
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		list.URL = getAttr(link, "href")
+		list.ID = leadingDigits(lastPathSegment(list.URL))
+	}
+
+	if h1 := findFirst(doc, byClass("h1", ListTitleIndicator)); h1 != nil {
+		list.Title = textContent(h1)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,333 @@
+// Package marc turns books into MARC 21 bibliographic records, as ISO 2709
+// transmission files or MARCXML, for loading into library systems.
+package marc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dchooyc/book"
+)
+
+const (
+	fieldTerminator  = 0x1e
+	recordTerminator = 0x1d
+	subfieldDelim    = 0x1f
+
+	leaderLen = 24
+
+	// SlimNamespace is the MARCXML schema namespace.
+	SlimNamespace = "http://www.loc.gov/MARC21/slim"
+)
+
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+// Record is a MARC 21 bibliographic record. Leader positions holding
+// lengths and addresses are filled in when the record is written.
+type Record struct {
+	Leader  string
+	Control []ControlField
+	Data    []DataField
+}
+
+// FromBook maps a Book onto a record for a language-material monograph:
+// 001 Goodreads ID, 020 ISBNs, 100/700 authors, 245 title, 264
+// publication, 300 extent, 520 description, 650 genres as uncontrolled
+// subjects and 856 the Goodreads page. The encoding level is "unknown",
+// so an ILS will treat it as copy to be upgraded; the 008 fixed field is
+// left for the ILS to generate.
+func FromBook(b book.Book) Record {
+	r := Record{Leader: "00000nam a2200000uu 4500"}
+
+	if id := b.ID; id != "" {
+		r.Control = append(r.Control, ControlField{Tag: "001", Value: id})
+	}
+
+	var d book.BookDetails
+	if b.Details != nil {
+		d = *b.Details
+	}
+
+	for _, isbn := range uniqueISBNs(d.ISBN13, d.ISBN10, b.ISBN) {
+		r.Data = append(r.Data, DataField{Tag: "020", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{'a', isbn}}})
+	}
+
+	for i, author := range b.Authors {
+		tag := "700"
+		if i == 0 {
+			tag = "100"
+		}
+
+		r.Data = append(r.Data, DataField{Tag: tag, Ind1: '1', Ind2: ' ', Subfields: []Subfield{{'a', author.SortName()}}})
+	}
+
+	r.Data = append(r.Data, titleField(b.Title, len(b.Authors) > 0))
+
+	if pub := publicationField(d); pub != nil {
+		r.Data = append(r.Data, *pub)
+	}
+
+	if d.Pages > 0 {
+		r.Data = append(r.Data, DataField{Tag: "300", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{'a', strconv.Itoa(d.Pages) + " pages"}}})
+	}
+
+	if d.Description != "" {
+		r.Data = append(r.Data, DataField{Tag: "520", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{'a', d.Description}}})
+	}
+
+	for _, genre := range b.Genres {
+		r.Data = append(r.Data, DataField{Tag: "650", Ind1: ' ', Ind2: '4', Subfields: []Subfield{{'a', subjectHeading(string(genre))}}})
+	}
+
+	if b.URL != "" {
+		r.Data = append(r.Data, DataField{Tag: "856", Ind1: '4', Ind2: '2', Subfields: []Subfield{
+			{'3', "Goodreads"},
+			{'u', b.URL},
+		}})
+	}
+
+	return r
+}
+
+// titleField builds 245 with the second indicator counting the leading
+// article a library files the title under.
+func titleField(title string, hasMainEntry bool) DataField {
+	ind1 := byte('0')
+	if hasMainEntry {
+		ind1 = '1'
+	}
+
+	nonfiling := 0
+	for _, article := range []string{"The ", "An ", "A "} {
+		if strings.HasPrefix(title, article) {
+			nonfiling = len(article)
+			break
+		}
+	}
+
+	main, sub, ok := strings.Cut(title, ": ")
+	if !ok {
+		return DataField{Tag: "245", Ind1: ind1, Ind2: byte('0' + nonfiling), Subfields: []Subfield{{'a', title}}}
+	}
+
+	return DataField{Tag: "245", Ind1: ind1, Ind2: byte('0' + nonfiling), Subfields: []Subfield{
+		{'a', main + " :"},
+		{'b', sub},
+	}}
+}
+
+func publicationField(d book.BookDetails) *DataField {
+	subfields := []Subfield{}
+
+	if d.Publisher != "" {
+		subfields = append(subfields, Subfield{'b', d.Publisher})
+	}
+
+	if date, err := book.ParseDate(d.Published); err == nil {
+		subfields = append(subfields, Subfield{'c', strconv.Itoa(date.Time.Year())})
+	}
+
+	if len(subfields) == 0 {
+		return nil
+	}
+
+	return &DataField{Tag: "264", Ind1: ' ', Ind2: '1', Subfields: subfields}
+}
+
+// subjectHeading turns a genre slug such as "science-fiction" into
+// "Science fiction".
+func subjectHeading(slug string) string {
+	words := strings.Fields(strings.ReplaceAll(slug, "-", " "))
+	if len(words) == 0 {
+		return slug
+	}
+
+	heading := strings.Join(words, " ")
+	r, size := utf8.DecodeRuneInString(heading)
+
+	return string(unicode.ToUpper(r)) + heading[size:]
+}
+
+func uniqueISBNs(isbns ...string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+
+	for _, isbn := range isbns {
+		if isbn != "" && !seen[isbn] {
+			seen[isbn] = true
+			out = append(out, isbn)
+		}
+	}
+
+	return out
+}
+
+// MarshalBinary writes the record in ISO 2709, the exchange format
+// library systems import as .mrc files.
+func (r Record) MarshalBinary() ([]byte, error) {
+	var dir, data bytes.Buffer
+
+	add := func(tag string, field []byte) error {
+		if len(tag) != 3 {
+			return fmt.Errorf("marc: tag %q is not three characters", tag)
+		}
+
+		field = append(field, fieldTerminator)
+		if len(field) > 9999 {
+			return fmt.Errorf("marc: field %s is %d bytes, over the 9999 limit", tag, len(field))
+		}
+
+		fmt.Fprintf(&dir, "%s%04d%05d", tag, len(field), data.Len())
+		data.Write(field)
+
+		return nil
+	}
+
+	for _, cf := range r.Control {
+		if err := add(cf.Tag, []byte(cf.Value)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, df := range r.Data {
+		field := []byte{df.Ind1, df.Ind2}
+		for _, sf := range df.Subfields {
+			field = append(field, subfieldDelim, sf.Code)
+			field = append(field, sf.Value...)
+		}
+
+		if err := add(df.Tag, field); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(r.Leader) != leaderLen {
+		return nil, fmt.Errorf("marc: leader is %d characters, not %d", len(r.Leader), leaderLen)
+	}
+
+	dir.WriteByte(fieldTerminator)
+
+	base := leaderLen + dir.Len()
+	length := base + data.Len() + 1
+	if length > 99999 {
+		return nil, fmt.Errorf("marc: record is %d bytes, over the 99999 limit", length)
+	}
+
+	leader := []byte(r.Leader)
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	out := make([]byte, 0, length)
+	out = append(out, leader...)
+	out = append(out, dir.Bytes()...)
+	out = append(out, data.Bytes()...)
+	out = append(out, recordTerminator)
+
+	return out, nil
+}
+
+// Encode writes books to w as an ISO 2709 file, one record each.
+func Encode(w io.Writer, books []book.Book) error {
+	for _, b := range books {
+		data, err := FromBook(b).MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("%s: %w", b.URL, err)
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type xmlCollection struct {
+	XMLName xml.Name    `xml:"collection"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Records []xmlRecord `xml:"record"`
+}
+
+type xmlRecord struct {
+	Leader  string            `xml:"leader"`
+	Control []xmlControlField `xml:"controlfield"`
+	Data    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// EncodeXML writes books to w as a MARCXML collection.
+func EncodeXML(w io.Writer, books []book.Book) error {
+	collection := xmlCollection{Xmlns: SlimNamespace}
+
+	for _, b := range books {
+		r := FromBook(b)
+		xr := xmlRecord{Leader: r.Leader}
+
+		for _, cf := range r.Control {
+			xr.Control = append(xr.Control, xmlControlField{Tag: cf.Tag, Value: cf.Value})
+		}
+
+		for _, df := range r.Data {
+			xd := xmlDataField{Tag: df.Tag, Ind1: string(df.Ind1), Ind2: string(df.Ind2)}
+			for _, sf := range df.Subfields {
+				xd.Subfields = append(xd.Subfields, xmlSubfield{Code: string(sf.Code), Value: sf.Value})
+			}
+
+			xr.Data = append(xr.Data, xd)
+		}
+
+		collection.Records = append(collection.Records, xr)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(collection); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
@@ -0,0 +1,18 @@
+package marc
+
+import "testing"
+
+func TestSubjectHeading(t *testing.T) {
+	tests := map[string]string{
+		"science-fiction": "Science fiction",
+		"émigré-fiction":  "Émigré fiction",
+		"ужасы":           "Ужасы",
+		"":                "",
+	}
+
+	for slug, want := range tests {
+		if got := subjectHeading(slug); got != want {
+			t.Errorf("subjectHeading(%q) = %q, want %q", slug, got, want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package book
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MarkdownLayout selects how WriteMarkdown lays books out.
+type MarkdownLayout int
+
+const (
+	// MarkdownSections gives each book a heading followed by its cover,
+	// rating, genres and description.
+	MarkdownSections MarkdownLayout = iota
+
+	// MarkdownTable puts one book per row of a single table, leaving out
+	// the description.
+	MarkdownTable
+)
+
+type markdownConfig struct {
+	layout           MarkdownLayout
+	title            string
+	descriptionLimit int
+	covers           bool
+}
+
+type MarkdownOption func(*markdownConfig)
+
+func WithMarkdownLayout(layout MarkdownLayout) MarkdownOption {
+	return func(c *markdownConfig) {
+		c.layout = layout
+	}
+}
+
+// WithMarkdownTitle starts the document with a top-level heading.
+func WithMarkdownTitle(title string) MarkdownOption {
+	return func(c *markdownConfig) {
+		c.title = title
+	}
+}
+
+// WithMarkdownDescriptionLimit cuts descriptions to at most n characters.
+// Zero, the default, keeps them whole.
+func WithMarkdownDescriptionLimit(n int) MarkdownOption {
+	return func(c *markdownConfig) {
+		c.descriptionLimit = n
+	}
+}
+
+// WithoutMarkdownCovers leaves out the cover images, for renderers that
+// would otherwise hotlink every one.
+func WithoutMarkdownCovers() MarkdownOption {
+	return func(c *markdownConfig) {
+		c.covers = false
+	}
+}
+
+// WriteMarkdown renders books as a Markdown document, as sections or as a
+// table, for reading lists in wikis and READMEs. Text taken from the page
+// is escaped so titles and descriptions can't break the markup.
+func WriteMarkdown(w io.Writer, books []Book, opts ...MarkdownOption) error {
+	cfg := markdownConfig{covers: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	if cfg.title != "" {
+		bw.WriteString("# " + markdownEscape(cfg.title) + "\n\n")
+	}
+
+	switch cfg.layout {
+	case MarkdownTable:
+		writeMarkdownTable(bw, books, cfg)
+	default:
+		for _, b := range books {
+			writeMarkdownSection(bw, b, cfg)
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeMarkdownSection(w *bufio.Writer, b Book, cfg markdownConfig) {
+	w.WriteString("## " + markdownLink(b.Title, b.URL) + "\n\n")
+
+	if authors := b.AuthorNames(); len(authors) > 0 {
+		w.WriteString("*by " + markdownEscape(strings.Join(authors, ", ")) + "*\n\n")
+	}
+
+	if cfg.covers && b.CoverUrl != "" {
+		w.WriteString("![Cover of " + markdownEscape(b.Title) + "](" + b.CoverUrl + ")\n\n")
+	}
+
+	facts := []string{}
+
+	if b.Rating > 0 {
+		facts = append(facts, "**Rating:** "+markdownRating(b))
+	}
+
+	if len(b.Genres) > 0 {
+		facts = append(facts, "**Genres:** "+markdownGenres(b))
+	}
+
+	if d := b.Details; d != nil && d.Pages > 0 {
+		facts = append(facts, "**Pages:** "+strconv.Itoa(d.Pages))
+	}
+
+	if year := publicationYear(markdownPublished(b)); year != "" {
+		facts = append(facts, "**Published:** "+year)
+	}
+
+	if len(facts) > 0 {
+		w.WriteString("- " + strings.Join(facts, "\n- ") + "\n\n")
+	}
+
+	if d := b.Details; d != nil && d.Description != "" {
+		description := d.Description
+		if cfg.descriptionLimit > 0 {
+			description = truncateRunes(description, cfg.descriptionLimit)
+		}
+
+		w.WriteString(markdownEscape(description) + "\n\n")
+	}
+}
+
+func writeMarkdownTable(w *bufio.Writer, books []Book, cfg markdownConfig) {
+	header := []string{"Title", "Author", "Rating", "Genres"}
+	if cfg.covers {
+		header = append([]string{"Cover"}, header...)
+	}
+
+	w.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	w.WriteString(strings.Repeat("| --- ", len(header)) + "|\n")
+
+	for _, b := range books {
+		cells := []string{
+			markdownLink(b.Title, b.URL),
+			markdownEscape(strings.Join(b.AuthorNames(), ", ")),
+			markdownRating(b),
+			markdownGenres(b),
+		}
+
+		if cfg.covers {
+			cover := ""
+			if b.CoverUrl != "" {
+				cover = `<img src="` + b.CoverUrl + `" alt="" width="50">`
+			}
+
+			cells = append([]string{cover}, cells...)
+		}
+
+		w.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	w.WriteString("\n")
+}
+
+func markdownPublished(b Book) string {
+	if b.Details == nil {
+		return ""
+	}
+
+	if b.Details.FirstPublished != "" {
+		return b.Details.FirstPublished
+	}
+
+	return b.Details.Published
+}
+
+func markdownRating(b Book) string {
+	if b.Rating == 0 {
+		return ""
+	}
+
+	s := "★ " + strconv.FormatFloat(b.Rating, 'f', 2, 64)
+	if b.Ratings > 0 {
+		s += " (" + groupThousands(b.Ratings) + " ratings)"
+	}
+
+	return s
+}
+
+func markdownGenres(b Book) string {
+	names := make([]string, len(b.Genres))
+	for i, genre := range b.Genres {
+		names[i] = markdownEscape(genre.Name())
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func markdownLink(text, url string) string {
+	if url == "" {
+		return markdownEscape(text)
+	}
+
+	return "[" + markdownEscape(text) + "](" + url + ")"
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "#", `\#`,
+)
+
+// markdownEscape escapes the characters that start emphasis, links, HTML
+// and table cells, and folds newlines so a value stays on one line.
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
+
+func groupThousands(n int) string {
+	if n < 0 {
+		return "-" + groupThousands(-n)
+	}
+
+	s := strconv.Itoa(n)
+
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+
+	return s
+}
@@ -0,0 +1,51 @@
+package book
+
+import "time"
+
+type CacheStatus string
+
+const (
+	CacheMiss        CacheStatus = "miss"
+	CacheHit         CacheStatus = "hit"
+	CacheRevalidated CacheStatus = "revalidated"
+)
+
+// RequestMetrics describes one Client.Get, however many HTTP requests it
+// took. Latency is the time spent in those requests and Wait the time
+// spent held back by rate limits and pauses, so Duration minus both is
+// mostly backoff between retries. Bytes is the decoded body size.
+type RequestMetrics struct {
+	URL        string
+	StatusCode int
+	Cache      CacheStatus
+	Attempts   int
+	Bytes      int
+	Duration   time.Duration
+	Latency    time.Duration
+	Wait       time.Duration
+	Err        error
+}
+
+// Retries is the number of requests made after the first.
+func (m RequestMetrics) Retries() int {
+	if m.Attempts < 2 {
+		return 0
+	}
+
+	return m.Attempts - 1
+}
+
+// WithMetricsHandler calls fn after every Get, including cache hits and
+// failures. fn runs on the fetching goroutine, so it should be quick; a
+// Client shared by crawl workers calls it concurrently.
+func WithMetricsHandler(fn func(RequestMetrics)) ClientOption {
+	return func(c *Client) {
+		c.onMetrics = fn
+	}
+}
+
+// BytesFetched is the total size of the response bodies the Client has
+// downloaded, after decoding. Cache hits don't count.
+func (c *Client) BytesFetched() int64 {
+	return c.fetched.Load()
+}
@@ -0,0 +1,188 @@
+package book
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Markers of the layout served to mobile user agents on m.goodreads.com.
+const (
+	MobileHost             = "m.goodreads.com"
+	MobileBookIndicator    = "mobileBookPage"
+	MobileTitleIndicator   = "bookTitle"
+	MobileAuthorIndicator  = "authorName"
+	MobileCoverIndicator   = "bookCover"
+	MobileRatingIndicator  = "average"
+	MobileRatingsIndicator = "votes"
+	MobileReviewsIndicator = "count"
+)
+
+// isMobilePage reports whether doc is a mobile page: it either says it
+// lives on the mobile host or carries the mobile book page wrapper.
+func isMobilePage(doc *html.Node) bool {
+	if findFirst(doc, byClass("", MobileBookIndicator)) != nil {
+		return true
+	}
+
+	for _, n := range []*html.Node{
+		findFirst(doc, byAttr("link", "rel", "canonical")),
+		findFirst(doc, byAttr("meta", "property", "og:url")),
+	} {
+		if n == nil {
+			continue
+		}
+
+		ref := getAttr(n, "href")
+		if ref == "" {
+			ref = getAttr(n, "content")
+		}
+
+		if u, err := url.Parse(ref); err == nil && strings.EqualFold(u.Hostname(), MobileHost) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// desktopURL moves a mobile book URL onto the main host, so the same book
+// fetched either way has one URL.
+func desktopURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Hostname(), MobileHost) {
+		return rawURL
+	}
+
+	base, _ := url.Parse(GoodreadsBaseURL)
+	u.Scheme, u.Host = base.Scheme, base.Host
+
+	return u.String()
+}
+
+func extractMobileBook(doc *html.Node, book *Book, cfg *parseConfig, sources map[string]string, errs *[]error) {
+	found := func(field, source string) {
+		sources[field] = source
+	}
+
+	// The mobile class names are generic, so look for them inside the
+	// book wrapper when the page has one.
+	page := findFirst(doc, byClass("", MobileBookIndicator))
+	if page == nil {
+		page = doc
+	}
+
+	for _, n := range []*html.Node{
+		findFirst(doc, byAttr("link", "rel", "canonical")),
+		findFirst(doc, byAttr("meta", "property", "og:url")),
+	} {
+		if n == nil {
+			continue
+		}
+
+		ref := getAttr(n, "href") + getAttr(n, "content")
+		if strings.Contains(ref, BookURLIndicator) {
+			book.URL = desktopURL(ref)
+			found(FieldURL, `link[rel=canonical]@href`)
+			break
+		}
+	}
+
+	work := findFirst(doc, func(n *html.Node) bool {
+		href := getAttr(n, "href")
+		return isElement(n, "a") && (strings.Contains(href, BookIDIndicator) || strings.Contains(href, LegacyEditionsIndicator))
+	})
+	if work != nil {
+		if id := leadingDigits(lastPathSegment(getAttr(work, "href"))); id != "" {
+			book.ID = id
+			found(FieldID, `a[href*="`+BookIDIndicator+`"]@href`)
+		}
+	}
+
+	if n := findFirst(page, byClass("", MobileTitleIndicator)); n != nil {
+		if title := textContent(n); title != "" {
+			book.Title = title
+			found(FieldTitle, `.`+MobileTitleIndicator)
+		}
+	}
+
+	if img := findFirst(page, byClass("img", MobileCoverIndicator)); img != nil {
+		if src := getAttr(img, "src"); src != "" {
+			book.CoverUrl = src
+			found(FieldCover, `img.`+MobileCoverIndicator+`@src`)
+		}
+	}
+
+	authors := []AuthorRef{}
+	for _, a := range findAll(page, byClass("a", MobileAuthorIndicator)) {
+		if name := textContent(a); name != "" {
+			authors = append(authors, NewAuthorRef(name, leadingDigits(lastPathSegment(getAttr(a, "href")))))
+		}
+	}
+
+	if len(authors) > 0 {
+		book.Authors = authors
+		found(FieldAuthors, `a.`+MobileAuthorIndicator)
+	}
+
+	seen := map[string]bool{}
+	for _, a := range findAll(page, func(n *html.Node) bool { return isElement(n, "a") }) {
+		href := getAttr(a, "href")
+		if !strings.Contains(href, BookGenresIndicator) {
+			continue
+		}
+
+		slug := lastPathSegment(href)
+		if slug == "" || seen[slug] || cfg.excludesGenre(slug) {
+			continue
+		}
+
+		seen[slug] = true
+		book.Genres = append(book.Genres, Genre(slug))
+		found(FieldGenres, `a[href*="`+BookGenresIndicator+`"]@href`)
+	}
+
+	if n := findFirst(page, byClass("", MobileRatingIndicator)); n != nil {
+		text := textContent(n)
+
+		if val, err := parseLocaleFloat(text); err != nil {
+			*errs = append(*errs, newFieldError(FieldRating, err, text))
+		} else {
+			book.Rating = val
+			found(FieldRating, `.`+MobileRatingIndicator)
+		}
+	}
+
+	for _, count := range []struct {
+		field, class string
+		dst          *int
+	}{
+		{FieldRatings, MobileRatingsIndicator, &book.Ratings},
+		{FieldReviews, MobileReviewsIndicator, &book.Reviews},
+	} {
+		n := findFirst(page, byClass("", count.class))
+		if n == nil {
+			continue
+		}
+
+		text := textContent(n)
+
+		nums := localeNumbers(text)
+		if len(nums) == 0 {
+			*errs = append(*errs, newFieldError(count.field, ErrUnexpectedFormat, text))
+			continue
+		}
+
+		val, err := parseLocaleInt(nums[0])
+		if err != nil {
+			*errs = append(*errs, newFieldError(count.field, err, text))
+			continue
+		}
+
+		*count.dst = val
+		found(count.field, `.`+count.class)
+	}
+
+	extractMicrodata(doc, book, sources, errs)
+}
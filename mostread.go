@@ -0,0 +1,66 @@
+package book
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+
+	"golang.org/x/net/html"
+)
+
+const MostReadURLIndicator = "/book/most_read"
+
+var readersPattern = regexp.MustCompile(`(?i)([\d,.]+)\s+(?:people\s+)?(?:readers|read this week|shelved)`)
+
+type MostReadPage struct {
+	Country string          `json:"country"`
+	Entries []MostReadEntry `json:"entries"`
+}
+
+type MostReadEntry struct {
+	BookRef
+	Rank    int `json:"rank"`
+	Readers int `json:"readers"`
+}
+
+func GetMostRead(r io.Reader) (*MostReadPage, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MostReadPage{
+		Entries: []MostReadEntry{},
+	}
+
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		if u, err := url.Parse(getAttr(link, "href")); err == nil {
+			page.Country = u.Query().Get("country")
+		}
+	}
+
+	for _, row := range findAll(doc, byAttr("tr", "itemtype", BookRefRowType)) {
+		ref, ok := extractBookRef(row)
+		if !ok {
+			continue
+		}
+
+		page.Entries = append(page.Entries, MostReadEntry{
+			BookRef: ref,
+			Rank:    len(page.Entries) + 1,
+			Readers: matchCount(readersPattern, textContent(row)),
+		})
+	}
+
+	return page, nil
+}
+
+func (p *MostReadPage) Books() Books {
+	books := Books{Books: []Book{}}
+
+	for _, entry := range p.Entries {
+		books.Books = append(books.Books, entry.Book())
+	}
+
+	return books
+}
@@ -0,0 +1,58 @@
+package book
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	NewReleasesURLIndicator = "/book/new_releases"
+	NewReleaseIndicator     = "bookBox"
+	NewReleaseDateIndicator = "greyText"
+)
+
+var expectedDatePattern = regexp.MustCompile(`(?i)(?:expected publication|release date|published)[:\s]+(.+)$`)
+
+type NewRelease struct {
+	BookRef
+	Expected string `json:"expected"`
+}
+
+func GetNewReleases(r io.Reader) ([]NewRelease, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := []NewRelease{}
+	seen := map[string]bool{}
+
+	for _, box := range findAll(doc, byClass("div", NewReleaseIndicator)) {
+		refs := extractCoverRefs(box)
+		if len(refs) == 0 || seen[refs[0].URL] {
+			continue
+		}
+
+		seen[refs[0].URL] = true
+		release := NewRelease{BookRef: refs[0]}
+
+		if title, author, ok := strings.Cut(release.Title, " by "); ok {
+			release.Title = title
+			release.Authors = []string{author}
+		}
+
+		for _, n := range findAll(box, byClass("", NewReleaseDateIndicator)) {
+			if m := expectedDatePattern.FindStringSubmatch(textContent(n)); m != nil {
+				release.Expected = strings.TrimSpace(m[1])
+				break
+			}
+		}
+
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
@@ -0,0 +1,369 @@
+package book
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const NextDataScriptID = "__NEXT_DATA__"
+
+var nextDataSource = `script#` + NextDataScriptID
+
+// BookDetails holds what the page's embedded application state knows
+// about a book beyond the core fields. RatingsHistogram counts the 1 to 5
+// star ratings in that order. Reviews are the few the page ships with,
+// not the whole set.
+type BookDetails struct {
+	TitleComplete    string   `json:"title_complete,omitempty" yaml:"title_complete,omitempty" xml:"title_complete,omitempty"`
+	OriginalTitle    string   `json:"original_title,omitempty" yaml:"original_title,omitempty" xml:"original_title,omitempty"`
+	Description      string   `json:"description,omitempty" yaml:"description,omitempty" xml:"description,omitempty"`
+	Pages            int      `json:"pages,omitempty" yaml:"pages,omitempty" xml:"pages,omitempty"`
+	Format           string   `json:"format,omitempty" yaml:"format,omitempty" xml:"format,omitempty"`
+	Publisher        string   `json:"publisher,omitempty" yaml:"publisher,omitempty" xml:"publisher,omitempty"`
+	Published        string   `json:"published,omitempty" yaml:"published,omitempty" xml:"published,omitempty"`
+	FirstPublished   string   `json:"first_published,omitempty" yaml:"first_published,omitempty" xml:"first_published,omitempty"`
+	Language         string   `json:"language,omitempty" yaml:"language,omitempty" xml:"language,omitempty"`
+	ISBN10           string   `json:"isbn10,omitempty" yaml:"isbn10,omitempty" xml:"isbn10,omitempty"`
+	ISBN13           string   `json:"isbn13,omitempty" yaml:"isbn13,omitempty" xml:"isbn13,omitempty"`
+	ASIN             string   `json:"asin,omitempty" yaml:"asin,omitempty" xml:"asin,omitempty"`
+	RatingsHistogram []int    `json:"ratings_histogram,omitempty" yaml:"ratings_histogram,omitempty" xml:"ratings_histogram>count,omitempty"`
+	Reviews          []Review `json:"reviews,omitempty" yaml:"reviews,omitempty" xml:"reviews>review,omitempty"`
+}
+
+// apolloState is the normalised GraphQL cache the React page hydrates
+// from: entities keyed by "Type:id", pointing at each other with
+// {"__ref": key}.
+type apolloState map[string]json.RawMessage
+
+type apolloRef struct {
+	Ref string `json:"__ref"`
+}
+
+type nextDataBook struct {
+	TypeName      string    `json:"__typename"`
+	LegacyID      int64     `json:"legacyId"`
+	WebURL        string    `json:"webUrl"`
+	Title         string    `json:"title"`
+	TitleComplete string    `json:"titleComplete"`
+	Description   string    `json:"description"`
+	ImageURL      string    `json:"imageUrl"`
+	Work          apolloRef `json:"work"`
+	Primary       *struct {
+		Node apolloRef `json:"node"`
+	} `json:"primaryContributorEdge"`
+	Secondary []struct {
+		Node apolloRef `json:"node"`
+	} `json:"secondaryContributorEdges"`
+	Genres []struct {
+		Genre struct {
+			Name   string `json:"name"`
+			WebURL string `json:"webUrl"`
+		} `json:"genre"`
+	} `json:"bookGenres"`
+	Details struct {
+		NumPages        int     `json:"numPages"`
+		Format          string  `json:"format"`
+		Publisher       string  `json:"publisher"`
+		PublicationTime float64 `json:"publicationTime"`
+		ISBN            string  `json:"isbn"`
+		ISBN13          string  `json:"isbn13"`
+		ASIN            string  `json:"asin"`
+		Language        struct {
+			Name string `json:"name"`
+		} `json:"language"`
+	} `json:"details"`
+}
+
+type nextDataWork struct {
+	LegacyID int64 `json:"legacyId"`
+	Stats    struct {
+		AverageRating    float64 `json:"averageRating"`
+		RatingsCount     int     `json:"ratingsCount"`
+		RatingsCountDist []int   `json:"ratingsCountDist"`
+		TextReviewsCount int     `json:"textReviewsCount"`
+	} `json:"stats"`
+	Details struct {
+		OriginalTitle   string  `json:"originalTitle"`
+		PublicationTime float64 `json:"publicationTime"`
+	} `json:"details"`
+}
+
+type nextDataContributor struct {
+	LegacyID int64  `json:"legacyId"`
+	Name     string `json:"name"`
+	WebURL   string `json:"webUrl"`
+}
+
+type nextDataReview struct {
+	TypeName     string    `json:"__typename"`
+	ID           string    `json:"id"`
+	Text         string    `json:"text"`
+	Rating       int       `json:"rating"`
+	CreatedAt    float64   `json:"createdAt"`
+	LikeCount    int       `json:"likeCount"`
+	CommentCount int       `json:"commentCount"`
+	Creator      apolloRef `json:"creator"`
+	Book         apolloRef `json:"book"`
+}
+
+type nextDataUser struct {
+	LegacyID int64  `json:"legacyId"`
+	Name     string `json:"name"`
+	WebURL   string `json:"webUrl"`
+}
+
+// findApolloState decodes the Apollo cache out of the page's __NEXT_DATA__
+// script.
+func findApolloState(doc *html.Node) apolloState {
+	script := findFirst(doc, byAttr("script", "id", NextDataScriptID))
+	if script == nil {
+		return nil
+	}
+
+	var data struct {
+		Props struct {
+			PageProps struct {
+				ApolloState apolloState `json:"apolloState"`
+			} `json:"pageProps"`
+		} `json:"props"`
+	}
+
+	if err := json.Unmarshal([]byte(rawText(script)), &data); err != nil {
+		return nil
+	}
+
+	return data.Props.PageProps.ApolloState
+}
+
+func (s apolloState) get(key string, v interface{}) bool {
+	raw, ok := s[key]
+	if !ok {
+		return false
+	}
+
+	return json.Unmarshal(raw, v) == nil
+}
+
+// bookKey finds the page's own book: the one the root query fetched,
+// rather than any of the similar books also in the cache.
+func (s apolloState) bookKey() string {
+	var root map[string]json.RawMessage
+	if s.get("ROOT_QUERY", &root) {
+		for field, raw := range root {
+			if !strings.HasPrefix(field, "getBookByLegacyId") {
+				continue
+			}
+
+			var ref apolloRef
+			if json.Unmarshal(raw, &ref) == nil && ref.Ref != "" {
+				return ref.Ref
+			}
+		}
+	}
+
+	for key := range s {
+		if strings.HasPrefix(key, "Book:") {
+			return key
+		}
+	}
+
+	return ""
+}
+
+// applyNextData fills book from the embedded application state, which
+// is the only source for BookDetails. Core fields follow the same
+// fallback rules as applyJSONLD.
+func applyNextData(doc *html.Node, book *Book, cfg *parseConfig, sources map[string]string) {
+	state := findApolloState(doc)
+	if state == nil {
+		return
+	}
+
+	key := state.bookKey()
+	fill := fieldFiller{sources: sources, source: nextDataSource, prefer: cfg.preferNextData}
+
+	var nb nextDataBook
+	if key == "" || !state.get(key, &nb) {
+		return
+	}
+
+	var work nextDataWork
+	state.get(nb.Work.Ref, &work)
+
+	if nb.Title != "" && fill.take(FieldTitle, book.Title == "") {
+		book.Title = nb.Title
+	}
+
+	if nb.WebURL != "" && fill.take(FieldURL, book.URL == "") {
+		book.URL = nb.WebURL
+	}
+
+	if work.LegacyID != 0 && fill.take(FieldID, book.ID == "") {
+		book.ID = strconv.FormatInt(work.LegacyID, 10)
+	}
+
+	if nb.ImageURL != "" && fill.take(FieldCover, book.CoverUrl == "") {
+		book.CoverUrl = nb.ImageURL
+	}
+
+	isbn := nb.Details.ISBN13
+	if isbn == "" {
+		isbn = nb.Details.ISBN
+	}
+
+	if isbn != "" && fill.take(FieldISBN, book.ISBN == "") {
+		book.ISBN = isbn
+	}
+
+	contributors := []apolloRef{}
+	if nb.Primary != nil {
+		contributors = append(contributors, nb.Primary.Node)
+	}
+
+	for _, edge := range nb.Secondary {
+		contributors = append(contributors, edge.Node)
+	}
+
+	authors := []AuthorRef{}
+	for _, ref := range contributors {
+		var c nextDataContributor
+		if state.get(ref.Ref, &c) && c.Name != "" {
+			authors = append(authors, NewAuthorRef(c.Name, legacyID(c.LegacyID)))
+		}
+	}
+
+	if len(authors) > 0 && fill.take(FieldAuthors, len(book.Authors) == 0) {
+		book.Authors = authors
+	}
+
+	genres := []Genre{}
+	for _, g := range nb.Genres {
+		slug := lastPathSegment(g.Genre.WebURL)
+		if slug != "" && !cfg.excludesGenre(slug) {
+			genres = append(genres, Genre(slug))
+		}
+	}
+
+	if len(genres) > 0 && fill.take(FieldGenres, len(book.Genres) == 0) {
+		book.Genres = genres
+	}
+
+	if work.Stats.AverageRating != 0 && fill.take(FieldRating, book.Rating == 0) {
+		book.Rating = work.Stats.AverageRating
+	}
+
+	if work.Stats.RatingsCount != 0 && fill.take(FieldRatings, book.Ratings == 0) {
+		book.Ratings = work.Stats.RatingsCount
+	}
+
+	if work.Stats.TextReviewsCount != 0 && fill.take(FieldReviews, book.Reviews == 0) {
+		book.Reviews = work.Stats.TextReviewsCount
+	}
+
+	book.Details = &BookDetails{
+		TitleComplete:    nb.TitleComplete,
+		OriginalTitle:    work.Details.OriginalTitle,
+		Description:      htmlText(nb.Description),
+		Pages:            nb.Details.NumPages,
+		Format:           nb.Details.Format,
+		Publisher:        nb.Details.Publisher,
+		Published:        epochMillisDate(nb.Details.PublicationTime),
+		FirstPublished:   epochMillisDate(work.Details.PublicationTime),
+		Language:         nb.Details.Language.Name,
+		ISBN10:           nb.Details.ISBN,
+		ISBN13:           nb.Details.ISBN13,
+		ASIN:             nb.Details.ASIN,
+		RatingsHistogram: work.Stats.RatingsCountDist,
+		Reviews:          nextDataReviews(state, key, book, cfg),
+	}
+	sources[FieldDetails] = nextDataSource
+}
+
+func nextDataReviews(state apolloState, bookKey string, book *Book, cfg *parseConfig) []Review {
+	reviews := []Review{}
+
+	for key := range state {
+		if !strings.HasPrefix(key, "Review:") {
+			continue
+		}
+
+		var nr nextDataReview
+		if !state.get(key, &nr) || (nr.Book.Ref != "" && nr.Book.Ref != bookKey) {
+			continue
+		}
+
+		review := Review{
+			ID:       lastPathSegment(key),
+			Book:     BookRef{Title: book.Title, URL: book.URL, ID: bookIDFromURL(book.URL)},
+			Rating:   nr.Rating,
+			Date:     epochMillisDate(nr.CreatedAt),
+			Text:     htmlText(nr.Text),
+			Shelves:  []string{},
+			Comments: nr.CommentCount,
+			Likes:    nr.LikeCount,
+		}
+
+		var user nextDataUser
+		if state.get(nr.Creator.Ref, &user) {
+			review.Reviewer = Reviewer{ID: legacyID(user.LegacyID), Name: user.Name, ProfileURL: user.WebURL}
+		}
+
+		cfg.applyReviewer(&review.Reviewer)
+		reviews = append(reviews, review)
+	}
+
+	// Map order is random; keep the output stable, newest first.
+	sort.Slice(reviews, func(i, j int) bool {
+		if reviews[i].Date != reviews[j].Date {
+			return reviews[i].Date > reviews[j].Date
+		}
+
+		return reviews[i].ID < reviews[j].ID
+	})
+
+	return reviews
+}
+
+func legacyID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(id, 10)
+}
+
+// epochMillisDate formats the millisecond timestamps the state uses as a
+// plain date.
+func epochMillisDate(ms float64) string {
+	if ms == 0 {
+		return ""
+	}
+
+	return time.UnixMilli(int64(ms)).UTC().Format(time.DateOnly)
+}
+
+// htmlText reduces an HTML fragment, such as a description, to its text.
+func htmlText(fragment string) string {
+	if fragment == "" {
+		return ""
+	}
+
+	div := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), div)
+	if err != nil {
+		return fragment
+	}
+
+	for _, n := range nodes {
+		div.AppendChild(n)
+	}
+
+	return textContent(div)
+}
@@ -0,0 +1,210 @@
+package book
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+func isElement(n *html.Node, tag string) bool {
+	return n != nil && n.Type == html.ElementNode && n.Data == tag
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+// hasClass reports whether n carries every class in class, which may
+// list several separated by spaces. A class also matches its
+// hyphen-suffixed variants such as BEM modifiers, so
+// "RatingStatistics__rating" matches "RatingStatistics__rating--large" but
+// not "RatingStatistics__ratingCount" or "RatingStatistics__rating__star".
+func hasClass(n *html.Node, class string) bool {
+	if n == nil || n.Type != html.ElementNode {
+		return false
+	}
+
+	tokens := strings.Fields(getAttr(n, "class"))
+
+	want := strings.Fields(class)
+	if len(want) == 0 {
+		return false
+	}
+
+	for _, w := range want {
+		if !hasClassToken(tokens, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasClassToken(tokens []string, class string) bool {
+	for _, token := range tokens {
+		rest, ok := strings.CutPrefix(token, class)
+		if ok && (rest == "" || rest[0] == '-') {
+			return true
+		}
+	}
+
+	return false
+}
+
+func findFirst(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if match(n) {
+		return n
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findFirst(c, match); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+func findAll(n *html.Node, match func(*html.Node) bool) []*html.Node {
+	nodes := []*html.Node{}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if match(n) {
+			nodes = append(nodes, n)
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(n)
+
+	return nodes
+}
+
+func byClass(tag, class string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return (tag == "" || isElement(n, tag)) && hasClass(n, class)
+	}
+}
+
+func byAttr(tag, key, val string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return (tag == "" || isElement(n, tag)) && n.Type == html.ElementNode && getAttr(n, key) == val
+	}
+}
+
+var blockElements = map[string]bool{
+	"br": true, "div": true, "p": true, "li": true, "ul": true, "ol": true,
+	"tr": true, "td": true, "th": true, "dt": true, "dd": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// rawText is the unprocessed text directly inside n, for elements such as
+// script whose content textContent skips.
+func rawText(n *html.Node) string {
+	var sb strings.Builder
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+
+	return sb.String()
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+			return
+		}
+
+		block := n.Type == html.ElementNode && blockElements[n.Data]
+		if block {
+			sb.WriteString(" ")
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+
+		if block {
+			sb.WriteString(" ")
+		}
+	}
+
+	walk(n)
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// truncateRunes cuts s to at most max runes, marking the cut with an
+// ellipsis.
+func truncateRunes(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max]) + "…"
+	}
+
+	return s
+}
+
+func nextElementSibling(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+
+	return nil
+}
+
+func parseCount(s string) int {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+
+		return -1
+	}, s)
+
+	val, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+
+	return val
+}
+
+func lastPathSegment(url string) string {
+	url, _, _ = strings.Cut(url, "?")
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+
+	return parts[len(parts)-1]
+}
+
+func leadingDigits(s string) string {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+
+	return s[:end]
+}
@@ -0,0 +1,75 @@
+package book
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeText puts s in Unicode NFC so composed and decomposed accents
+// compare equal, and collapses runs of whitespace, newlines included, to
+// single spaces. Entities are left alone: the HTML parser has already
+// decoded them, and decoding again would turn an escaped "&lt;" in the
+// source into a literal "<".
+func NormalizeText(s string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(s)), " ")
+}
+
+// WithRawText skips the normalization pass GetBook otherwise runs over
+// every string field, leaving the text exactly as extracted.
+func WithRawText() ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.rawText = true
+	}
+}
+
+// WithTextNormalizer replaces NormalizeText as the function the
+// normalization pass runs over every string field.
+func WithTextNormalizer(fn func(string) string) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.normalize = fn
+	}
+}
+
+// normalizeBook cleans up the free text of b. URLs, IDs and genre slugs
+// are identifiers and are left exactly as extracted.
+func (cfg *parseConfig) normalizeBook(b *Book) {
+	if cfg.rawText {
+		return
+	}
+
+	clean := cfg.normalize
+	if clean == nil {
+		clean = NormalizeText
+	}
+
+	b.Title = clean(b.Title)
+	b.ISBN = clean(b.ISBN)
+
+	for i, author := range b.Authors {
+		b.Authors[i] = NewAuthorRef(clean(author.Name()), author.ID())
+	}
+
+	if d := b.Details; d != nil {
+		for _, s := range []*string{
+			&d.TitleComplete, &d.OriginalTitle, &d.Description, &d.Format, &d.Publisher,
+			&d.Published, &d.FirstPublished, &d.Language, &d.ISBN10, &d.ISBN13, &d.ASIN,
+		} {
+			*s = clean(*s)
+		}
+
+		for i := range d.Reviews {
+			normalizeReview(&d.Reviews[i], clean)
+		}
+	}
+}
+
+func normalizeReview(r *Review, clean func(string) string) {
+	for _, s := range []*string{&r.Date, &r.Text, &r.Reviewer.Name} {
+		*s = clean(*s)
+	}
+
+	for i, shelf := range r.Shelves {
+		r.Shelves[i] = clean(shelf)
+	}
+}
@@ -0,0 +1,49 @@
+package book_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestNormalizationLeavesURLsAndEntitiesAlone(t *testing.T) {
+	want := book.Book{
+		Title:    "&lt;div&gt; & co",
+		URL:      "https://www.goodreads.com/book/show/1?w=1&not=2&copy=3",
+		ID:       "1",
+		CoverUrl: "https://images.gr-assets.com/books/1.jpg?w=1&not=2&copy=3",
+	}
+
+	got, err := book.GetBook(bytes.NewReader(testutil.RenderFixture(want)))
+	if err != nil {
+		t.Fatalf("GetBook: %v", err)
+	}
+
+	if got.Title != want.Title {
+		t.Errorf("Title = %q, want %q", got.Title, want.Title)
+	}
+
+	if got.URL != want.URL {
+		t.Errorf("URL = %q, want %q", got.URL, want.URL)
+	}
+
+	if got.CoverUrl != want.CoverUrl {
+		t.Errorf("CoverUrl = %q, want %q", got.CoverUrl, want.CoverUrl)
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	tests := map[string]string{
+		"  Dune \n\t Messiah ": "Dune Messiah",
+		"Café":                "Café",
+		"&amp;lt;":             "&amp;lt;",
+	}
+
+	for in, want := range tests {
+		if got := book.NormalizeText(in); got != want {
+			t.Errorf("NormalizeText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
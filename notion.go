@@ -0,0 +1,72 @@
+package book
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NotionTable shapes books for a Notion database CSV import. Notion takes
+// the first column as the page title and splits multi-select cells on
+// commas.
+func NotionTable(books []Book) Table {
+	return selectTable("notion", []string{"Name", "Authors", "Genres", "Rating", "Ratings", "Reviews", "Goodreads", "Cover"}, books)
+}
+
+// AirtableTable shapes books for an Airtable CSV import. Multi-select
+// cells are comma separated and the cover URL is imported as an
+// attachment.
+func AirtableTable(books []Book) Table {
+	return selectTable("airtable", []string{"Title", "Authors", "Genres", "Rating", "Ratings", "Reviews", "Goodreads URL", "Cover"}, books)
+}
+
+func WriteNotionCSV(w io.Writer, books []Book) error {
+	return NotionTable(books).WriteCSV(w)
+}
+
+func WriteAirtableCSV(w io.Writer, books []Book) error {
+	return AirtableTable(books).WriteCSV(w)
+}
+
+func selectTable(name string, columns []string, books []Book) Table {
+	table := Table{Name: name, Columns: columns}
+
+	for _, b := range books {
+		table.Rows = append(table.Rows, []string{
+			b.Title,
+			selectField(b.AuthorNames()),
+			selectField(b.GenreSlugs()),
+			strconv.FormatFloat(b.Rating, 'f', -1, 64),
+			strconv.Itoa(b.Ratings),
+			strconv.Itoa(b.Reviews),
+			b.URL,
+			b.CoverUrl,
+		})
+	}
+
+	return table
+}
+
+// selectField joins options into a single multi-select cell. Commas inside
+// an option would split it into two, so they are dropped.
+func selectField(options []string) string {
+	cleaned := make([]string, 0, len(options))
+
+	for _, option := range options {
+		option = strings.TrimSpace(strings.ReplaceAll(option, ",", ""))
+		if option != "" {
+			cleaned = append(cleaned, option)
+		}
+	}
+
+	return strings.Join(cleaned, ", ")
+}
+
+func (t Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(t.Columns)
+	cw.WriteAll(t.Rows)
+
+	return cw.Error()
+}
@@ -0,0 +1,486 @@
+// Package notion pushes books into a Notion database through the Notion
+// API, updating the page already holding a book rather than adding a
+// second one. For a one-off import without an integration token, the book
+// package's WriteNotionCSV produces a file Notion can import instead.
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dchooyc/book"
+)
+
+const (
+	DefaultBaseURL = "https://api.notion.com/v1/"
+
+	// APIVersion is the Notion-Version the request and response shapes
+	// here follow.
+	APIVersion = "2022-06-28"
+
+	// Notion caps a text object at 2000 characters, a rich text array at
+	// 100 objects and a select option name at 100 characters.
+	maxTextLen    = 2000
+	maxTextChunks = 100
+	maxOptionLen  = 100
+)
+
+var (
+	ErrNoID = errors.New("book has no Goodreads ID")
+
+	// ErrMapping is returned when a mapping leaves out the title or
+	// Goodreads ID property, without which pages can't be created or found
+	// again.
+	ErrMapping = errors.New("mapping needs Title and GoodreadsID properties")
+)
+
+// APIError is an error response from the Notion API.
+type APIError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("notion: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// Mapping names the database property each Book field is written to. A
+// field with no name is left out. The properties must already exist in
+// the database with the types noted.
+type Mapping struct {
+	Title       string // title
+	GoodreadsID string // rich text; the key books are upserted by
+	Authors     string // multi-select
+	Genres      string // multi-select
+	Rating      string // number
+	Ratings     string // number
+	Reviews     string // number
+	URL         string // URL
+	Cover       string // URL
+	Published   string // date
+	Pages       string // number
+	Publisher   string // rich text
+	ISBN        string // rich text
+	Description string // rich text
+}
+
+// DefaultMapping uses the column names of the book package's Notion CSV,
+// plus "Goodreads ID", so a database first filled by a CSV import can be
+// kept up to date through the API.
+func DefaultMapping() Mapping {
+	return Mapping{
+		Title:       "Name",
+		GoodreadsID: "Goodreads ID",
+		Authors:     "Authors",
+		Genres:      "Genres",
+		Rating:      "Rating",
+		Ratings:     "Ratings",
+		Reviews:     "Reviews",
+		URL:         "Goodreads",
+		Cover:       "Cover",
+	}
+}
+
+// Properties builds the page property values for b under m.
+func (m Mapping) Properties(b book.Book) map[string]interface{} {
+	props := map[string]interface{}{}
+
+	set := func(name string, value interface{}) {
+		if name != "" {
+			props[name] = value
+		}
+	}
+
+	set(m.Title, map[string]interface{}{"title": richText(b.Title)})
+	set(m.GoodreadsID, map[string]interface{}{"rich_text": richText(b.ID)})
+	set(m.Authors, multiSelect(b.AuthorNames()))
+	set(m.Genres, multiSelect(genreNames(b.Genres)))
+	set(m.Rating, number(b.Rating))
+	set(m.Ratings, number(float64(b.Ratings)))
+	set(m.Reviews, number(float64(b.Reviews)))
+	set(m.URL, urlValue(b.URL))
+	set(m.Cover, urlValue(b.CoverUrl))
+
+	var d book.BookDetails
+	if b.Details != nil {
+		d = *b.Details
+	}
+
+	date := map[string]interface{}{"date": nil}
+	if published, err := d.PublishedDate(); err == nil {
+		date["date"] = map[string]interface{}{"start": published.Time.Format("2006-01-02")}
+	}
+
+	set(m.Published, date)
+
+	if d.Pages > 0 {
+		set(m.Pages, number(float64(d.Pages)))
+	} else {
+		set(m.Pages, map[string]interface{}{"number": nil})
+	}
+
+	isbn := b.ISBN
+	if d.ISBN13 != "" {
+		isbn = d.ISBN13
+	}
+
+	set(m.Publisher, map[string]interface{}{"rich_text": richText(d.Publisher)})
+	set(m.ISBN, map[string]interface{}{"rich_text": richText(isbn)})
+	set(m.Description, map[string]interface{}{"rich_text": richText(d.Description)})
+
+	return props
+}
+
+// richText splits s into text objects under Notion's length limit,
+// dropping whatever doesn't fit in the array.
+func richText(s string) []interface{} {
+	chunks := []interface{}{}
+
+	runes := []rune(s)
+	for len(runes) > 0 && len(chunks) < maxTextChunks {
+		n := len(runes)
+		if n > maxTextLen {
+			n = maxTextLen
+		}
+
+		chunks = append(chunks, map[string]interface{}{"text": map[string]string{"content": string(runes[:n])}})
+		runes = runes[n:]
+	}
+
+	return chunks
+}
+
+// multiSelect turns names into options. Notion rejects commas in option
+// names, so they are dropped, as in the CSV.
+func multiSelect(names []string) map[string]interface{} {
+	options := []interface{}{}
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		name = strings.TrimSpace(strings.ReplaceAll(name, ",", ""))
+		if runes := []rune(name); len(runes) > maxOptionLen {
+			name = string(runes[:maxOptionLen])
+		}
+
+		if name != "" && !seen[name] {
+			seen[name] = true
+			options = append(options, map[string]string{"name": name})
+		}
+	}
+
+	return map[string]interface{}{"multi_select": options}
+}
+
+func genreNames(genres []book.Genre) []string {
+	names := make([]string, len(genres))
+	for i, genre := range genres {
+		names[i] = genre.Name()
+	}
+
+	return names
+}
+
+func number(n float64) map[string]interface{} {
+	return map[string]interface{}{"number": n}
+}
+
+func urlValue(u string) map[string]interface{} {
+	if u == "" {
+		return map[string]interface{}{"url": nil}
+	}
+
+	return map[string]interface{}{"url": u}
+}
+
+// Exporter writes books into one Notion database.
+type Exporter struct {
+	token    string
+	database string
+	mapping  Mapping
+	baseURL  string
+	http     *http.Client
+	limiter  *book.RateLimiter
+	retry    book.RetryPolicy
+	covers   bool
+}
+
+type Option func(*Exporter)
+
+func WithMapping(m Mapping) Option {
+	return func(e *Exporter) {
+		e.mapping = m
+	}
+}
+
+func WithHTTPClient(c *http.Client) Option {
+	return func(e *Exporter) {
+		e.http = c
+	}
+}
+
+// WithBaseURL points the exporter at another API root, such as a test
+// server. It must end in a slash.
+func WithBaseURL(u string) Option {
+	return func(e *Exporter) {
+		e.baseURL = u
+	}
+}
+
+// WithRateLimiter replaces the default limit of three requests a second,
+// the average Notion allows per integration.
+func WithRateLimiter(l *book.RateLimiter) Option {
+	return func(e *Exporter) {
+		e.limiter = l
+	}
+}
+
+func WithRetryPolicy(p book.RetryPolicy) Option {
+	return func(e *Exporter) {
+		e.retry = p
+	}
+}
+
+// WithoutPageCovers stops the exporter setting each page's cover image to
+// the book cover.
+func WithoutPageCovers() Option {
+	return func(e *Exporter) {
+		e.covers = false
+	}
+}
+
+// NewExporter writes to the database with the given ID using an internal
+// integration token. The database has to be shared with the integration.
+func NewExporter(token, databaseID string, opts ...Option) *Exporter {
+	e := &Exporter{
+		token:    token,
+		database: databaseID,
+		mapping:  DefaultMapping(),
+		baseURL:  DefaultBaseURL,
+		http:     http.DefaultClient,
+		limiter:  book.NewRateLimiter(3, 1, 0),
+		retry:    book.DefaultRetryPolicy,
+		covers:   true,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Stats counts the outcome of an Export.
+type Stats struct {
+	Created int
+	Updated int
+	Failed  int
+}
+
+// Export upserts each book in turn. A book that fails is counted and
+// skipped; its error is returned joined with the others once every book
+// has been tried. Cancelling ctx stops the export early.
+func (e *Exporter) Export(ctx context.Context, books []book.Book) (Stats, error) {
+	stats := Stats{}
+	errs := []error{}
+
+	for _, b := range books {
+		_, created, err := e.Upsert(ctx, b)
+		if ctx.Err() != nil {
+			return stats, ctx.Err()
+		}
+
+		switch {
+		case err != nil:
+			stats.Failed++
+			errs = append(errs, fmt.Errorf("%s: %w", b.Title, err))
+		case created:
+			stats.Created++
+		default:
+			stats.Updated++
+		}
+	}
+
+	return stats, errors.Join(errs...)
+}
+
+// Upsert updates the page whose Goodreads ID property matches b.ID, or
+// creates one, and returns its page ID and whether it was created.
+func (e *Exporter) Upsert(ctx context.Context, b book.Book) (string, bool, error) {
+	if e.mapping.Title == "" || e.mapping.GoodreadsID == "" {
+		return "", false, ErrMapping
+	}
+
+	if b.ID == "" {
+		return "", false, fmt.Errorf("%w: %s", ErrNoID, b.URL)
+	}
+
+	pageID, err := e.find(ctx, b.ID)
+	if err != nil {
+		return "", false, err
+	}
+
+	body := map[string]interface{}{"properties": e.mapping.Properties(b)}
+	if e.covers && b.CoverUrl != "" {
+		body["cover"] = map[string]interface{}{"type": "external", "external": map[string]string{"url": b.CoverUrl}}
+	}
+
+	if pageID != "" {
+		var page struct{}
+		return pageID, false, e.do(ctx, http.MethodPatch, "pages/"+pageID, body, &page)
+	}
+
+	body["parent"] = map[string]string{"database_id": e.database}
+
+	pageID, err = e.create(ctx, b.ID, body)
+	if err != nil {
+		return "", false, err
+	}
+
+	return pageID, true, nil
+}
+
+// create adds a page, which do won't retry after a network or server
+// error since the page may have been created all the same. Instead create
+// looks the book up again before each retry, and returns the page it
+// finds rather than adding a second one.
+func (e *Exporter) create(ctx context.Context, id string, body map[string]interface{}) (string, error) {
+	for attempt := 1; ; attempt++ {
+		var page struct {
+			ID string `json:"id"`
+		}
+
+		err := e.do(ctx, http.MethodPost, "pages", body, &page)
+		if err == nil {
+			return page.ID, nil
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Status < http.StatusInternalServerError {
+			return "", err
+		}
+
+		if ctx.Err() != nil || !e.retry.Allows(attempt) {
+			return "", err
+		}
+
+		if err := e.retry.Wait(ctx, attempt, 0); err != nil {
+			return "", err
+		}
+
+		pageID, err := e.find(ctx, id)
+		if err != nil {
+			return "", err
+		}
+
+		if pageID != "" {
+			return pageID, nil
+		}
+	}
+}
+
+// find returns the ID of the page holding the book with Goodreads ID id,
+// or "" if there is none.
+func (e *Exporter) find(ctx context.Context, id string) (string, error) {
+	query := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"property":  e.mapping.GoodreadsID,
+			"rich_text": map[string]string{"equals": id},
+		},
+		"page_size": 1,
+	}
+
+	var result struct {
+		Results []struct {
+			ID string `json:"id"`
+		} `json:"results"`
+	}
+
+	if err := e.do(ctx, http.MethodPost, "databases/"+e.database+"/query", query, &result); err != nil {
+		return "", err
+	}
+
+	if len(result.Results) == 0 {
+		return "", nil
+	}
+
+	return result.Results[0].ID, nil
+}
+
+// do sends one API request under the retry policy, honouring
+// Retry-After. A 429 is always retried, as Notion hasn't acted on the
+// request; network and server errors only for idempotent requests.
+func (e *Exporter) do(ctx context.Context, method, path string, in, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := e.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+e.token)
+		req.Header.Set("Notion-Version", APIVersion)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := e.http.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || !idempotent(method, path) || !e.retry.Allows(attempt) {
+				return err
+			}
+
+			if err := e.retry.Wait(ctx, attempt, 0); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return json.Unmarshal(data, out)
+		}
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests || book.RetryableStatus(resp.StatusCode) && idempotent(method, path)
+		if retryable && e.retry.Allows(attempt) {
+			if err := e.retry.Wait(ctx, attempt, book.RetryAfter(resp.Header)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		apiErr := &APIError{Status: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+
+		return apiErr
+	}
+}
+
+// idempotent reports whether sending a request twice is harmless, which
+// holds for everything but creating a page. Database queries are POSTs
+// but only read.
+func idempotent(method, path string) bool {
+	return method != http.MethodPost || strings.HasSuffix(path, "/query")
+}
@@ -0,0 +1,95 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/notion"
+)
+
+// fakeNotion stores pages by Goodreads ID. With dropCreate set, the first
+// create is stored but its connection is closed before the response, as
+// when a network fails after Notion has acted.
+type fakeNotion struct {
+	mu         sync.Mutex
+	pages      map[string]string
+	creates    int
+	dropCreate bool
+}
+
+func (f *fakeNotion) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body struct {
+		Filter struct {
+			RichText struct {
+				Equals string `json:"equals"`
+			} `json:"rich_text"`
+		} `json:"filter"`
+		Properties map[string]struct {
+			RichText []struct {
+				Text struct {
+					Content string `json:"content"`
+				} `json:"text"`
+			} `json:"rich_text"`
+		} `json:"properties"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	switch r.URL.Path {
+	case "/databases/db/query":
+		results := []map[string]string{}
+		if id, ok := f.pages[body.Filter.RichText.Equals]; ok {
+			results = append(results, map[string]string{"id": id})
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	case "/pages":
+		f.creates++
+		id := body.Properties["Goodreads ID"].RichText[0].Text.Content
+		f.pages[id] = "page-" + id
+
+		if f.dropCreate {
+			f.dropCreate = false
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"id": f.pages[id]})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestUpsertDoesNotDuplicateAfterLostCreate(t *testing.T) {
+	fake := &fakeNotion{pages: map[string]string{}, dropCreate: true}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	e := notion.NewExporter("token", "db",
+		notion.WithBaseURL(srv.URL+"/"),
+		notion.WithRateLimiter(book.NewRateLimiter(1000, 10, 0)),
+		notion.WithRetryPolicy(book.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+
+	id, created, err := e.Upsert(context.Background(), book.Book{Title: "Dune", ID: "234225"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "page-234225" || !created {
+		t.Errorf("Upsert = %q, %v; want page-234225, true", id, created)
+	}
+
+	if fake.creates != 1 {
+		t.Errorf("%d create requests, want 1", fake.creates)
+	}
+}
@@ -0,0 +1,124 @@
+package book
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// localeNumberPattern matches a number written with any of the grouping
+// marks Goodreads uses across languages: comma, dot, apostrophe, and
+// plain or narrow no-break spaces.
+var localeNumberPattern = regexp.MustCompile(`\d(?:[\d.,'\x{2019} \x{00a0}\x{202f}]*\d)?`)
+
+var numberSeparators = strings.NewReplacer(",", "", ".", "", "'", "", "\u2019", "", " ", "", "\u00a0", "", "\u202f", "")
+
+// localeNumbers returns the numbers in s in order of appearance, which
+// keeps ratings before reviews whatever the words around them are.
+func localeNumbers(s string) []string {
+	return localeNumberPattern.FindAllString(s, -1)
+}
+
+// parseLocaleInt parses a count such as "1,234", "1.234" or "1 234".
+func parseLocaleInt(s string) (int, error) {
+	return strconv.Atoi(numberSeparators.Replace(strings.TrimSpace(s)))
+}
+
+// parseLocaleFloat parses an average rating such as "4.32" or "4,32". A
+// final dot or comma followed by one or two digits is the decimal mark;
+// any other is grouping, which an average rating never has.
+func parseLocaleFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	whole, frac := s, ""
+
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 && len(s)-i-1 >= 1 && len(s)-i-1 <= 2 {
+		whole, frac = s[:i], s[i+1:]
+	}
+
+	num := numberSeparators.Replace(whole)
+	if frac != "" {
+		num += "." + frac
+	}
+
+	return strconv.ParseFloat(num, 64)
+}
+
+var compactMultipliers = map[byte]float64{'k': 1e3, 'K': 1e3, 'm': 1e6, 'M': 1e6}
+
+// statsCounts picks the ratings and reviews counts out of a stats label.
+// English labels are read by the word after each number, which copes with
+// "1 rating and 0 reviews", a count on its own such as "12 ratings", and
+// an average rating mixed in. Labels in other languages fall back to the
+// order of the first two numbers, which Goodreads keeps as ratings then
+// reviews. A missing count in a worded label is zero.
+func statsCounts(label string) (ratings, reviews string, ok bool) {
+	locs := localeNumberPattern.FindAllStringIndex(label, -1)
+	tagged := false
+
+	for i, loc := range locs {
+		end := len(label)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+
+		num, after := label[loc[0]:loc[1]], label[loc[1]:end]
+
+		// An abbreviated count such as "1.2k" keeps its suffix.
+		if len(after) > 0 && compactMultipliers[after[0]] != 0 {
+			if r, _ := utf8.DecodeRuneInString(after[1:]); !unicode.IsLetter(r) {
+				num, after = num+after[:1], after[1:]
+			}
+		}
+
+		word := strings.ToLower(after)
+
+		switch {
+		case strings.Contains(word, "rating") && ratings == "":
+			ratings, tagged = num, true
+		case strings.Contains(word, "review") && reviews == "":
+			reviews, tagged = num, true
+		}
+	}
+
+	if tagged {
+		if ratings == "" {
+			ratings = "0"
+		}
+
+		if reviews == "" {
+			reviews = "0"
+		}
+
+		return ratings, reviews, true
+	}
+
+	if len(locs) < 2 {
+		return "", "", false
+	}
+
+	return label[locs[0][0]:locs[0][1]], label[locs[1][0]:locs[1][1]], true
+}
+
+// parseStatCount is parseLocaleInt that also accepts the abbreviated
+// "1.2k" and "3M" forms.
+func parseStatCount(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, strconv.ErrSyntax
+	}
+
+	mult, ok := compactMultipliers[s[len(s)-1]]
+	if !ok {
+		return parseLocaleInt(s)
+	}
+
+	val, err := parseLocaleFloat(s[:len(s)-1])
+	if err != nil {
+		return 0, err
+	}
+
+	return int(math.Round(val * mult)), nil
+}
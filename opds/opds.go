@@ -0,0 +1,341 @@
+// Package opds publishes a Books collection as an OPDS 1.2 catalog, the
+// Atom-based format e-reader apps such as KOReader and Thorium browse.
+package opds
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dchooyc/book"
+)
+
+const (
+	NavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	AcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+
+	RelImage      = "http://opds-spec.org/image"
+	RelThumbnail  = "http://opds-spec.org/image/thumbnail"
+	RelSubsection = "subsection"
+
+	atomNamespace = "http://www.w3.org/2005/Atom"
+	dcNamespace   = "http://purl.org/dc/terms/"
+	opdsNamespace = "http://opds-spec.org/2010/catalog"
+
+	allPath   = "all"
+	genrePath = "genre/"
+)
+
+type Link struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type Author struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type Category struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    string     `xml:"updated"`
+	Authors    []Author   `xml:"author"`
+	Identifier string     `xml:"dc:identifier,omitempty"`
+	Publisher  string     `xml:"dc:publisher,omitempty"`
+	Language   string     `xml:"dc:language,omitempty"`
+	Categories []Category `xml:"category"`
+	Summary    string     `xml:"summary,omitempty"`
+	Links      []Link     `xml:"link"`
+}
+
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	XmlnsDC string   `xml:"xmlns:dc,attr"`
+	XmlnsOP string   `xml:"xmlns:opds,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []Link   `xml:"link"`
+	Entries []Entry  `xml:"entry"`
+}
+
+// Encode writes the feed as an XML document.
+func (f *Feed) Encode(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// Catalog serves a fixed collection of books as a root navigation feed,
+// an acquisition feed of every book and one acquisition feed per genre.
+type Catalog struct {
+	title       string
+	id          string
+	basePath    string
+	updated     time.Time
+	acquisition func(book.Book) []Link
+	books       []book.Book
+}
+
+type Option func(*Catalog)
+
+// WithID sets the catalog's Atom ID, a URN or URL that stays the same
+// across regenerations.
+func WithID(id string) Option {
+	return func(c *Catalog) {
+		c.id = id
+	}
+}
+
+// WithBasePath sets where the catalog is mounted, so the links between
+// its feeds resolve. It defaults to "/".
+func WithBasePath(p string) Option {
+	return func(c *Catalog) {
+		c.basePath = p
+	}
+}
+
+func WithUpdated(t time.Time) Option {
+	return func(c *Catalog) {
+		c.updated = t
+	}
+}
+
+// WithAcquisitionLinks adds links for getting each book, such as
+// "http://opds-spec.org/acquisition/open-access" to a local EPUB. Without
+// them entries are partial and point at the Goodreads page instead.
+func WithAcquisitionLinks(fn func(book.Book) []Link) Option {
+	return func(c *Catalog) {
+		c.acquisition = fn
+	}
+}
+
+func NewCatalog(title string, books []book.Book, opts ...Option) *Catalog {
+	c := &Catalog{
+		title:    title,
+		id:       "urn:dchooyc-book:catalog",
+		basePath: "/",
+		updated:  time.Now().UTC(),
+		books:    books,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Catalog) href(rel string) string {
+	return path.Join(c.basePath, rel)
+}
+
+func (c *Catalog) feed(id, title, self, kind string) *Feed {
+	return &Feed{
+		Xmlns:   atomNamespace,
+		XmlnsDC: dcNamespace,
+		XmlnsOP: opdsNamespace,
+		ID:      id,
+		Title:   title,
+		Updated: c.stamp(),
+		Links: []Link{
+			{Rel: "self", Href: self, Type: kind},
+			{Rel: "start", Href: c.href(""), Type: NavigationType},
+		},
+	}
+}
+
+func (c *Catalog) stamp() string {
+	return c.updated.UTC().Format(time.RFC3339)
+}
+
+// Root is the navigation feed listing all books and each genre.
+func (c *Catalog) Root() *Feed {
+	f := c.feed(c.id, c.title, c.href(""), NavigationType)
+
+	f.Entries = append(f.Entries, c.navEntry(c.id+":all", "All books", c.href(allPath)))
+
+	for _, genre := range c.genres() {
+		f.Entries = append(f.Entries, c.navEntry(c.id+":genre:"+string(genre), genre.Name(), c.href(genrePath+string(genre))))
+	}
+
+	return f
+}
+
+func (c *Catalog) navEntry(id, title, href string) Entry {
+	return Entry{
+		ID:      id,
+		Title:   title,
+		Updated: c.stamp(),
+		Links:   []Link{{Rel: RelSubsection, Href: href, Type: AcquisitionType}},
+	}
+}
+
+// All is the acquisition feed of every book.
+func (c *Catalog) All() *Feed {
+	f := c.feed(c.id+":all", c.title+": all books", c.href(allPath), AcquisitionType)
+
+	for _, b := range c.books {
+		f.Entries = append(f.Entries, c.entry(b))
+	}
+
+	return f
+}
+
+// Genre is the acquisition feed of the books tagged with genre.
+func (c *Catalog) Genre(genre book.Genre) *Feed {
+	f := c.feed(c.id+":genre:"+string(genre), genre.Name(), c.href(genrePath+string(genre)), AcquisitionType)
+
+	for _, b := range c.books {
+		for _, g := range b.Genres {
+			if g == genre {
+				f.Entries = append(f.Entries, c.entry(b))
+				break
+			}
+		}
+	}
+
+	return f
+}
+
+func (c *Catalog) genres() []book.Genre {
+	seen := map[book.Genre]bool{}
+	genres := []book.Genre{}
+
+	for _, b := range c.books {
+		for _, g := range b.Genres {
+			if !seen[g] {
+				seen[g] = true
+				genres = append(genres, g)
+			}
+		}
+	}
+
+	sort.Slice(genres, func(i, j int) bool { return genres[i] < genres[j] })
+
+	return genres
+}
+
+func (c *Catalog) entry(b book.Book) Entry {
+	e := Entry{
+		ID:      entryID(b),
+		Title:   b.Title,
+		Updated: c.stamp(),
+	}
+
+	for _, a := range b.Authors {
+		e.Authors = append(e.Authors, Author{Name: a.Name(), URI: a.URL()})
+	}
+
+	for _, g := range b.Genres {
+		e.Categories = append(e.Categories, Category{Term: string(g), Label: g.Name()})
+	}
+
+	isbn := b.ISBN
+	if d := b.Details; d != nil {
+		e.Publisher, e.Language, e.Summary = d.Publisher, d.Language, d.Description
+
+		if d.ISBN13 != "" {
+			isbn = d.ISBN13
+		}
+	}
+
+	if isbn != "" {
+		e.Identifier = "urn:isbn:" + isbn
+	}
+
+	if b.CoverUrl != "" {
+		e.Links = append(e.Links,
+			Link{Rel: RelImage, Href: b.CoverUrl, Type: imageType(b.CoverUrl)},
+			Link{Rel: RelThumbnail, Href: b.CoverUrl, Type: imageType(b.CoverUrl)},
+		)
+	}
+
+	if b.URL != "" {
+		e.Links = append(e.Links, Link{Rel: "alternate", Href: b.URL, Type: "text/html", Title: "Goodreads"})
+	}
+
+	if c.acquisition != nil {
+		e.Links = append(e.Links, c.acquisition(b)...)
+	}
+
+	return e
+}
+
+func entryID(b book.Book) string {
+	switch {
+	case b.ID != "":
+		return "urn:goodreads:" + b.ID
+	case b.URL != "":
+		return b.URL
+	}
+
+	return "urn:title:" + url.PathEscape(b.Title)
+}
+
+func imageType(href string) string {
+	switch strings.ToLower(path.Ext(href)) {
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	}
+
+	return "image/jpeg"
+}
+
+// ServeHTTP serves Root at the base path, All under "all" and each genre
+// under "genre/<slug>".
+func (c *Catalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rel := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(c.basePath, "/")), "/")
+
+	var f *Feed
+	kind := AcquisitionType
+
+	switch {
+	case rel == "":
+		f, kind = c.Root(), NavigationType
+	case rel == allPath:
+		f = c.All()
+	case strings.HasPrefix(rel, genrePath):
+		genre := book.Genre(strings.TrimPrefix(rel, genrePath))
+		if f = c.Genre(genre); len(f.Entries) == 0 {
+			f = nil
+		}
+	}
+
+	if f == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", kind)
+	f.Encode(w)
+}
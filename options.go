@@ -0,0 +1,120 @@
+package book
+
+import "strings"
+
+var DefaultGenreBlacklist = []string{
+	"to-read",
+	"currently-reading",
+	"read",
+	"owned",
+	"owned-books",
+	"books-i-own",
+	"audiobook",
+	"audiobooks",
+	"audible",
+	"kindle",
+	"ebook",
+	"ebooks",
+	"favorites",
+	"wishlist",
+	"library",
+	"default",
+}
+
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	genreBlacklist map[string]bool
+	scrubUsers     bool
+	scrubSalt      string
+	mode           ParseMode
+	required       []string
+	preferJSONLD   bool
+	preferNextData bool
+	selectors      Selectors
+	rawText        bool
+	normalize      func(string) string
+}
+
+type ParseMode int
+
+const (
+	// ParseLenient returns whatever could be parsed, with errors for
+	// fields that were present but malformed. It suits bulk crawls.
+	ParseLenient ParseMode = iota
+
+	// ParseStrict returns no Book at all unless every required field was
+	// found and nothing failed to parse. It suits data-quality pipelines.
+	ParseStrict
+)
+
+func newParseConfig(opts []ParseOption) *parseConfig {
+	cfg := &parseConfig{
+		genreBlacklist: map[string]bool{},
+		required:       DefaultRequiredFields,
+		selectors:      DefaultSelectors(),
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+func WithGenreBlacklist(genres ...string) ParseOption {
+	return func(cfg *parseConfig) {
+		for _, genre := range genres {
+			cfg.genreBlacklist[strings.ToLower(genre)] = true
+		}
+	}
+}
+
+func WithParseMode(mode ParseMode) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.mode = mode
+	}
+}
+
+// WithRequiredFields sets the fields strict mode insists on, replacing
+// DefaultRequiredFields. Use the Field constants.
+func WithRequiredFields(fields ...string) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.required = fields
+	}
+}
+
+// WithPreferJSONLD takes every field the page's schema.org JSON-LD block
+// has from there, rather than only those the HTML lacks. The JSON-LD is
+// meant for machines and changes far less often than the markup.
+func WithPreferJSONLD() ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.preferJSONLD = true
+	}
+}
+
+// WithPreferNextData is WithPreferJSONLD for the page's embedded
+// __NEXT_DATA__ application state, which is also where BookDetails comes
+// from. When both are preferred, JSON-LD is applied last and wins.
+func WithPreferNextData() ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.preferNextData = true
+	}
+}
+
+func WithScrubbedReviewers(salt string) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.scrubUsers = true
+		cfg.scrubSalt = salt
+	}
+}
+
+func (cfg *parseConfig) applyReviewer(r *Reviewer) {
+	if cfg.scrubUsers {
+		r.Scrub(cfg.scrubSalt)
+	}
+}
+
+func (cfg *parseConfig) excludesGenre(genre string) bool {
+	return cfg.genreBlacklist[strings.ToLower(genre)]
+}
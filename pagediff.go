@@ -0,0 +1,164 @@
+package book
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// PageDiff is one structural difference between two snapshots of a book
+// page, located at the node an extractor depends on.
+type PageDiff struct {
+	Field  string `json:"field"`
+	Change string `json:"change"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+func (d PageDiff) String() string {
+	switch {
+	case d.Old == "":
+		return fmt.Sprintf("%s: %s: %s", d.Field, d.Change, d.New)
+	case d.New == "":
+		return fmt.Sprintf("%s: %s: %s", d.Field, d.Change, d.Old)
+	}
+
+	return fmt.Sprintf("%s: %s: %s -> %s", d.Field, d.Change, d.Old, d.New)
+}
+
+var bookPageIndicators = []struct {
+	field string
+	match func(*html.Node) bool
+}{
+	{"title", byAttr("h1", "data-testid", "bookTitle")},
+	{"url", byAttr("link", "rel", "canonical")},
+	{"id", byHref(BookIDIndicator)},
+	{"cover", byClass("", BookCoverIndicator)},
+	{"authors", byClass("div", BookAuthorsIndicator)},
+	{"genres", byHref(BookGenresIndicator)},
+	{"rating", byClass("div", BookRatingIndicator)},
+	{"stats", byClass("div", BookStatsIndicator)},
+}
+
+// DiffPages compares two snapshots of the same book page around the nodes
+// each extractor looks for. It reports indicators that disappeared or
+// appeared, moved to a different place in the tree, changed their inner
+// structure, or matched a different number of nodes. Text changes are
+// ignored; an empty result means the extractors see the same structure.
+func DiffPages(old, new io.Reader) ([]PageDiff, error) {
+	oldDoc, err := html.Parse(old)
+	if err != nil {
+		return nil, err
+	}
+
+	newDoc, err := html.Parse(new)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []PageDiff{}
+
+	for _, indicator := range bookPageIndicators {
+		oldNodes := findAll(oldDoc, indicator.match)
+		newNodes := findAll(newDoc, indicator.match)
+
+		switch {
+		case len(oldNodes) == 0 && len(newNodes) == 0:
+			continue
+		case len(newNodes) == 0:
+			diffs = append(diffs, PageDiff{Field: indicator.field, Change: "missing", Old: nodePath(oldNodes[0])})
+			continue
+		case len(oldNodes) == 0:
+			diffs = append(diffs, PageDiff{Field: indicator.field, Change: "appeared", New: nodePath(newNodes[0])})
+			continue
+		}
+
+		if oldPath, newPath := nodePath(oldNodes[0]), nodePath(newNodes[0]); oldPath != newPath {
+			diffs = append(diffs, PageDiff{Field: indicator.field, Change: "moved", Old: oldPath, New: newPath})
+		}
+
+		if oldShape, newShape := nodeShape(oldNodes[0]), nodeShape(newNodes[0]); oldShape != newShape {
+			diffs = append(diffs, PageDiff{Field: indicator.field, Change: "restructured", Old: oldShape, New: newShape})
+		}
+
+		if len(oldNodes) != len(newNodes) {
+			diffs = append(diffs, PageDiff{
+				Field:  indicator.field,
+				Change: "count",
+				Old:    fmt.Sprint(len(oldNodes)),
+				New:    fmt.Sprint(len(newNodes)),
+			})
+		}
+	}
+
+	return diffs, nil
+}
+
+func byHref(substr string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return isElement(n, "a") && strings.Contains(getAttr(n, "href"), substr)
+	}
+}
+
+// nodePath is the chain of element names and classes from the document
+// root down to n, e.g. "html>body>div.BookPage>h1.Text.Text__title1".
+func nodePath(n *html.Node) string {
+	parts := []string{}
+
+	for ; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode {
+			parts = append(parts, nodeLabel(n))
+		}
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+
+	return strings.Join(parts, ">")
+}
+
+// nodeShape renders the element structure under n without text, e.g.
+// "div.a(span,a.b(img))".
+func nodeShape(n *html.Node) string {
+	var sb strings.Builder
+	writeShape(&sb, n)
+
+	return sb.String()
+}
+
+func writeShape(sb *strings.Builder, n *html.Node) {
+	sb.WriteString(nodeLabel(n))
+
+	first := true
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+
+		if first {
+			sb.WriteByte('(')
+			first = false
+		} else {
+			sb.WriteByte(',')
+		}
+
+		writeShape(sb, c)
+	}
+
+	if !first {
+		sb.WriteByte(')')
+	}
+}
+
+func nodeLabel(n *html.Node) string {
+	label := n.Data
+
+	for _, class := range strings.Fields(getAttr(n, "class")) {
+		label += "." + class
+	}
+
+	return label
+}
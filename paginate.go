@@ -0,0 +1,161 @@
+package book
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// FetchAllList follows a list's "next" links and returns every row in one
+// List. maxPages caps the number of pages fetched; zero means no limit.
+// NextPage is left set when the limit cut the list short.
+func (c *Client) FetchAllList(ctx context.Context, url string, maxPages int) (*List, error) {
+	var all *List
+
+	err := c.paginate(ctx, url, maxPages, func(body []byte) (string, error) {
+		list, err := GetList(bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+
+		if all == nil {
+			all = list
+		} else {
+			all.Books = append(all.Books, list.Books...)
+		}
+
+		all.NextPage = list.NextPage
+
+		return list.NextPage, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+// FetchAllShelf is FetchAllList for a user's shelf.
+func (c *Client) FetchAllShelf(ctx context.Context, url string, maxPages int) (*Shelf, error) {
+	var all *Shelf
+
+	err := c.paginate(ctx, url, maxPages, func(body []byte) (string, error) {
+		shelf, err := GetShelf(bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+
+		if all == nil {
+			all = shelf
+		} else {
+			all.Rows = append(all.Rows, shelf.Rows...)
+		}
+
+		all.NextPage = shelf.NextPage
+
+		return shelf.NextPage, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+// FetchAllBookURLs collects the book URLs from a paginated page such as
+// search results, without duplicates.
+func (c *Client) FetchAllBookURLs(ctx context.Context, url string, maxPages int) ([]string, error) {
+	urls := []string{}
+	seen := map[string]bool{}
+
+	err := c.paginate(ctx, url, maxPages, func(body []byte) (string, error) {
+		pageURLs, err := GetBookURLs(bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+
+		for _, u := range pageURLs {
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+
+		doc, err := html.Parse(bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+
+		return extractNextPage(doc), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+func (c *Client) SearchAllBookURLs(ctx context.Context, query string, maxPages int) ([]string, error) {
+	return c.FetchAllBookURLs(ctx, SearchURLPrefix+url.QueryEscape(query), maxPages)
+}
+
+// paginate fetches pages starting at start, handing each body to page,
+// which returns the next page link. It stops when there is no next link,
+// a link repeats, or maxPages pages have been fetched.
+func (c *Client) paginate(ctx context.Context, start string, maxPages int, page func([]byte) (string, error)) error {
+	current, err := c.resolve(start)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+
+	for n := 0; maxPages <= 0 || n < maxPages; n++ {
+		seen[current] = true
+
+		body, err := c.Get(ctx, current)
+		if err != nil {
+			return err
+		}
+
+		next, err := page(body)
+		if err != nil {
+			return err
+		}
+
+		if next == "" {
+			return nil
+		}
+
+		base, err := url.Parse(current)
+		if err != nil {
+			return err
+		}
+
+		ref, err := url.Parse(next)
+		if err != nil {
+			return err
+		}
+
+		current = base.ResolveReference(ref).String()
+		if seen[current] {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func FetchAllList(ctx context.Context, url string, maxPages int) (*List, error) {
+	return DefaultClient.FetchAllList(ctx, url, maxPages)
+}
+
+func FetchAllShelf(ctx context.Context, url string, maxPages int) (*Shelf, error) {
+	return DefaultClient.FetchAllShelf(ctx, url, maxPages)
+}
+
+func FetchAllBookURLs(ctx context.Context, url string, maxPages int) ([]string, error) {
+	return DefaultClient.FetchAllBookURLs(ctx, url, maxPages)
+}
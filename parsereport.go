@@ -0,0 +1,221 @@
+package book
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+// DefaultRequiredFields are the fields strict mode insists on and
+// ParseReport.Degraded checks when given none: a book page missing any of
+// them means the layout moved.
+var DefaultRequiredFields = []string{FieldTitle, FieldURL, FieldRating}
+
+var bookReportFields = []string{
+	FieldTitle, FieldURL, FieldID, FieldCover, FieldAuthors, FieldGenres, FieldRating, FieldRatings, FieldReviews, FieldISBN, FieldDetails,
+}
+
+// FieldReport is the outcome for one field. Found means the field ended
+// up with a non-zero value, or for the rating and counts that the page
+// gave one, zero included; Source names the selector it came from; Err
+// is set, and Error to its text, when the field was on the page but
+// failed to parse.
+type FieldReport struct {
+	Field  string `json:"field"`
+	Found  bool   `json:"found"`
+	Source string `json:"source,omitempty"`
+	Err    error  `json:"-"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ParseReport records what a parse found and where, so a pipeline can
+// notice a scrape that still succeeds but has quietly lost fields after
+// a Goodreads layout change.
+type ParseReport struct {
+	Layout BookLayout    `json:"layout"`
+	Fields []FieldReport `json:"fields"`
+}
+
+// GetBookWithReport is GetBook plus a report of which fields were found.
+// The report is nil only when the page couldn't be parsed at all or was a
+// block page; in strict mode a rejected Book still comes with its report.
+func GetBookWithReport(r io.Reader, opts ...ParseOption) (*Book, *ParseReport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg := newParseConfig(opts)
+	book := &Book{}
+	errs := []error{}
+
+	sources := map[string]string{}
+
+	layout := detectBookLayout(doc, cfg)
+
+	switch layout {
+	case LayoutLegacy:
+		extractLegacyBook(doc, book, cfg, sources, &errs)
+	case LayoutMobile:
+		extractMobileBook(doc, book, cfg, sources, &errs)
+	default:
+		extractBookInfo(doc, book, cfg, sources, &errs)
+	}
+
+	applyNextData(doc, book, cfg, sources)
+	applyJSONLD(doc, book, cfg, sources)
+	cfg.normalizeBook(book)
+
+	if book.Title == "" {
+		if block, ok := DetectBlock(0, nil, data); ok {
+			return nil, nil, block
+		}
+
+		if gate, ok := DetectGate("", data); ok {
+			return nil, nil, gate
+		}
+	}
+
+	errs = recoveredErrors(errs, sources)
+	report := newParseReport(book, sources, cfg, errors.Join(errs...))
+	report.Layout = layout
+
+	if cfg.mode == ParseStrict {
+		for _, field := range cfg.required {
+			if fr, ok := report.Field(field); ok && !fr.Found && fr.Err == nil {
+				errs = append(errs, newFieldError(field, ErrMissingField, ""))
+			}
+		}
+
+		if len(errs) > 0 {
+			return nil, report, errors.Join(errs...)
+		}
+	}
+
+	return book, report, errors.Join(errs...)
+}
+
+// recoveredErrors drops the errors for fields another source went on to
+// fill, since the Book no longer lacks them.
+func recoveredErrors(errs []error, sources map[string]string) []error {
+	kept := []error{}
+
+	for _, err := range errs {
+		fe, ok := err.(*FieldError)
+		if ok && sources[fe.Field] != "" {
+			continue
+		}
+
+		if ok && fe.Field == FieldStats && sources[FieldRatings] != "" && sources[FieldReviews] != "" {
+			continue
+		}
+
+		kept = append(kept, err)
+	}
+
+	return kept
+}
+
+func newParseReport(b *Book, sources map[string]string, cfg *parseConfig, err error) *ParseReport {
+	failed := map[string]error{}
+	for _, fe := range FieldErrors(err) {
+		failed[fe.Field] = fe
+
+		// A stats label that can't be split loses both counts.
+		if fe.Field == FieldStats {
+			failed[FieldRatings] = fe
+			failed[FieldReviews] = fe
+		}
+	}
+
+	found := map[string]bool{
+		FieldTitle:   b.Title != "",
+		FieldURL:     b.URL != "",
+		FieldID:      b.ID != "",
+		FieldCover:   b.CoverUrl != "",
+		FieldAuthors: len(b.Authors) > 0,
+		FieldGenres:  len(b.Genres) > 0,
+		FieldRating:  b.Rating != 0 || sources[FieldRating] != "",
+		FieldRatings: b.Ratings != 0 || sources[FieldRatings] != "",
+		FieldReviews: b.Reviews != 0 || sources[FieldReviews] != "",
+		FieldISBN:    b.ISBN != "",
+		FieldDetails: b.Details != nil,
+	}
+
+	selectors := cfg.selectors.describe()
+	report := &ParseReport{}
+
+	for _, field := range bookReportFields {
+		fr := FieldReport{Field: field, Found: found[field], Err: failed[field]}
+		if fr.Found {
+			fr.Source = sources[field]
+			if fr.Source == "" {
+				fr.Source = selectors[field]
+			}
+		}
+
+		if fr.Err != nil {
+			fr.Error = fr.Err.Error()
+		}
+
+		report.Fields = append(report.Fields, fr)
+	}
+
+	return report
+}
+
+func (r *ParseReport) Field(name string) (FieldReport, bool) {
+	for _, fr := range r.Fields {
+		if fr.Field == name {
+			return fr, true
+		}
+	}
+
+	return FieldReport{}, false
+}
+
+func (r *ParseReport) Found() []string {
+	fields := []string{}
+	for _, fr := range r.Fields {
+		if fr.Found {
+			fields = append(fields, fr.Field)
+		}
+	}
+
+	return fields
+}
+
+func (r *ParseReport) Missing() []string {
+	fields := []string{}
+	for _, fr := range r.Fields {
+		if !fr.Found {
+			fields = append(fields, fr.Field)
+		}
+	}
+
+	return fields
+}
+
+// Degraded reports whether any of the required fields, or
+// DefaultRequiredFields if none are given, is missing or failed to parse.
+func (r *ParseReport) Degraded(required ...string) bool {
+	if len(required) == 0 {
+		required = DefaultRequiredFields
+	}
+
+	for _, name := range required {
+		fr, ok := r.Field(name)
+		if !ok || !fr.Found || fr.Err != nil {
+			return true
+		}
+	}
+
+	return false
+}
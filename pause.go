@@ -0,0 +1,145 @@
+package book
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxPause         = time.Hour
+	defaultMaintenancePause = 5 * time.Minute
+	maxConsecutivePauses    = 5
+)
+
+var maintenanceMarkers = [][]byte{
+	[]byte("down for maintenance"),
+	[]byte("scheduled maintenance"),
+	[]byte("is over capacity"),
+}
+
+type PauseEvent struct {
+	URL        string
+	Until      time.Time
+	Reason     string
+	StatusCode int
+}
+
+type MaintenanceError struct {
+	URL    string
+	Until  time.Time
+	Reason string
+}
+
+func (e *MaintenanceError) Error() string {
+	return fmt.Sprintf("fetching %s: %s until %s", e.URL, e.Reason, e.Until.Format(time.RFC3339))
+}
+
+func WithMaxPause(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxPause = d
+	}
+}
+
+func WithPauseHandler(fn func(PauseEvent)) ClientOption {
+	return func(c *Client) {
+		c.onPause = fn
+	}
+}
+
+func (c *Client) PausedUntil() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pausedUntil
+}
+
+func (c *Client) pause(event PauseEvent) {
+	c.mu.Lock()
+	if event.Until.After(c.pausedUntil) {
+		c.pausedUntil = event.Until
+	}
+	c.mu.Unlock()
+
+	if c.onPause != nil {
+		c.onPause(event)
+	}
+}
+
+func (c *Client) pauseSignal(resp *response) (time.Time, string, bool) {
+	now := time.Now()
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.header.Get("Retry-After"), now)
+
+	if isMaintenancePage(resp.statusCode, resp.body) {
+		if !hasRetryAfter {
+			retryAfter = defaultMaintenancePause
+		}
+
+		return now.Add(retryAfter), "site maintenance", true
+	}
+
+	if hasRetryAfter && (resp.statusCode == http.StatusTooManyRequests || resp.statusCode == http.StatusServiceUnavailable) {
+		return now.Add(retryAfter), http.StatusText(resp.statusCode), true
+	}
+
+	return time.Time{}, "", false
+}
+
+func parseRetryAfter(val string, now time.Time) (time.Duration, bool) {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(val); err == nil {
+		if secs < 0 {
+			secs = 0
+		}
+
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(val); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+
+		return d, true
+	}
+
+	return 0, false
+}
+
+func isMaintenancePage(statusCode int, body []byte) bool {
+	lower := bytes.ToLower(body)
+
+	if statusCode < http.StatusInternalServerError {
+		lower = pageTitle(lower)
+	}
+
+	for _, marker := range maintenanceMarkers {
+		if bytes.Contains(lower, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func pageTitle(lowerBody []byte) []byte {
+	start := bytes.Index(lowerBody, []byte("<title"))
+	if start < 0 {
+		return nil
+	}
+
+	end := bytes.Index(lowerBody[start:], []byte("</title>"))
+	if end < 0 {
+		return nil
+	}
+
+	return lowerBody[start : start+end]
+}
@@ -0,0 +1,41 @@
+package book_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+func TestProxyPoolWithCustomTransportFails(t *testing.T) {
+	pool := book.NewProxyPool(book.RoundRobin)
+	if err := pool.Add("http://127.0.0.1:3128", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	hc := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return nil, errors.New("unreachable")
+	})}
+
+	c := book.NewClient(book.WithHTTPClient(hc), book.WithProxyPool(pool))
+
+	if err := c.Err(); !errors.Is(err, book.ErrCustomTransport) {
+		t.Errorf("Err() = %v, want ErrCustomTransport", err)
+	}
+
+	if _, err := c.FetchBook(context.Background(), "/book/show/1"); !errors.Is(err, book.ErrCustomTransport) {
+		t.Errorf("FetchBook error = %v, want ErrCustomTransport", err)
+	}
+
+	if called {
+		t.Error("request bypassed the proxy pool")
+	}
+
+	if stats := pool.Stats(); stats[0].Requests != 0 {
+		t.Errorf("pool served %d requests, want 0", stats[0].Requests)
+	}
+}
@@ -0,0 +1,94 @@
+package book
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	QuoteIndicator       = "quote"
+	QuoteTextIndicator   = "quoteText"
+	QuoteFooterIndicator = "quoteFooter"
+	QuoteSourceIndicator = "authorOrTitle"
+	QuoteTagIndicator    = "/quotes/tag/"
+	QuoteURLIndicator    = "/quotes/"
+	QuoteSeparator       = "―"
+)
+
+type QuotesPage struct {
+	Quotes   []Quote `json:"quotes"`
+	NextPage string  `json:"next_page,omitempty"`
+}
+
+type Quote struct {
+	Text   string   `json:"text"`
+	Author string   `json:"author"`
+	Work   string   `json:"work,omitempty"`
+	URL    string   `json:"url"`
+	ID     string   `json:"id"`
+	Likes  int      `json:"likes"`
+	Tags   []string `json:"tags"`
+}
+
+func GetQuotes(r io.Reader) (*QuotesPage, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &QuotesPage{
+		Quotes:   []Quote{},
+		NextPage: extractNextPage(doc),
+	}
+
+	for _, n := range findAll(doc, byClass("div", QuoteIndicator)) {
+		quote, ok := extractQuote(n)
+		if ok {
+			page.Quotes = append(page.Quotes, quote)
+		}
+	}
+
+	return page, nil
+}
+
+func extractQuote(n *html.Node) (Quote, bool) {
+	quote := Quote{Tags: []string{}}
+
+	textNode := findFirst(n, byClass("div", QuoteTextIndicator))
+	if textNode == nil {
+		return quote, false
+	}
+
+	text, _, _ := strings.Cut(textContent(textNode), QuoteSeparator)
+	quote.Text = strings.Trim(strings.TrimSpace(text), "“”\"")
+
+	if span := findFirst(textNode, byClass("span", QuoteSourceIndicator)); span != nil {
+		quote.Author = strings.TrimSuffix(textContent(span), ",")
+	}
+
+	if a := findFirst(textNode, byClass("a", QuoteSourceIndicator)); a != nil {
+		quote.Work = textContent(a)
+	}
+
+	footer := findFirst(n, byClass("div", QuoteFooterIndicator))
+	if footer == nil {
+		return quote, true
+	}
+
+	for _, a := range findAll(footer, func(n *html.Node) bool { return isElement(n, "a") }) {
+		href := getAttr(a, "href")
+
+		switch {
+		case strings.Contains(href, QuoteTagIndicator):
+			quote.Tags = append(quote.Tags, textContent(a))
+		case strings.Contains(href, QuoteURLIndicator):
+			quote.URL = href
+			quote.ID = leadingDigits(lastPathSegment(href))
+			quote.Likes = parseCount(textContent(a))
+		}
+	}
+
+	return quote, true
+}
@@ -0,0 +1,100 @@
+package book
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket shared by every request that goes through
+// it. It is safe for concurrent use, and one limiter can be handed to
+// several Clients so that together they stay under a single budget.
+type RateLimiter struct {
+	rate   float64
+	burst  float64
+	jitter time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allows rps requests per second on average with bursts of
+// up to burst requests. Each wait is stretched by a random delay of up to
+// jitter so that requests don't land on a fixed cadence.
+func NewRateLimiter(rps float64, burst int, jitter time.Duration) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		jitter: jitter,
+		tokens: float64(burst),
+	}
+}
+
+func WithRateLimiter(l *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+func WithRateLimit(rps float64, burst int, jitter time.Duration) ClientOption {
+	return WithRateLimiter(NewRateLimiter(rps, burst, jitter))
+}
+
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	delay := l.reserve(time.Now())
+
+	if l.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(l.jitter)))
+	}
+
+	if err := sleepUntil(ctx, time.Now().Add(delay)); err != nil {
+		l.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// reserve takes a token, letting the bucket go negative so that concurrent
+// callers queue up behind each other, and returns how long the caller has
+// to wait for its token to be earned.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
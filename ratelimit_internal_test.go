@@ -0,0 +1,99 @@
+package book
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	l := NewRateLimiter(10, 3, 0)
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// The burst is free, then each request waits for its own token.
+	want := []time.Duration{0, 0, 0, 100 * time.Millisecond, 200 * time.Millisecond}
+	for i, w := range want {
+		if got := l.reserve(now); got != w {
+			t.Errorf("request %d waits %v, want %v", i, got, w)
+		}
+	}
+
+	// A long idle spell refills the bucket only up to the burst.
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if got := l.reserve(now); got != 0 {
+			t.Errorf("after idling, request %d waits %v, want 0", i, got)
+		}
+	}
+
+	if got := l.reserve(now); got != 100*time.Millisecond {
+		t.Errorf("request past the burst waits %v, want 100ms", got)
+	}
+
+	// A cancelled wait gives its token back to the next caller.
+	l.cancel()
+	if got := l.reserve(now); got != 100*time.Millisecond {
+		t.Errorf("after a cancel, request waits %v, want 100ms", got)
+	}
+}
+
+func TestRateLimiterSharedAcrossGoroutines(t *testing.T) {
+	l := NewRateLimiter(200, 1, 0)
+	ctx := context.Background()
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 5; j++ {
+				if err := l.Wait(ctx); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// 20 requests at 200 per second with a burst of one take at least
+	// 19 intervals of 5ms however the goroutines interleave.
+	if elapsed := time.Since(start); elapsed < 95*time.Millisecond {
+		t.Errorf("20 requests took %v, want at least 95ms", elapsed)
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := NewRateLimiter(1, 1, 0)
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait = %v, want DeadlineExceeded", err)
+	}
+
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+
+	if tokens < -0.1 || tokens > 0.1 {
+		t.Errorf("tokens = %v after a cancelled wait, want the token refunded to about 0", tokens)
+	}
+}
+
+func TestNilRateLimiterDoesNotWait(t *testing.T) {
+	var l *RateLimiter
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("Wait = %v, want nil", err)
+	}
+}
@@ -0,0 +1,70 @@
+package book
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	RecommendationsURLIndicator     = "/recommendations"
+	RecommendationSectionIndicator  = "recommendationSection"
+	RecommendationGenreURLIndicator = "/recommendations/genre/"
+	RecommendationShelfURLIndicator = "/recommendations/shelf/"
+)
+
+type Recommendations struct {
+	Sections []RecommendationSection `json:"sections"`
+}
+
+type RecommendationSection struct {
+	Title string    `json:"title"`
+	Kind  string    `json:"kind"`
+	Name  string    `json:"name"`
+	URL   string    `json:"url"`
+	Books []BookRef `json:"books"`
+}
+
+func GetRecommendations(r io.Reader) (*Recommendations, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	recs := &Recommendations{
+		Sections: []RecommendationSection{},
+	}
+
+	for _, n := range findAll(doc, byClass("", RecommendationSectionIndicator)) {
+		section := RecommendationSection{
+			Books: extractSectionRefs(n),
+		}
+
+		if h2 := findFirst(n, func(n *html.Node) bool { return isElement(n, "h2") }); h2 != nil {
+			section.Title = textContent(h2)
+		}
+
+		link := findFirst(n, func(n *html.Node) bool {
+			href := getAttr(n, "href")
+			return isElement(n, "a") && (strings.Contains(href, RecommendationGenreURLIndicator) ||
+				strings.Contains(href, RecommendationShelfURLIndicator))
+		})
+		if link != nil {
+			section.URL = getAttr(link, "href")
+			section.Name = lastPathSegment(section.URL)
+
+			if strings.Contains(section.URL, RecommendationGenreURLIndicator) {
+				section.Kind = "genre"
+			} else {
+				section.Kind = "shelf"
+			}
+		}
+
+		if len(section.Books) > 0 {
+			recs.Sections = append(recs.Sections, section)
+		}
+	}
+
+	return recs, nil
+}
@@ -0,0 +1,199 @@
+package book
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const recordingSuffix = ".html.gz"
+
+// recordingTimeLayout sorts lexically in time order, so a directory
+// listing is already oldest first.
+const recordingTimeLayout = "20060102T150405.000000000Z"
+
+// Recorder receives the raw body of every page the Client fetches, before
+// any parsing. *DirRecorder and *snapshot.Archive both implement it.
+type Recorder interface {
+	Record(url string, t time.Time, body []byte) error
+}
+
+// WithRecorder archives every 200 response through r. A failure to
+// record fails the Get, so a fixture capture never silently misses pages.
+func WithRecorder(r Recorder) ClientOption {
+	return func(c *Client) {
+		c.recorder = r
+	}
+}
+
+// DirRecorder keeps each recorded response as its own gzip file under dir,
+// one directory per URL, named by the time it was fetched. The URL and
+// time are also written into the gzip header, so a file copied out of
+// the tree on its own still says where it came from.
+type DirRecorder struct {
+	dir string
+}
+
+func NewDirRecorder(dir string) (*DirRecorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &DirRecorder{dir: dir}, nil
+}
+
+func (d *DirRecorder) Record(url string, t time.Time, body []byte) error {
+	t = t.UTC()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	gz.ModTime = t
+
+	// The gzip header only holds Latin-1; resolved URLs are escaped ASCII.
+	if isASCII(url) {
+		gz.Name = url
+	}
+
+	if _, err := gz.Write(body); err != nil {
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	path := filepath.Join(d.urlDir(url), t.Format(recordingTimeLayout)+recordingSuffix)
+
+	return writeFileAtomic(path, buf.Bytes(), t)
+}
+
+// Versions lists the times url was recorded, oldest first.
+func (d *DirRecorder) Versions(url string) ([]time.Time, error) {
+	entries, err := os.ReadDir(d.urlDir(url))
+	if errors.Is(err, os.ErrNotExist) {
+		return []time.Time{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	times := []time.Time{}
+
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), recordingSuffix)
+		if !ok {
+			continue
+		}
+
+		if t, err := time.Parse(recordingTimeLayout, name); err == nil {
+			times = append(times, t)
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	return times, nil
+}
+
+// Read returns the body recorded for url at t.
+func (d *DirRecorder) Read(url string, t time.Time) ([]byte, error) {
+	path := filepath.Join(d.urlDir(url), t.UTC().Format(recordingTimeLayout)+recordingSuffix)
+
+	body, _, err := readRecording(path)
+
+	return body, err
+}
+
+// Latest returns the most recent body recorded for url, or ErrNotFound.
+func (d *DirRecorder) Latest(url string) ([]byte, time.Time, error) {
+	times, err := d.Versions(url)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if len(times) == 0 {
+		return nil, time.Time{}, ErrNotFound
+	}
+
+	t := times[len(times)-1]
+	body, err := d.Read(url, t)
+
+	return body, t, err
+}
+
+// Walk calls fn for every recording under the directory, which is how a
+// capture is re-parsed offline.
+func (d *DirRecorder) Walk(fn func(url string, t time.Time, body []byte) error) error {
+	return filepath.WalkDir(d.dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, ok := strings.CutSuffix(e.Name(), recordingSuffix)
+		if e.IsDir() || !ok {
+			return nil
+		}
+
+		t, err := time.Parse(recordingTimeLayout, name)
+		if err != nil {
+			return nil
+		}
+
+		body, url, err := readRecording(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		return fn(url, t, body)
+	})
+}
+
+func (d *DirRecorder) urlDir(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	name := hex.EncodeToString(sum[:])
+
+	return filepath.Join(d.dir, name[:2], name)
+}
+
+func readRecording(path string) ([]byte, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, "", err
+	}
+	defer gz.Close()
+
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, gz.Name, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,283 @@
+package book
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+const defaultRefreshInterval = time.Hour
+
+// RefreshTier is a freshness SLA: every corpus entry with at least
+// MinRatings ratings should have been fetched within MaxAge. An entry
+// belongs to the first tier it qualifies for, so tiers are listed from
+// most to least demanding.
+type RefreshTier struct {
+	Name       string
+	MinRatings int
+	MaxAge     time.Duration
+}
+
+var DefaultRefreshTiers = []RefreshTier{
+	{Name: "popular", MinRatings: 10000, MaxAge: 24 * time.Hour},
+	{Name: "long tail", MinRatings: 0, MaxAge: 30 * 24 * time.Hour},
+}
+
+type RefreshItem struct {
+	URL         string
+	Ratings     int
+	LastFetched time.Time
+}
+
+// RefreshStore is the corpus a Refresher keeps fresh.
+type RefreshStore interface {
+	RefreshItems() ([]RefreshItem, error)
+	SaveRefreshed(b *Book, fetched time.Time) error
+}
+
+type RefreshReport struct {
+	Started   time.Time
+	Planned   int
+	Refreshed int
+	Failed    int
+	Tiers     []TierAttainment
+}
+
+// TierAttainment is the share of a tier's entries that met the SLA at the
+// end of a refresh cycle.
+type TierAttainment struct {
+	Tier       string
+	Total      int
+	Fresh      int
+	Attainment float64
+}
+
+type Refresher struct {
+	fetcher  Fetcher
+	store    RefreshStore
+	tiers    []RefreshTier
+	budget   int
+	interval time.Duration
+	onReport func(RefreshReport)
+	onError  func(url string, err error)
+	feed     *ChangefeedWriter
+	now      func() time.Time
+}
+
+type RefresherOption func(*Refresher)
+
+func WithRefreshTiers(tiers ...RefreshTier) RefresherOption {
+	return func(r *Refresher) {
+		r.tiers = tiers
+	}
+}
+
+// WithRefreshBudget caps the number of fetches per cycle. Zero means no
+// cap.
+func WithRefreshBudget(n int) RefresherOption {
+	return func(r *Refresher) {
+		r.budget = n
+	}
+}
+
+func WithRefreshInterval(d time.Duration) RefresherOption {
+	return func(r *Refresher) {
+		r.interval = d
+	}
+}
+
+func WithRefreshReportHandler(fn func(RefreshReport)) RefresherOption {
+	return func(r *Refresher) {
+		r.onReport = fn
+	}
+}
+
+func WithRefreshErrorHandler(fn func(url string, err error)) RefresherOption {
+	return func(r *Refresher) {
+		r.onError = fn
+	}
+}
+
+// WithRefreshChangefeed emits a changefeed event for every refreshed book
+// whose content changed.
+func WithRefreshChangefeed(w *ChangefeedWriter) RefresherOption {
+	return func(r *Refresher) {
+		r.feed = w
+	}
+}
+
+func NewRefresher(fetcher Fetcher, store RefreshStore, opts ...RefresherOption) *Refresher {
+	r := &Refresher{
+		fetcher:  fetcher,
+		store:    store,
+		tiers:    DefaultRefreshTiers,
+		interval: defaultRefreshInterval,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run refreshes the corpus once per interval until ctx is done.
+func (r *Refresher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce plans a batch within the budget, fetches it and reports SLA
+// attainment per tier. Individual fetch failures are counted, not
+// returned; only store errors and cancellation end the cycle early.
+func (r *Refresher) RunOnce(ctx context.Context) (RefreshReport, error) {
+	report := RefreshReport{Started: r.now()}
+
+	items, err := r.store.RefreshItems()
+	if err != nil {
+		return report, err
+	}
+
+	plan := r.Plan(items, report.Started)
+	report.Planned = len(plan)
+
+	fetched := map[string]time.Time{}
+
+	for _, item := range plan {
+		b, err := r.fetcher.FetchBook(ctx, item.URL)
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+
+			report.Failed++
+
+			if r.onError != nil {
+				r.onError(item.URL, err)
+			}
+
+			continue
+		}
+
+		now := r.now()
+		if err := r.store.SaveRefreshed(b, now); err != nil {
+			return report, err
+		}
+
+		if r.feed != nil {
+			if _, err := r.feed.Observe(*b); err != nil {
+				return report, err
+			}
+		}
+
+		fetched[item.URL] = now
+		report.Refreshed++
+	}
+
+	for i := range items {
+		if t, ok := fetched[items[i].URL]; ok {
+			items[i].LastFetched = t
+		}
+	}
+
+	report.Tiers = r.Attainment(items, r.now())
+
+	if r.onReport != nil {
+		r.onReport(report)
+	}
+
+	return report, nil
+}
+
+// Plan returns the stale items, most overdue first relative to their tier's
+// MaxAge, truncated to the budget.
+func (r *Refresher) Plan(items []RefreshItem, now time.Time) []RefreshItem {
+	type candidate struct {
+		item    RefreshItem
+		overdue float64
+	}
+
+	candidates := []candidate{}
+
+	for _, item := range items {
+		tier, ok := r.tierFor(item)
+		if !ok || tier.MaxAge <= 0 {
+			continue
+		}
+
+		age := now.Sub(item.LastFetched)
+		if age < tier.MaxAge {
+			continue
+		}
+
+		candidates = append(candidates, candidate{item, float64(age) / float64(tier.MaxAge)})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].overdue > candidates[j].overdue
+	})
+
+	if r.budget > 0 && len(candidates) > r.budget {
+		candidates = candidates[:r.budget]
+	}
+
+	plan := make([]RefreshItem, len(candidates))
+	for i, c := range candidates {
+		plan[i] = c.item
+	}
+
+	return plan
+}
+
+func (r *Refresher) Attainment(items []RefreshItem, now time.Time) []TierAttainment {
+	attainment := make([]TierAttainment, len(r.tiers))
+	for i, tier := range r.tiers {
+		attainment[i].Tier = tier.Name
+	}
+
+	for _, item := range items {
+		for i, tier := range r.tiers {
+			if item.Ratings < tier.MinRatings {
+				continue
+			}
+
+			attainment[i].Total++
+			if now.Sub(item.LastFetched) < tier.MaxAge {
+				attainment[i].Fresh++
+			}
+
+			break
+		}
+	}
+
+	for i := range attainment {
+		if attainment[i].Total > 0 {
+			attainment[i].Attainment = float64(attainment[i].Fresh) / float64(attainment[i].Total)
+		}
+	}
+
+	return attainment
+}
+
+func (r *Refresher) tierFor(item RefreshItem) (RefreshTier, bool) {
+	for _, tier := range r.tiers {
+		if item.Ratings >= tier.MinRatings {
+			return tier, true
+		}
+	}
+
+	return RefreshTier{}, false
+}
@@ -0,0 +1,345 @@
+package book
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]string
+}
+
+// BooksTable flattens books into a single table, one row per book, with
+// multi-valued fields joined by "|" and any "|" or "\" inside a value
+// escaped with a backslash. The detail columns are empty for books
+// without details; ReadBooksCSV reads the table back, leaving out only
+// the details' reviews.
+func BooksTable(books []Book) Table {
+	table := Table{
+		Name: "books",
+		Columns: []string{
+			"title", "url", "id", "cover_url", "authors", "genres", "rating", "ratings", "reviews",
+			"author_ids", "isbn",
+			"title_complete", "original_title", "description", "pages", "format", "publisher",
+			"published", "first_published", "language", "isbn10", "isbn13", "asin", "ratings_histogram",
+		},
+	}
+
+	for _, b := range books {
+		table.Rows = append(table.Rows, bookRow(b))
+	}
+
+	return table
+}
+
+func bookRow(b Book) []string {
+	row := []string{
+		b.Title,
+		b.URL,
+		b.ID,
+		b.CoverUrl,
+		joinList(b.AuthorNames()),
+		joinList(b.GenreSlugs()),
+		strconv.FormatFloat(b.Rating, 'f', -1, 64),
+		strconv.Itoa(b.Ratings),
+		strconv.Itoa(b.Reviews),
+		joinList(authorIDs(b)),
+		b.ISBN,
+	}
+
+	d := b.Details
+	if d == nil {
+		return append(row, make([]string, 13)...)
+	}
+
+	histogram := make([]string, len(d.RatingsHistogram))
+	for i, n := range d.RatingsHistogram {
+		histogram[i] = strconv.Itoa(n)
+	}
+
+	return append(row,
+		d.TitleComplete,
+		d.OriginalTitle,
+		d.Description,
+		strconv.Itoa(d.Pages),
+		d.Format,
+		d.Publisher,
+		d.Published,
+		d.FirstPublished,
+		d.Language,
+		d.ISBN10,
+		d.ISBN13,
+		d.ASIN,
+		joinList(histogram),
+	)
+}
+
+// authorIDs lists the authors' IDs in order, or nothing if none is known.
+func authorIDs(b Book) []string {
+	ids := make([]string, len(b.Authors))
+	known := false
+
+	for i, author := range b.Authors {
+		ids[i] = author.ID()
+		known = known || ids[i] != ""
+	}
+
+	if !known {
+		return nil
+	}
+
+	return ids
+}
+
+// ReadBooksCSV reads a books table written by BooksTable back into books.
+func ReadBooksCSV(r io.Reader) ([]Book, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := BooksTable(nil).Columns
+	if len(records) == 0 || strings.Join(records[0], ",") != strings.Join(columns, ",") {
+		return nil, fmt.Errorf("books CSV header: %w", ErrUnexpectedFormat)
+	}
+
+	books := make([]Book, 0, len(records)-1)
+	for i, row := range records[1:] {
+		b, err := bookFromRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("books CSV line %d: %w", i+2, err)
+		}
+
+		books = append(books, b)
+	}
+
+	return books, nil
+}
+
+func bookFromRow(row []string) (Book, error) {
+	b := Book{
+		Title:    row[0],
+		URL:      row[1],
+		ID:       row[2],
+		CoverUrl: row[3],
+		ISBN:     row[10],
+	}
+
+	var err error
+	if b.Rating, err = strconv.ParseFloat(row[6], 64); err != nil {
+		return Book{}, err
+	}
+
+	if b.Ratings, err = strconv.Atoi(row[7]); err != nil {
+		return Book{}, err
+	}
+
+	if b.Reviews, err = strconv.Atoi(row[8]); err != nil {
+		return Book{}, err
+	}
+
+	ids := splitList(row[9])
+	for i, name := range splitList(row[4]) {
+		var id string
+		if i < len(ids) {
+			id = ids[i]
+		}
+
+		b.Authors = append(b.Authors, NewAuthorRef(name, id))
+	}
+
+	for _, slug := range splitList(row[5]) {
+		b.Genres = append(b.Genres, Genre(slug))
+	}
+
+	// Pages is always written for a book with details, so an empty cell
+	// means there were none.
+	if row[14] == "" {
+		return b, nil
+	}
+
+	d := &BookDetails{
+		TitleComplete:  row[11],
+		OriginalTitle:  row[12],
+		Description:    row[13],
+		Format:         row[15],
+		Publisher:      row[16],
+		Published:      row[17],
+		FirstPublished: row[18],
+		Language:       row[19],
+		ISBN10:         row[20],
+		ISBN13:         row[21],
+		ASIN:           row[22],
+	}
+
+	if d.Pages, err = strconv.Atoi(row[14]); err != nil {
+		return Book{}, err
+	}
+
+	for _, count := range splitList(row[23]) {
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return Book{}, err
+		}
+
+		d.RatingsHistogram = append(d.RatingsHistogram, n)
+	}
+
+	b.Details = d
+
+	return b, nil
+}
+
+var listEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`)
+
+func joinList(values []string) string {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = listEscaper.Replace(v)
+	}
+
+	return strings.Join(escaped, "|")
+}
+
+// splitList undoes joinList. An empty cell is an empty list.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var values []string
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			sb.WriteByte(s[i])
+		case c == '|':
+			values = append(values, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return append(values, sb.String())
+}
+
+// RelationalTables normalizes books into works, editions, authors and
+// genres, with book_authors and book_genres joining them. author_id and
+// genre_id are keys local to the export; an author's Goodreads ID, when
+// known, is in goodreads_id.
+func RelationalTables(books []Book) []Table {
+	works := Table{Name: "works", Columns: []string{"work_id", "title", "rating", "ratings", "reviews"}}
+	editions := Table{Name: "editions", Columns: []string{"edition_id", "work_id", "url", "title", "cover_url"}}
+	authors := Table{Name: "authors", Columns: []string{"author_id", "goodreads_id", "name"}}
+	genres := Table{Name: "genres", Columns: []string{"genre_id", "slug"}}
+	bookGenres := Table{Name: "book_genres", Columns: []string{"work_id", "genre_id"}}
+	bookAuthors := Table{Name: "book_authors", Columns: []string{"work_id", "author_id", "position"}}
+
+	authorIDs, genreIDs := map[string]string{}, map[string]string{}
+	seenWorks, seenEditions := map[string]bool{}, map[string]bool{}
+
+	for _, b := range books {
+		editionID := bookIDFromURL(b.URL)
+
+		workID := b.ID
+		if workID == "" {
+			workID = editionID
+		}
+
+		if workID == "" {
+			continue
+		}
+
+		if editionID != "" && !seenEditions[editionID] {
+			seenEditions[editionID] = true
+			editions.Rows = append(editions.Rows, []string{editionID, workID, b.URL, b.Title, b.CoverUrl})
+		}
+
+		if seenWorks[workID] {
+			continue
+		}
+
+		seenWorks[workID] = true
+
+		works.Rows = append(works.Rows, []string{
+			workID,
+			b.Title,
+			strconv.FormatFloat(b.Rating, 'f', -1, 64),
+			strconv.Itoa(b.Ratings),
+			strconv.Itoa(b.Reviews),
+		})
+
+		for i, author := range b.Authors {
+			// Authors are told apart by Goodreads ID, so namesakes get a
+			// row each; a ref without one matches an author by name.
+			key := "name:" + author.Name()
+			if author.ID() != "" {
+				key = "id:" + author.ID()
+			}
+
+			id, ok := authorIDs[key]
+			if !ok {
+				id = strconv.Itoa(len(authors.Rows) + 1)
+				authorIDs[key] = id
+				authors.Rows = append(authors.Rows, []string{id, author.ID(), author.Name()})
+			}
+
+			if _, ok := authorIDs["name:"+author.Name()]; !ok {
+				authorIDs["name:"+author.Name()] = id
+			}
+
+			bookAuthors.Rows = append(bookAuthors.Rows, []string{workID, id, strconv.Itoa(i + 1)})
+		}
+
+		for _, slug := range b.GenreSlugs() {
+			id, ok := genreIDs[slug]
+			if !ok {
+				id = strconv.Itoa(len(genreIDs) + 1)
+				genreIDs[slug] = id
+				genres.Rows = append(genres.Rows, []string{id, slug})
+			}
+
+			bookGenres.Rows = append(bookGenres.Rows, []string{workID, id})
+		}
+	}
+
+	return []Table{works, editions, authors, genres, bookGenres, bookAuthors}
+}
+
+func ExportRelational(dir string, books []Book) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, table := range RelationalTables(books) {
+		if err := table.writeCSV(filepath.Join(dir, table.Name+".csv")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t Table) writeCSV(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := t.WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
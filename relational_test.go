@@ -0,0 +1,47 @@
+package book_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+func TestRelationalTablesAuthors(t *testing.T) {
+	books := []book.Book{
+		{ID: "1", Title: "Lord of Light", Authors: []book.AuthorRef{book.NewAuthorRef("Roger Zelazny", "3619")}},
+		{ID: "2", Title: "Persuasion", Authors: []book.AuthorRef{book.NewAuthorRef("Jane Austen", "1265")}},
+		{ID: "3", Title: "Namesake", Authors: []book.AuthorRef{book.NewAuthorRef("Jane Austen", "9999")}},
+		{ID: "4", Title: "Emma", Authors: book.AuthorRefs("Jane Austen", "Anonymous")},
+		{ID: "5", Title: "Amber", Authors: []book.AuthorRef{book.NewAuthorRef("Roger Zelazny", "3619")}},
+	}
+
+	tables := map[string]book.Table{}
+	for _, table := range book.RelationalTables(books) {
+		tables[table.Name] = table
+	}
+
+	wantAuthors := [][]string{
+		{"1", "3619", "Roger Zelazny"},
+		{"2", "1265", "Jane Austen"},
+		{"3", "9999", "Jane Austen"},
+		{"4", "", "Anonymous"},
+	}
+
+	if got := tables["authors"].Rows; !reflect.DeepEqual(got, wantAuthors) {
+		t.Errorf("authors = %q, want %q", got, wantAuthors)
+	}
+
+	wantBookAuthors := [][]string{
+		{"1", "1", "1"},
+		{"2", "2", "1"},
+		{"3", "3", "1"},
+		{"4", "2", "1"},
+		{"4", "4", "2"},
+		{"5", "1", "1"},
+	}
+
+	if got := tables["book_authors"].Rows; !reflect.DeepEqual(got, wantBookAuthors) {
+		t.Errorf("book_authors = %q, want %q", got, wantBookAuthors)
+	}
+}
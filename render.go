@@ -0,0 +1,86 @@
+package book
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Renderer loads a page in a real browser and returns the document once
+// its scripts have run. Parts of a book page, such as the rating figures
+// and reviews, are hydrated client-side and missing from the plain HTML.
+// The headless package has a Chrome-based implementation.
+type Renderer interface {
+	Render(ctx context.Context, url string) ([]byte, error)
+}
+
+type RendererFunc func(ctx context.Context, url string) ([]byte, error)
+
+func (f RendererFunc) Render(ctx context.Context, url string) ([]byte, error) {
+	return f(ctx, url)
+}
+
+// WithRenderer makes FetchBook render a page with r when the plain HTML
+// parse comes back without what the caller needs, as judged by when. A
+// nil when uses NeedsRender. Rendering is slow, so only books that fail
+// the check pay for it.
+func WithRenderer(r Renderer, when func(*Book) bool) ClientOption {
+	return func(c *Client) {
+		if when == nil {
+			when = NeedsRender
+		}
+
+		c.renderer = r
+		c.renderWhen = when
+	}
+}
+
+// NeedsRender reports whether b is missing the rating figures that are
+// only present once the page's scripts have run.
+func NeedsRender(b *Book) bool {
+	return b.Title != "" && (b.Rating == 0 || b.Ratings == 0)
+}
+
+// Render fetches rawURL through the Client's renderer. It honours
+// robots.txt and the rate limits like Get does, but bypasses the cache,
+// since a rendered page differs from the plain one stored there.
+func (c *Client) Render(ctx context.Context, rawURL string) ([]byte, error) {
+	if c.renderer == nil {
+		return nil, fmt.Errorf("rendering %s: no renderer configured", rawURL)
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	target, err := c.resolve(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkRobots(ctx, target); err != nil {
+		return nil, err
+	}
+
+	if err := c.wait(ctx, target); err != nil {
+		return nil, err
+	}
+
+	body, err := c.renderer.Render(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, ok := DetectBlock(0, nil, body); ok {
+		block.URL = target
+		return nil, block
+	}
+
+	if c.recorder != nil {
+		if err := c.recorder.Record(target, time.Now(), body); err != nil {
+			return nil, fmt.Errorf("recording %s: %w", target, err)
+		}
+	}
+
+	return body, nil
+}
@@ -0,0 +1,58 @@
+package book_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestFetchBookKeepsBookWhenRenderFails(t *testing.T) {
+	plain := book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225"}
+
+	fake := testutil.NewFakeGoodreads(plain)
+	defer fake.Close()
+
+	renderErr := errors.New("browser crashed")
+	renderer := book.RendererFunc(func(ctx context.Context, url string) ([]byte, error) {
+		return nil, renderErr
+	})
+
+	c := book.NewClient(book.WithBaseURL(fake.URL), book.WithRenderer(renderer, nil))
+
+	got, err := c.FetchBook(context.Background(), fake.BookURL(plain))
+	if !errors.Is(err, renderErr) {
+		t.Errorf("error = %v, want the render error", err)
+	}
+
+	if got == nil || got.Title != plain.Title {
+		t.Fatalf("FetchBook = %+v, want the plain book", got)
+	}
+}
+
+func TestFetchBookUsesRenderedPage(t *testing.T) {
+	plain := book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225"}
+
+	fake := testutil.NewFakeGoodreads(plain)
+	defer fake.Close()
+
+	full := plain
+	full.Rating, full.Ratings, full.Reviews = 4.27, 1431283, 53209
+
+	renderer := book.RendererFunc(func(ctx context.Context, url string) ([]byte, error) {
+		return testutil.RenderFixture(full), nil
+	})
+
+	c := book.NewClient(book.WithBaseURL(fake.URL), book.WithRenderer(renderer, nil))
+
+	got, err := c.FetchBook(context.Background(), fake.BookURL(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Rating != full.Rating || got.Ratings != full.Ratings {
+		t.Errorf("rating %v from %d, want the rendered %v from %d", got.Rating, got.Ratings, full.Rating, full.Ratings)
+	}
+}
@@ -0,0 +1,94 @@
+package book
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how Client retries network errors, 429s and 5xx
+// responses. MaxAttempts counts the first request, so 1 disables retries.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+func WithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) ClientOption {
+	return WithRetryPolicy(RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: baseDelay, MaxDelay: maxDelay})
+}
+
+// maxRetryDelay caps the backoff of a policy with no MaxDelay, well short
+// of where doubling would overflow a Duration.
+const maxRetryDelay = time.Hour
+
+// Delay is the backoff before retry number attempt (starting at 1): the
+// base delay doubled per attempt, capped at MaxDelay (an hour if unset),
+// with the upper half randomised so that concurrent clients spread out.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	limit := p.MaxDelay
+	if limit <= 0 {
+		limit = maxRetryDelay
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+
+	if d > limit {
+		d = limit
+	}
+
+	half := d / 2
+
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// Allows reports whether a request that has failed attempt times may be
+// tried again.
+func (p RetryPolicy) Allows(attempt int) bool {
+	return attempt < p.MaxAttempts
+}
+
+// Wait sleeps before retry number attempt, honouring a server-provided
+// Retry-After when it asks for longer than the policy would wait. It
+// returns early with ctx's error if ctx is done first.
+func (p RetryPolicy) Wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	delay := p.Delay(attempt)
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+
+	return sleepUntil(ctx, time.Now().Add(delay))
+}
+
+// RetryableStatus reports whether a response status is worth retrying:
+// 429 Too Many Requests or any server error.
+func RetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// RetryAfter is the wait a response's Retry-After header asks for, given
+// in seconds or as an HTTP date, or zero if it has none.
+func RetryAfter(header http.Header) time.Duration {
+	d, _ := parseRetryAfter(header.Get("Retry-After"), time.Now())
+	return d
+}
@@ -0,0 +1,28 @@
+package book_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dchooyc/book"
+)
+
+func TestRetryDelayBounds(t *testing.T) {
+	tests := []struct {
+		policy book.RetryPolicy
+		max    time.Duration
+	}{
+		{book.RetryPolicy{BaseDelay: time.Second, MaxDelay: 30 * time.Second}, 30 * time.Second},
+		{book.RetryPolicy{BaseDelay: time.Second}, time.Hour},
+		{book.RetryPolicy{BaseDelay: 2 * time.Hour}, time.Hour},
+	}
+
+	for _, tt := range tests {
+		for _, attempt := range []int{1, 2, 10, 64, 100, 1000} {
+			d := tt.policy.Delay(attempt)
+			if d < 0 || d > tt.max {
+				t.Errorf("%+v: Delay(%d) = %v, want within [0, %v]", tt.policy, attempt, d, tt.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package book
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	ReviewURLIndicator      = "/review/show/"
+	ReviewTextIndicator     = "reviewText"
+	ReviewerIndicator       = "userReview"
+	ReviewLikesIndicator    = "likesCount"
+	ReviewCommentIndicator  = "comment"
+	ReviewCommentsIndicator = "comment_list"
+	ReviewShelfIndicator    = "shelf="
+)
+
+var commentTotalPattern = regexp.MustCompile(`(?i)showing\s+\d+\s*-\s*\d+\s+of\s+([\d,]+)`)
+
+type Review struct {
+	ID       string   `json:"id" yaml:"id" xml:"id"`
+	URL      string   `json:"url" yaml:"url" xml:"url"`
+	Book     BookRef  `json:"book" yaml:"book" xml:"book"`
+	Reviewer Reviewer `json:"reviewer" yaml:"reviewer" xml:"reviewer"`
+	Rating   int      `json:"rating" yaml:"rating" xml:"rating"`
+	Date     string   `json:"date" yaml:"date" xml:"date"`
+	Text     string   `json:"text" yaml:"text" xml:"text"`
+	Shelves  []string `json:"shelves" yaml:"shelves" xml:"shelves>shelf"`
+	Comments int      `json:"comments" yaml:"comments" xml:"comments"`
+	Likes    int      `json:"likes" yaml:"likes" xml:"likes"`
+}
+
+func GetReview(r io.Reader, opts ...ParseOption) (*Review, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &Review{
+		Shelves: []string{},
+	}
+
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		review.URL = getAttr(link, "href")
+		review.ID = leadingDigits(lastPathSegment(review.URL))
+	}
+
+	if a := findFirst(doc, byClass("a", BookRefTitleIndicator)); a != nil {
+		review.Book.Title = textContent(a)
+		review.Book.URL = getAttr(a, "href")
+		review.Book.ID = bookIDFromURL(review.Book.URL)
+	}
+
+	if a := findFirst(doc, byClass("a", ReviewerIndicator)); a != nil {
+		review.Reviewer.Name = textContent(a)
+		review.Reviewer.ProfileURL = getAttr(a, "href")
+		review.Reviewer.ID = leadingDigits(lastPathSegment(review.Reviewer.ProfileURL))
+	}
+
+	if meta := findFirst(doc, byAttr("", "itemprop", "ratingValue")); meta != nil {
+		review.Rating, _ = strconv.Atoi(getAttr(meta, "content"))
+	}
+
+	if review.Rating == 0 {
+		if stars := findFirst(doc, byClass("", ShelfStaticStarsIndicator)); stars != nil {
+			review.Rating = starTitles[getAttr(stars, "title")]
+		}
+	}
+
+	if date := findFirst(doc, byAttr("", "itemprop", "publishDate")); date != nil {
+		review.Date = textContent(date)
+	}
+
+	if text := findFirst(doc, byClass("div", ReviewTextIndicator)); text != nil {
+		review.Text = textContent(text)
+	}
+
+	for _, a := range findAll(doc, func(n *html.Node) bool { return isElement(n, "a") }) {
+		if strings.Contains(getAttr(a, "href"), ReviewShelfIndicator) {
+			review.Shelves = append(review.Shelves, textContent(a))
+		}
+	}
+
+	if likes := findFirst(doc, byClass("", ReviewLikesIndicator)); likes != nil {
+		review.Likes = parseCount(textContent(likes))
+	}
+
+	review.Comments = extractCommentCount(doc)
+
+	newParseConfig(opts).applyReviewer(&review.Reviewer)
+
+	return review, nil
+}
+
+func extractCommentCount(doc *html.Node) int {
+	for _, h2 := range findAll(doc, func(n *html.Node) bool { return isElement(n, "h2") }) {
+		if m := commentTotalPattern.FindStringSubmatch(textContent(h2)); m != nil {
+			return parseCount(m[1])
+		}
+	}
+
+	list := findFirst(doc, byAttr("", "id", ReviewCommentsIndicator))
+	if list == nil {
+		return 0
+	}
+
+	return len(findAll(list, byClass("div", ReviewCommentIndicator)))
+}
@@ -0,0 +1,28 @@
+package book
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const ScrubbedIDPrefix = "anon-"
+
+type Reviewer struct {
+	ID         string `json:"id" yaml:"id" xml:"id"`
+	Name       string `json:"name,omitempty" yaml:"name,omitempty" xml:"name,omitempty"`
+	ProfileURL string `json:"profile_url,omitempty" yaml:"profile_url,omitempty" xml:"profile_url,omitempty"`
+}
+
+func (r *Reviewer) Scrub(salt string) {
+	if r.ID == "" {
+		r.ID = leadingDigits(lastPathSegment(r.ProfileURL))
+	}
+
+	if r.ID != "" {
+		sum := sha256.Sum256([]byte(salt + r.ID))
+		r.ID = ScrubbedIDPrefix + hex.EncodeToString(sum[:8])
+	}
+
+	r.Name = ""
+	r.ProfileURL = ""
+}
@@ -0,0 +1,277 @@
+package book
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	robotsTTL = 24 * time.Hour
+
+	// robotsErrorTTL is how long a robots.txt 5xx keeps the host off
+	// limits before it is asked again, so an outage doesn't block the host
+	// for a whole robotsTTL.
+	robotsErrorTTL = 5 * time.Minute
+)
+
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// RobotsRules are the robots.txt rules that apply to one user agent.
+type RobotsRules struct {
+	rules      []robotsRule
+	CrawlDelay time.Duration
+}
+
+type robotsRule struct {
+	pattern string
+	match   *regexp.Regexp
+	allow   bool
+}
+
+// ParseRobots reads a robots.txt and keeps the group that best matches
+// userAgent, falling back to the "*" group.
+func ParseRobots(r io.Reader, userAgent string) (*RobotsRules, error) {
+	userAgent = strings.ToLower(userAgent)
+
+	type group struct {
+		agents []string
+		rules  RobotsRules
+	}
+
+	groups := []*group{}
+	var current *group
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		val = strings.TrimSpace(val)
+
+		if key == "user-agent" {
+			if !inAgents {
+				current = &group{}
+				groups = append(groups, current)
+				inAgents = true
+			}
+
+			current.agents = append(current.agents, strings.ToLower(val))
+			continue
+		}
+
+		inAgents = false
+
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "allow", "disallow":
+			if val != "" {
+				current.rules.rules = append(current.rules.rules, robotsRule{pattern: val, match: robotsPattern(val), allow: key == "allow"})
+			}
+		case "crawl-delay":
+			if secs, err := strconv.ParseFloat(val, 64); err == nil && secs > 0 {
+				current.rules.CrawlDelay = time.Duration(secs * float64(time.Second))
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var fallback *RobotsRules
+
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			if agent == "*" {
+				if fallback == nil {
+					fallback = &g.rules
+				}
+
+				continue
+			}
+
+			if strings.Contains(userAgent, agent) {
+				return &g.rules, nil
+			}
+		}
+	}
+
+	if fallback == nil {
+		fallback = &RobotsRules{}
+	}
+
+	return fallback, nil
+}
+
+// Allowed reports whether path (with any query) may be fetched. The
+// longest matching rule wins, and allow wins a tie.
+func (r *RobotsRules) Allowed(path string) bool {
+	best, allowed := -1, true
+
+	for _, rule := range r.rules {
+		if !rule.match.MatchString(path) {
+			continue
+		}
+
+		if n := len(rule.pattern); n > best || (n == best && rule.allow) {
+			best, allowed = n, rule.allow
+		}
+	}
+
+	return allowed
+}
+
+// robotsPattern compiles a robots.txt path pattern, where * matches any
+// run of characters and a trailing $ anchors the end.
+func robotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	return regexp.MustCompile(expr)
+}
+
+// WithRobots makes the client fetch each host's robots.txt, refuse
+// disallowed URLs with ErrDisallowed and space requests by the host's
+// Crawl-delay.
+func WithRobots(respect bool) ClientOption {
+	return func(c *Client) {
+		if respect {
+			c.robots = &robotsCache{hosts: map[string]*robotsEntry{}}
+		} else {
+			c.robots = nil
+		}
+	}
+}
+
+type robotsCache struct {
+	mu    sync.Mutex
+	hosts map[string]*robotsEntry
+}
+
+type robotsEntry struct {
+	rules   *RobotsRules
+	fetched time.Time
+	ttl     time.Duration
+	next    time.Time
+}
+
+// checkRobots returns ErrDisallowed for URLs the host's robots.txt rules
+// out, and otherwise waits out the host's Crawl-delay.
+func (c *Client) checkRobots(ctx context.Context, target string) error {
+	if c.robots == nil {
+		return nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+
+	entry, err := c.robotsEntry(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	if !entry.rules.Allowed(u.RequestURI()) {
+		return fmt.Errorf("fetching %s: %w", target, ErrDisallowed)
+	}
+
+	if entry.rules.CrawlDelay <= 0 {
+		return nil
+	}
+
+	c.robots.mu.Lock()
+	slot := entry.next
+	if now := time.Now(); slot.Before(now) {
+		slot = now
+	}
+	entry.next = slot.Add(entry.rules.CrawlDelay)
+	c.robots.mu.Unlock()
+
+	return sleepUntil(ctx, slot)
+}
+
+func (c *Client) robotsEntry(ctx context.Context, u *url.URL) (*robotsEntry, error) {
+	host := u.Scheme + "://" + u.Host
+
+	c.robots.mu.Lock()
+	entry, ok := c.robots.hosts[host]
+	c.robots.mu.Unlock()
+
+	if ok && time.Since(entry.fetched) < entry.ttl {
+		return entry, nil
+	}
+
+	rules, ttl, err := c.fetchRobots(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	c.robots.mu.Lock()
+	defer c.robots.mu.Unlock()
+
+	entry = &robotsEntry{rules: rules, fetched: time.Now(), ttl: ttl}
+	if prev, ok := c.robots.hosts[host]; ok {
+		entry.next = prev.next
+	}
+
+	c.robots.hosts[host] = entry
+
+	return entry, nil
+}
+
+// fetchRobots follows RFC 9309: a 4xx means no rules, while a 5xx means
+// the whole host is off limits until robots.txt can be read. It returns
+// how long the rules should be cached, which is short after a 5xx so the
+// file is asked for again soon.
+func (c *Client) fetchRobots(ctx context.Context, host string) (*RobotsRules, time.Duration, error) {
+	target := host + "/robots.txt"
+
+	if err := c.wait(ctx, target); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.fetch(ctx, target, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case resp.statusCode == http.StatusOK:
+		rules, err := ParseRobots(strings.NewReader(string(resp.body)), c.userAgent)
+		return rules, robotsTTL, err
+	case resp.statusCode >= http.StatusInternalServerError:
+		return &RobotsRules{rules: []robotsRule{{pattern: "/", match: robotsPattern("/")}}}, robotsErrorTTL, nil
+	}
+
+	return &RobotsRules{}, robotsTTL, nil
+}
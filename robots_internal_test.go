@@ -0,0 +1,45 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRobotsServerErrorIsCachedBriefly(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte("User-agent: *\nAllow: /\n"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithRobots(true), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	ctx := context.Background()
+	target := srv.URL + "/book/show/1"
+
+	if err := c.checkRobots(ctx, target); !errors.Is(err, ErrDisallowed) {
+		t.Fatalf("during the outage: err = %v, want ErrDisallowed", err)
+	}
+
+	down.Store(false)
+
+	// Age the cached 5xx past robotsErrorTTL but well within robotsTTL.
+	for _, entry := range c.robots.hosts {
+		entry.fetched = entry.fetched.Add(-robotsErrorTTL - time.Second)
+	}
+
+	if err := c.checkRobots(ctx, target); err != nil {
+		t.Errorf("after the outage: err = %v, want robots.txt fetched again", err)
+	}
+}
@@ -0,0 +1,167 @@
+package book_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+// randomBooks lets testing/quick generate whole libraries.
+type randomBooks []book.Book
+
+func (randomBooks) Generate(r *rand.Rand, size int) reflect.Value {
+	books := make(randomBooks, r.Intn(size+1))
+	for i := range books {
+		books[i] = testutil.RandomBook(r)
+	}
+
+	return reflect.ValueOf(books)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	property := func(books randomBooks) bool {
+		for _, want := range books {
+			data, err := json.Marshal(want)
+			if err != nil {
+				t.Log(err)
+				return false
+			}
+
+			var got book.Book
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Log(err)
+				return false
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Logf("got %+v\nwant %+v", got, want)
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	property := func(books randomBooks) bool {
+		var buf bytes.Buffer
+		if _, err := book.WriteJSONL(&buf, book.IterBooks(books)); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		got := []book.Book{}
+		err := book.ReadJSONL(&buf, func(b *book.Book) error {
+			got = append(got, *b)
+			return nil
+		})
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if len(got) != len(books) {
+			t.Logf("read %d books, wrote %d", len(got), len(books))
+			return false
+		}
+
+		for i := range got {
+			if !reflect.DeepEqual(got[i], books[i]) {
+				t.Logf("book %d: got %+v\nwant %+v", i, got[i], books[i])
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestCSVRoundTrip reads the books table back and checks every book
+// survived, quotes, commas, separators and newlines included. The
+// Goodreads export is lossy by design, so for it only the cells are
+// checked.
+func TestCSVRoundTrip(t *testing.T) {
+	property := func(books randomBooks) bool {
+		var buf bytes.Buffer
+		if err := book.BooksTable(books).WriteCSV(&buf); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		got, err := book.ReadBooksCSV(&buf)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if len(got) != len(books) {
+			t.Logf("read %d books, wrote %d", len(got), len(books))
+			return false
+		}
+
+		for i := range got {
+			if !reflect.DeepEqual(got[i], books[i]) {
+				t.Logf("book %d: got %+v\nwant %+v", i, got[i], books[i])
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGoodreadsCSVCells(t *testing.T) {
+	property := func(books randomBooks) bool {
+		table := book.GoodreadsTable(books)
+
+		var buf bytes.Buffer
+		if err := table.WriteCSV(&buf); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if !reflect.DeepEqual(records[0], table.Columns) || len(records)-1 != len(table.Rows) {
+			t.Log("header or row count changed")
+			return false
+		}
+
+		for i, row := range table.Rows {
+			if !reflect.DeepEqual(records[i+1], row) {
+				t.Logf("row %d: got %q, want %q", i, records[i+1], row)
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
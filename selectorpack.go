@@ -0,0 +1,160 @@
+package book
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const SelectorSchemaVersion = "1.1"
+
+var selectorPackDefaults = map[string]string{
+	"book_title_prefix": BookTitlePrefix,
+	"book_title_class":  BookTitleClass,
+	"book_title_testid": BookTitleTestID,
+	"book_url":          BookURLIndicator,
+	"book_id":           BookIDIndicator,
+	"book_cover":        BookCoverIndicator,
+	"book_cover_image":  BookCoverImageClass,
+	"book_authors":      BookAuthorsIndicator,
+	"book_genres":       BookGenresIndicator,
+	"book_rating":       BookRatingIndicator,
+	"book_stats":        BookStatsIndicator,
+}
+
+type SelectorPack struct {
+	SchemaVersion string            `json:"schema_version"`
+	Name          string            `json:"name,omitempty"`
+	Selectors     map[string]string `json:"selectors"`
+}
+
+type SchemaVersionError struct {
+	Got       string
+	Supported string
+	Reason    string
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("selector pack schema version %q is not supported (package supports %s): %s",
+		e.Got, e.Supported, e.Reason)
+}
+
+func DefaultSelectorPack() *SelectorPack {
+	selectors := make(map[string]string, len(selectorPackDefaults))
+	for key, val := range selectorPackDefaults {
+		selectors[key] = val
+	}
+
+	return &SelectorPack{
+		SchemaVersion: SelectorSchemaVersion,
+		Name:          "default",
+		Selectors:     selectors,
+	}
+}
+
+func LoadSelectorPack(r io.Reader) (*SelectorPack, error) {
+	pack := &SelectorPack{}
+
+	if err := json.NewDecoder(r).Decode(pack); err != nil {
+		return nil, fmt.Errorf("decoding selector pack: %w", err)
+	}
+
+	if err := pack.Validate(); err != nil {
+		return nil, err
+	}
+
+	return pack, nil
+}
+
+func (p *SelectorPack) Validate() error {
+	if err := CheckSchemaVersion(p.SchemaVersion); err != nil {
+		return err
+	}
+
+	errs := []error{}
+	keys := make([]string, 0, len(p.Selectors))
+	for key := range p.Selectors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, ok := selectorPackDefaults[key]; !ok {
+			errs = append(errs, fmt.Errorf("selector %q is unknown to schema %s; remove it or upgrade the package", key, SelectorSchemaVersion))
+			continue
+		}
+
+		if strings.TrimSpace(p.Selectors[key]) == "" {
+			errs = append(errs, fmt.Errorf("selector %q is empty; omit it to use the built-in default %q", key, selectorPackDefaults[key]))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *SelectorPack) Lookup(key string) string {
+	if val, ok := p.Selectors[key]; ok && val != "" {
+		return val
+	}
+
+	return selectorPackDefaults[key]
+}
+
+func CheckSchemaVersion(version string) error {
+	gotMajor, gotMinor, err := parseSchemaVersion(version)
+	if err != nil {
+		return &SchemaVersionError{
+			Got:       version,
+			Supported: SelectorSchemaVersion,
+			Reason:    `set "schema_version" to a "major.minor" value such as "` + SelectorSchemaVersion + `"`,
+		}
+	}
+
+	major, minor, _ := parseSchemaVersion(SelectorSchemaVersion)
+
+	switch {
+	case gotMajor < major:
+		return &SchemaVersionError{
+			Got:       version,
+			Supported: SelectorSchemaVersion,
+			Reason:    fmt.Sprintf("the pack predates schema %d.x; regenerate it from DefaultSelectorPack", major),
+		}
+	case gotMajor > major:
+		return &SchemaVersionError{
+			Got:       version,
+			Supported: SelectorSchemaVersion,
+			Reason:    fmt.Sprintf("the pack targets schema %d.x; upgrade github.com/dchooyc/book to a release supporting it", gotMajor),
+		}
+	case gotMinor > minor:
+		return &SchemaVersionError{
+			Got:       version,
+			Supported: SelectorSchemaVersion,
+			Reason:    fmt.Sprintf("the pack uses schema %d.%d features; upgrade github.com/dchooyc/book or use a pack built for %s", gotMajor, gotMinor, SelectorSchemaVersion),
+		}
+	}
+
+	return nil
+}
+
+func parseSchemaVersion(version string) (int, int, error) {
+	majorStr, minorStr, ok := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	if !ok {
+		return 0, 0, fmt.Errorf("malformed schema version %q", version)
+	}
+
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	minor, err := strconv.Atoi(minorStr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return major, minor, nil
+}
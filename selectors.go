@@ -0,0 +1,112 @@
+package book
+
+import "fmt"
+
+// Selectors are the markers the HTML parser finds each field of a modern
+// book page by. They default to the Book*Indicator constants; overriding
+// them lets a caller follow a Goodreads redesign from configuration
+// instead of waiting for a release.
+type Selectors struct {
+	TitlePrefix string `json:"title_prefix"`
+	TitleClass  string `json:"title_class"`
+	TitleTestID string `json:"title_testid"`
+	URL         string `json:"url"`
+	ID          string `json:"id"`
+	Cover       string `json:"cover"`
+	CoverImage  string `json:"cover_image"`
+	Authors     string `json:"authors"`
+	Genres      string `json:"genres"`
+	Rating      string `json:"rating"`
+	Stats       string `json:"stats"`
+}
+
+func DefaultSelectors() Selectors {
+	return Selectors{
+		TitlePrefix: BookTitlePrefix,
+		TitleClass:  BookTitleClass,
+		TitleTestID: BookTitleTestID,
+		URL:         BookURLIndicator,
+		ID:          BookIDIndicator,
+		Cover:       BookCoverIndicator,
+		CoverImage:  BookCoverImageClass,
+		Authors:     BookAuthorsIndicator,
+		Genres:      BookGenresIndicator,
+		Rating:      BookRatingIndicator,
+		Stats:       BookStatsIndicator,
+	}
+}
+
+// WithSelectors replaces the selectors GetBook looks for. Fields left
+// empty keep their defaults, so a hot-patch only needs the ones that
+// changed.
+func WithSelectors(s Selectors) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.selectors = s.withDefaults()
+	}
+}
+
+// WithSelectorPack is WithSelectors for a pack loaded from JSON with
+// LoadSelectorPack.
+func WithSelectorPack(p *SelectorPack) ParseOption {
+	return WithSelectors(p.ParseSelectors())
+}
+
+// ParseSelectors maps the pack's keys onto a Selectors, with the defaults
+// for any the pack leaves out.
+func (p *SelectorPack) ParseSelectors() Selectors {
+	return Selectors{
+		TitlePrefix: p.Lookup("book_title_prefix"),
+		TitleClass:  p.Lookup("book_title_class"),
+		TitleTestID: p.Lookup("book_title_testid"),
+		URL:         p.Lookup("book_url"),
+		ID:          p.Lookup("book_id"),
+		Cover:       p.Lookup("book_cover"),
+		CoverImage:  p.Lookup("book_cover_image"),
+		Authors:     p.Lookup("book_authors"),
+		Genres:      p.Lookup("book_genres"),
+		Rating:      p.Lookup("book_rating"),
+		Stats:       p.Lookup("book_stats"),
+	}
+}
+
+func (s Selectors) withDefaults() Selectors {
+	def := DefaultSelectors()
+
+	fill := func(val *string, fallback string) {
+		if *val == "" {
+			*val = fallback
+		}
+	}
+
+	fill(&s.TitlePrefix, def.TitlePrefix)
+	fill(&s.TitleClass, def.TitleClass)
+	fill(&s.TitleTestID, def.TitleTestID)
+	fill(&s.URL, def.URL)
+	fill(&s.ID, def.ID)
+	fill(&s.Cover, def.Cover)
+	fill(&s.CoverImage, def.CoverImage)
+	fill(&s.Authors, def.Authors)
+	fill(&s.Genres, def.Genres)
+	fill(&s.Rating, def.Rating)
+	fill(&s.Stats, def.Stats)
+
+	return s
+}
+
+// describe says where the HTML parser reads each field from, for
+// ParseReport.
+func (s Selectors) describe() map[string]string {
+	stats := fmt.Sprintf(`div.%s@aria-label`, s.Stats)
+
+	return map[string]string{
+		FieldTitle:   fmt.Sprintf(`h1[class=%q][data-testid=%s]@aria-label`, s.TitleClass, s.TitleTestID),
+		FieldURL:     `link[rel=canonical]@href`,
+		FieldID:      fmt.Sprintf(`a[href*=%q]@href`, s.ID),
+		FieldCover:   fmt.Sprintf(`div.%s > div > img.%s@src`, s.Cover, s.CoverImage),
+		FieldAuthors: fmt.Sprintf(`div.%s a[href*=%q], div.%s a[href*=%q]`, s.Authors, AuthorURLIndicator, s.Authors, AuthorSearchURLPrefix),
+		FieldGenres:  fmt.Sprintf(`a[href*=%q]@href`, s.Genres),
+		FieldRating:  `div.` + s.Rating,
+		FieldRatings: stats,
+		FieldReviews: stats,
+	}
+}
@@ -0,0 +1,159 @@
+package book
+
+import (
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	SeriesURLIndicator         = "/series/"
+	SeriesTitleIndicator       = "responsiveSeriesHeader__title"
+	SeriesDescriptionIndicator = "expandableHtml"
+	SeriesWorkIndicator        = "listWithDividers__item"
+	SeriesRatingIndicator      = "communityRating"
+	SeriesNumberPrefix         = "Book "
+)
+
+var seriesRatingPattern = regexp.MustCompile(`^([\d.]+)`)
+
+type Series struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Works       []SeriesWork `json:"works"`
+}
+
+type SeriesWork struct {
+	BookRef
+	Number   string  `json:"number"`
+	Position float64 `json:"position"`
+}
+
+func GetSeries(r io.Reader) (*Series, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &Series{
+		Works: []SeriesWork{},
+	}
+
+	if header := findFirst(doc, byClass("div", SeriesTitleIndicator)); header != nil {
+		series.Title = textContent(header)
+	}
+
+	if desc := findFirst(doc, byClass("div", SeriesDescriptionIndicator)); desc != nil {
+		series.Description = textContent(desc)
+	}
+
+	for _, item := range findAll(doc, byClass("div", SeriesWorkIndicator)) {
+		work, ok := extractSeriesWork(item)
+		if ok {
+			series.Works = append(series.Works, work)
+		}
+	}
+
+	return series, nil
+}
+
+func extractSeriesWork(item *html.Node) (SeriesWork, bool) {
+	work := SeriesWork{Position: -1}
+
+	titleLink := findFirst(item, byAttr("a", "itemprop", "url"))
+	if titleLink == nil {
+		return work, false
+	}
+
+	work.Title = textContent(titleLink)
+	work.URL = getAttr(titleLink, "href")
+	work.ID = bookIDFromURL(work.URL)
+
+	if h3 := findFirst(item, func(n *html.Node) bool { return isElement(n, "h3") }); h3 != nil {
+		work.Number = strings.TrimPrefix(textContent(h3), SeriesNumberPrefix)
+
+		if pos, err := strconv.ParseFloat(work.Number, 64); err == nil {
+			work.Position = pos
+		}
+	}
+
+	if cover := findFirst(item, func(n *html.Node) bool { return isElement(n, "img") }); cover != nil {
+		work.CoverUrl = getAttr(cover, "src")
+	}
+
+	for _, author := range findAll(item, byAttr("span", "itemprop", "author")) {
+		if name := findFirst(author, byAttr("span", "itemprop", "name")); name != nil {
+			work.Authors = append(work.Authors, textContent(name))
+		}
+	}
+
+	if rating := findFirst(item, byClass("div", SeriesRatingIndicator)); rating != nil {
+		text := textContent(rating)
+		work.Rating = matchFloat(seriesRatingPattern, text)
+		work.Ratings = matchCount(ratingsPattern, text)
+
+		if m := publishedPattern.FindStringSubmatch(text); m != nil {
+			work.Year, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	return work, true
+}
+
+func (w SeriesWork) IsNumbered() bool {
+	return w.Position >= 0
+}
+
+func (w SeriesWork) IsNovella() bool {
+	return w.IsNumbered() && w.Position != math.Trunc(w.Position)
+}
+
+func (s *Series) ReadingOrder(includeNovellas bool) []SeriesWork {
+	works := s.orderable(includeNovellas)
+
+	sort.SliceStable(works, func(i, j int) bool {
+		return works[i].Position < works[j].Position
+	})
+
+	return works
+}
+
+func (s *Series) PublicationOrder(includeNovellas bool) []SeriesWork {
+	works := s.orderable(includeNovellas)
+
+	sort.SliceStable(works, func(i, j int) bool {
+		a, b := works[i], works[j]
+
+		switch {
+		case a.Year == b.Year:
+			return a.Position < b.Position
+		case a.Year == 0:
+			return false
+		case b.Year == 0:
+			return true
+		}
+
+		return a.Year < b.Year
+	})
+
+	return works
+}
+
+func (s *Series) orderable(includeNovellas bool) []SeriesWork {
+	works := []SeriesWork{}
+
+	for _, w := range s.Works {
+		if !w.IsNumbered() || (w.IsNovella() && !includeNovellas) {
+			continue
+		}
+
+		works = append(works, w)
+	}
+
+	return works
+}
@@ -0,0 +1,108 @@
+package book_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+const seriesPage = `<!DOCTYPE html>
+<html><body>
+<div class="responsiveSeriesHeader__title"><h1>The Witcher Series</h1></div>
+<div class="expandableHtml">The Witcher saga by Andrzej Sapkowski.</div>
+<div class="listWithDividers">
+  <div class="listWithDividers__item">
+    <h3>Book 1</h3>
+    <img src="https://images.gr-assets.com/books/1.jpg">
+    <a itemprop="url" href="https://www.goodreads.com/book/show/40603587-the-last-wish">The Last Wish</a>
+    <span itemprop="author"><a href="/author/show/38569"><span itemprop="name">Andrzej Sapkowski</span></a></span>
+    <div class="communityRating">4.17 · 195,203 Ratings · published 1993</div>
+  </div>
+  <div class="listWithDividers__item">
+    <h3>Book 2</h3>
+    <a itemprop="url" href="https://www.goodreads.com/book/show/15831.Sword_of_Destiny">Sword of Destiny</a>
+    <div class="communityRating">4.26 · 120,000 Ratings · published 1992</div>
+  </div>
+  <div class="listWithDividers__item">
+    <h3>Book 0.5</h3>
+    <a itemprop="url" href="https://www.goodreads.com/book/show/12345-the-road">The Road with No Return</a>
+    <div class="communityRating">3.80 · 2,100 Ratings · published 1988</div>
+  </div>
+  <div class="listWithDividers__item">
+    <h3>Book 1-8</h3>
+    <a itemprop="url" href="https://www.goodreads.com/book/show/99-box-set">The Witcher Boxed Set</a>
+  </div>
+  <div class="listWithDividers__item">
+    <h3>Book 3</h3>
+    <span>No link to this book</span>
+  </div>
+</div>
+</body></html>`
+
+func TestGetSeries(t *testing.T) {
+	series, err := book.GetSeries(strings.NewReader(seriesPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if series.Title != "The Witcher Series" || series.Description != "The Witcher saga by Andrzej Sapkowski." {
+		t.Errorf("title %q, description %q", series.Title, series.Description)
+	}
+
+	if len(series.Works) != 4 {
+		t.Fatalf("got %d works, want 4 with title links: %+v", len(series.Works), series.Works)
+	}
+
+	first := series.Works[0]
+	if first.Title != "The Last Wish" || first.ID != "40603587" || first.Number != "1" || first.Position != 1 {
+		t.Errorf("first work = %+v", first)
+	}
+
+	if first.CoverUrl != "https://images.gr-assets.com/books/1.jpg" || len(first.Authors) != 1 || first.Authors[0] != "Andrzej Sapkowski" {
+		t.Errorf("first work cover %q, authors %q", first.CoverUrl, first.Authors)
+	}
+
+	if first.Rating != 4.17 || first.Ratings != 195203 || first.Year != 1993 {
+		t.Errorf("first work rating %v from %d, year %d", first.Rating, first.Ratings, first.Year)
+	}
+
+	novella := series.Works[2]
+	if novella.Number != "0.5" || novella.Position != 0.5 || !novella.IsNovella() {
+		t.Errorf("novella = %+v, want position 0.5", novella)
+	}
+
+	boxSet := series.Works[3]
+	if boxSet.Number != "1-8" || boxSet.IsNumbered() {
+		t.Errorf("box set = %+v, want it unnumbered", boxSet)
+	}
+}
+
+func TestSeriesOrder(t *testing.T) {
+	series, err := book.GetSeries(strings.NewReader(seriesPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		works []book.SeriesWork
+		want  []string
+	}{
+		{"reading", series.ReadingOrder(false), []string{"The Last Wish", "Sword of Destiny"}},
+		{"reading with novellas", series.ReadingOrder(true), []string{"The Road with No Return", "The Last Wish", "Sword of Destiny"}},
+		{"publication", series.PublicationOrder(false), []string{"Sword of Destiny", "The Last Wish"}},
+		{"publication with novellas", series.PublicationOrder(true), []string{"The Road with No Return", "Sword of Destiny", "The Last Wish"}},
+	}
+
+	for _, tt := range tests {
+		got := make([]string, len(tt.works))
+		for i, w := range tt.works {
+			got[i] = w.Title
+		}
+
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s order = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dchooyc/book"
+)
+
+const (
+	mediaJSON   = "application/json"
+	mediaJSONLD = "application/ld+json"
+	mediaCSV    = "text/csv"
+)
+
+var supportedMedia = []string{mediaJSON, mediaJSONLD, mediaCSV}
+
+// negotiate picks the response format from the Accept header: the
+// supported type with the highest q-value, preferring earlier entries in
+// supportedMedia on ties. An empty header means JSON; false means nothing
+// acceptable is supported.
+func negotiate(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return mediaJSON, true
+	}
+
+	best, bestQ := "", 0.0
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if val, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(val, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		for _, supported := range supportedMedia {
+			if q > bestQ && mediaMatches(mediaType, supported) {
+				best, bestQ = supported, q
+			}
+		}
+	}
+
+	return best, best != ""
+}
+
+func mediaMatches(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(pattern, "/*")
+
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+func writeBatch(w http.ResponseWriter, media string, resp BatchResponse) {
+	w.Header().Set("Vary", "Accept")
+
+	switch media {
+	case mediaCSV:
+		w.Header().Set("Content-Type", mediaCSV+"; charset=utf-8")
+		batchTable(resp).WriteCSV(w)
+	case mediaJSONLD:
+		w.Header().Set("Content-Type", mediaJSONLD)
+		json.NewEncoder(w).Encode(batchJSONLD(resp))
+	default:
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// batchTable is the books table with each row prefixed by the item's
+// input, status and error.
+func batchTable(resp BatchResponse) book.Table {
+	books := book.BooksTable(nil)
+
+	table := book.Table{
+		Name:    "batch",
+		Columns: append([]string{"input", "status", "error"}, books.Columns...),
+	}
+
+	for _, result := range resp.Results {
+		row := []string{result.Input, strconv.Itoa(result.Status), result.Error}
+
+		if result.Book != nil {
+			row = append(row, book.BooksTable([]book.Book{*result.Book}).Rows[0]...)
+		} else {
+			row = append(row, make([]string, len(books.Columns))...)
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
+
+// batchJSONLD returns the found books as a schema.org ItemList; failed
+// items have no JSON-LD representation and are left out.
+func batchJSONLD(resp BatchResponse) map[string]interface{} {
+	items := []map[string]interface{}{}
+
+	for _, result := range resp.Results {
+		if result.Book == nil {
+			continue
+		}
+
+		doc := result.Book.JSONLD()
+		delete(doc, "@context")
+
+		items = append(items, map[string]interface{}{
+			"@type":    "ListItem",
+			"position": len(items) + 1,
+			"item":     doc,
+		})
+	}
+
+	return map[string]interface{}{
+		"@context":        book.SchemaOrgContext,
+		"@type":           "ItemList",
+		"itemListElement": items,
+	}
+}
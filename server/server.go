@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/dchooyc/book"
+)
+
+const (
+	BatchPath       = "/books:batch"
+	DefaultMaxBatch = 50
+	maxRequestBody  = 1 << 20
+)
+
+type Server struct {
+	client   *book.Client
+	maxBatch int
+	mux      *http.ServeMux
+
+	mu       sync.Mutex
+	inflight map[string]*call
+}
+
+type Option func(*Server)
+
+func WithMaxBatch(n int) Option {
+	return func(s *Server) {
+		s.maxBatch = n
+	}
+}
+
+func New(client *book.Client, opts ...Option) *Server {
+	if client == nil {
+		client = book.DefaultClient
+	}
+
+	s := &Server{
+		client:   client,
+		maxBatch: DefaultMaxBatch,
+		mux:      http.NewServeMux(),
+		inflight: map[string]*call{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc(BatchPath, s.handleBatch)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type BatchRequest struct {
+	Items []string `json:"items"`
+}
+
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
+
+type BatchResult struct {
+	Input  string     `json:"input"`
+	Status int        `json:"status"`
+	Error  string     `json:"error,omitempty"`
+	Book   *book.Book `json:"book,omitempty"`
+}
+
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	media, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, http.StatusNotAcceptable, "supported types: "+strings.Join(supportedMedia, ", "))
+		return
+	}
+
+	// ?filter= takes a book.ParseFilter expression. Books that don't match
+	// are left out of the results; failed lookups are kept so their errors
+	// still reach the caller.
+	filter, err := book.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req BatchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if len(req.Items) == 0 {
+		writeError(w, http.StatusBadRequest, "items must not be empty")
+		return
+	}
+
+	if s.maxBatch > 0 && len(req.Items) > s.maxBatch {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("at most %d items per batch", s.maxBatch))
+		return
+	}
+
+	resp := BatchResponse{Results: make([]BatchResult, len(req.Items))}
+
+	var wg sync.WaitGroup
+	for i, item := range req.Items {
+		wg.Add(1)
+
+		go func(i int, item string) {
+			defer wg.Done()
+			resp.Results[i] = s.lookup(r.Context(), item)
+		}(i, item)
+	}
+
+	wg.Wait()
+
+	matched := resp.Results[:0]
+	for _, result := range resp.Results {
+		if result.Book == nil || filter.Match(*result.Book) {
+			matched = append(matched, result)
+		}
+	}
+
+	resp.Results = matched
+
+	writeBatch(w, media, resp)
+}
+
+func (s *Server) lookup(ctx context.Context, item string) BatchResult {
+	result := BatchResult{Input: item}
+
+	target, ok := s.targetURL(item)
+	if !ok {
+		result.Status = http.StatusBadRequest
+		result.Error = "not a book URL on the configured host, or an ISBN"
+		return result
+	}
+
+	// A book that only partly parsed is still returned, with the fields
+	// that failed listed in Error.
+	b, err := s.fetch(ctx, target)
+	if b == nil {
+		result.Status = errorStatus(err)
+		result.Error = err.Error()
+		return result
+	}
+
+	if b.Title == "" {
+		result.Status = http.StatusNotFound
+		result.Error = "no book found"
+		return result
+	}
+
+	result.Status = http.StatusOK
+	result.Book = b
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+type call struct {
+	done chan struct{}
+	book *book.Book
+	err  error
+}
+
+// fetch coalesces concurrent lookups of the same URL, across batches, into a
+// single upstream request.
+func (s *Server) fetch(ctx context.Context, target string) (*book.Book, error) {
+	s.mu.Lock()
+	if c, ok := s.inflight[target]; ok {
+		s.mu.Unlock()
+
+		select {
+		case <-c.done:
+			return c.book, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c := &call{done: make(chan struct{})}
+	s.inflight[target] = c
+	s.mu.Unlock()
+
+	c.book, c.err = s.client.FetchBook(context.WithoutCancel(ctx), target)
+	close(c.done)
+
+	s.mu.Lock()
+	delete(s.inflight, target)
+	s.mu.Unlock()
+
+	return c.book, c.err
+}
+
+// targetURL turns an item into the page to fetch. Book URLs must be
+// relative or on the client's own host: the client resolves absolute URLs
+// as given, so accepting any host would let callers make the server fetch
+// internal addresses.
+func (s *Server) targetURL(item string) (string, bool) {
+	item = strings.TrimSpace(item)
+
+	if strings.Contains(item, book.BookURLIndicator) {
+		return item, s.onBaseHost(item)
+	}
+
+	isbn := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, item)
+
+	if isISBN(isbn) {
+		return book.SearchURLPrefix + url.QueryEscape(isbn), true
+	}
+
+	return "", false
+}
+
+func (s *Server) onBaseHost(item string) bool {
+	u, err := url.Parse(item)
+	if err != nil || !strings.HasPrefix(u.Path, book.BookURLIndicator) {
+		return false
+	}
+
+	if u.Scheme == "" && u.Host == "" && u.User == nil {
+		return true
+	}
+
+	base, err := url.Parse(s.client.BaseURL())
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.User == nil && strings.EqualFold(u.Host, base.Host)
+}
+
+func isISBN(s string) bool {
+	if len(s) != 10 && len(s) != 13 {
+		return false
+	}
+
+	for i, r := range s {
+		if r >= '0' && r <= '9' {
+			continue
+		}
+
+		if len(s) == 10 && i == 9 && (r == 'X' || r == 'x') {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func errorStatus(err error) int {
+	var statusErr *book.StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode == http.StatusNotFound {
+			return http.StatusNotFound
+		}
+
+		return http.StatusBadGateway
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+
+	return http.StatusBadGateway
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
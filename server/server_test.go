@@ -0,0 +1,94 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/server"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestBatchFilter(t *testing.T) {
+	dune := book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225", Rating: 4.27}
+	emma := book.Book{Title: "Emma", ID: "6969", URL: "https://www.goodreads.com/book/show/6969", Rating: 4.02}
+
+	fake := testutil.NewFakeGoodreads(dune, emma)
+	defer fake.Close()
+
+	srv := server.New(book.NewClient(book.WithBaseURL(fake.URL)))
+
+	body := `{"items": ["` + fake.BookURL(dune) + `", "` + fake.BookURL(emma) + `", "https://elsewhere.example/book/show/1"]}`
+	req := httptest.NewRequest(http.MethodPost, server.BatchPath+"?filter=rating%3E4.2", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	srv.ServeHTTP(rec, req)
+
+	var resp server.BatchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(resp.Results) != 2 {
+		t.Fatalf("got %d results, want Dune and the rejected URL: %+v", len(resp.Results), resp.Results)
+	}
+
+	if resp.Results[0].Book == nil || resp.Results[0].Book.Title != "Dune" {
+		t.Errorf("first result = %+v, want Dune", resp.Results[0])
+	}
+
+	if resp.Results[1].Status != http.StatusBadRequest {
+		t.Errorf("second result status = %d, want %d", resp.Results[1].Status, http.StatusBadRequest)
+	}
+}
+
+func TestBatchFilterSyntaxError(t *testing.T) {
+	srv := server.New(book.NewClient())
+
+	req := httptest.NewRequest(http.MethodPost, server.BatchPath+"?filter=rating%3E", strings.NewReader(`{"items": ["x"]}`))
+	rec := httptest.NewRecorder()
+
+	srv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestBatchPartialParse(t *testing.T) {
+	dune := book.Book{Title: "Dune", ID: "234225", URL: "https://www.goodreads.com/book/show/234225", Rating: 4.27}
+
+	page := strings.Replace(string(testutil.RenderFixture(dune)), ">4.27<", ">n/a<", 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	}))
+	defer upstream.Close()
+
+	srv := server.New(book.NewClient(book.WithBaseURL(upstream.URL)))
+
+	req := httptest.NewRequest(http.MethodPost, server.BatchPath, strings.NewReader(`{"items": ["/book/show/234225"]}`))
+	rec := httptest.NewRecorder()
+
+	srv.ServeHTTP(rec, req)
+
+	var resp server.BatchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if len(resp.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(resp.Results))
+	}
+
+	result := resp.Results[0]
+	if result.Status != http.StatusOK || result.Book == nil || result.Book.Title != "Dune" {
+		t.Errorf("result = %+v, want status 200 with the partly parsed book", result)
+	}
+
+	if !strings.Contains(result.Error, book.FieldRating) {
+		t.Errorf("error = %q, want it to name the rating field", result.Error)
+	}
+}
@@ -0,0 +1,124 @@
+package book
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithCookieJar keeps cookies across requests, which is what a logged-in
+// session needs.
+func WithCookieJar(jar http.CookieJar) ClientOption {
+	return func(c *Client) {
+		c.jar = jar
+	}
+}
+
+// WithSessionCookies seeds the client's cookie jar, creating one if
+// needed, with cookies from a browser session so pages that need a
+// logged-in user (recommendations, full review lists) can be fetched.
+// Cookies without a domain are scoped to the client's base URL.
+func WithSessionCookies(cookies ...*http.Cookie) ClientOption {
+	return func(c *Client) {
+		c.sessionCookies = append(c.sessionCookies, cookies...)
+	}
+}
+
+func (c *Client) sessionPolicy() {
+	if c.jar == nil && len(c.sessionCookies) == 0 {
+		return
+	}
+
+	jar := c.jar
+	if jar == nil {
+		jar = c.httpClient.Jar
+	}
+
+	if jar == nil {
+		jar, _ = cookiejar.New(nil)
+	}
+
+	base, err := url.Parse(c.baseURL)
+	if err == nil {
+		for _, cookie := range c.sessionCookies {
+			u := base
+			if domain := strings.TrimPrefix(cookie.Domain, "."); domain != "" {
+				u = &url.URL{Scheme: base.Scheme, Host: domain, Path: "/"}
+			}
+
+			jar.SetCookies(u, []*http.Cookie{cookie})
+		}
+	}
+
+	hc := *c.httpClient
+	hc.Jar = jar
+	c.httpClient = &hc
+}
+
+// ParseCookieHeader splits a Cookie header value as copied from browser
+// dev tools ("a=1; b=2") into cookies.
+func ParseCookieHeader(header string) []*http.Cookie {
+	req := http.Request{Header: http.Header{"Cookie": {header}}}
+	return req.Cookies()
+}
+
+// LoadCookiesFile reads cookies in the Netscape cookies.txt format that
+// browser extensions and curl export. Expired cookies are dropped.
+func LoadCookiesFile(r io.Reader) ([]*http.Cookie, error) {
+	cookies := []*http.Cookie{}
+	now := time.Now()
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(text, "#HttpOnly_"); ok {
+			text, httpOnly = rest, true
+		}
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("cookies file line %d: expected 7 tab-separated fields, got %d", line, len(fields))
+		}
+
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cookies file line %d: bad expiry %q", line, fields[4])
+		}
+
+		cookie := &http.Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+
+		if expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+			if cookie.Expires.Before(now) {
+				continue
+			}
+		}
+
+		cookies = append(cookies, cookie)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cookies, nil
+}
@@ -0,0 +1,145 @@
+package book
+
+import (
+	"io"
+	"strconv"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	ShelfURLIndicator         = "/review/list/"
+	ShelfRowIndicator         = "bookalike"
+	ShelfFieldIndicator       = "field"
+	ShelfValueIndicator       = "value"
+	ShelfLinkIndicator        = "shelfLink"
+	ShelfDateReadIndicator    = "date_read_value"
+	ShelfStaticStarsIndicator = "staticStars"
+)
+
+var starTitles = map[string]int{
+	"did not like it": 1,
+	"it was ok":       2,
+	"liked it":        3,
+	"really liked it": 4,
+	"it was amazing":  5,
+}
+
+type Shelf struct {
+	Rows     []ShelfRow `json:"rows"`
+	NextPage string     `json:"next_page,omitempty"`
+}
+
+type ShelfRow struct {
+	Title      string   `json:"title"`
+	URL        string   `json:"url"`
+	ID         string   `json:"id"`
+	Author     string   `json:"author"`
+	UserRating int      `json:"user_rating"`
+	AvgRating  float64  `json:"avg_rating"`
+	DateRead   string   `json:"date_read"`
+	DateAdded  string   `json:"date_added"`
+	Shelves    []string `json:"shelves"`
+}
+
+func GetShelf(r io.Reader) (*Shelf, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	shelf := &Shelf{
+		Rows:     []ShelfRow{},
+		NextPage: extractNextPage(doc),
+	}
+
+	for _, tr := range findAll(doc, byClass("tr", ShelfRowIndicator)) {
+		shelf.Rows = append(shelf.Rows, extractShelfRow(tr))
+	}
+
+	return shelf, nil
+}
+
+func extractShelfRow(tr *html.Node) ShelfRow {
+	row := ShelfRow{Shelves: []string{}}
+
+	if value := shelfField(tr, "title"); value != nil {
+		if a := findFirst(value, func(n *html.Node) bool { return isElement(n, "a") }); a != nil {
+			row.URL = getAttr(a, "href")
+			row.ID = bookIDFromURL(row.URL)
+			row.Title = getAttr(a, "title")
+
+			if row.Title == "" {
+				row.Title = textContent(a)
+			}
+		}
+	}
+
+	if value := shelfField(tr, "author"); value != nil {
+		if a := findFirst(value, func(n *html.Node) bool { return isElement(n, "a") }); a != nil {
+			row.Author = textContent(a)
+		}
+	}
+
+	if value := shelfField(tr, "avg_rating"); value != nil {
+		row.AvgRating, _ = strconv.ParseFloat(textContent(value), 64)
+	}
+
+	if value := shelfField(tr, "rating"); value != nil {
+		row.UserRating = extractUserRating(value)
+	}
+
+	if value := shelfField(tr, "shelves"); value != nil {
+		for _, a := range findAll(value, byClass("a", ShelfLinkIndicator)) {
+			row.Shelves = append(row.Shelves, textContent(a))
+		}
+	}
+
+	if value := shelfField(tr, "date_read"); value != nil {
+		if span := findFirst(value, byClass("span", ShelfDateReadIndicator)); span != nil {
+			row.DateRead = textContent(span)
+		}
+	}
+
+	if value := shelfField(tr, "date_added"); value != nil {
+		if span := findFirst(value, func(n *html.Node) bool { return isElement(n, "span") }); span != nil {
+			row.DateAdded = getAttr(span, "title")
+
+			if row.DateAdded == "" {
+				row.DateAdded = textContent(span)
+			}
+		}
+	}
+
+	return row
+}
+
+func shelfField(tr *html.Node, name string) *html.Node {
+	td := findFirst(tr, func(n *html.Node) bool {
+		return isElement(n, "td") && hasClass(n, ShelfFieldIndicator) && hasClass(n, name)
+	})
+	if td == nil {
+		return nil
+	}
+
+	return findFirst(td, byClass("div", ShelfValueIndicator))
+}
+
+func extractUserRating(value *html.Node) int {
+	stars := findFirst(value, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && getAttr(n, "data-rating") != ""
+	})
+	if stars != nil {
+		rating, err := strconv.Atoi(getAttr(stars, "data-rating"))
+		if err == nil {
+			return rating
+		}
+	}
+
+	static := findFirst(value, byClass("", ShelfStaticStarsIndicator))
+	if static != nil {
+		return starTitles[getAttr(static, "title")]
+	}
+
+	return 0
+}
@@ -0,0 +1,403 @@
+// Package site renders a Books collection as a static website: an index
+// that filters by genre and author, a page per genre and author, and a
+// detail page per book. The output uses relative links only, so it works
+// from a GitHub Pages project path or straight off the disk.
+package site
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dchooyc/book"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed templates/style.css
+var stylesheet []byte
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"rating": formatRating,
+	"asset":  asset,
+	"slugs":  slugs,
+	"card":   func(root string, e *Entry) card { return card{Root: root, Entry: e} },
+}).ParseFS(templateFS, "templates/*.html"))
+
+const (
+	booksDir   = "books"
+	genresDir  = "genres"
+	authorsDir = "authors"
+	coversDir  = "covers"
+)
+
+// Link is a page of the site, addressed relative to its root.
+type Link struct {
+	Name  string
+	Path  string
+	Count int
+}
+
+// Entry is a book as the templates see it.
+type Entry struct {
+	book.Book
+	Path    string
+	Cover   string
+	Authors []Link
+	Genres  []Link
+	Year    string
+}
+
+// card is an entry along with the root of the page listing it, for the
+// shared "card" template.
+type card struct {
+	Root string
+	*Entry
+}
+
+type page struct {
+	Site    string
+	Title   string
+	Root    string
+	Entries []*Entry
+	Entry   *Entry
+	Genres  []Link
+	Authors []Link
+}
+
+type Option func(*config)
+
+type config struct {
+	title  string
+	client *book.Client
+}
+
+// WithTitle sets the site name shown on every page. It defaults to
+// "Library".
+func WithTitle(title string) Option {
+	return func(c *config) {
+		c.title = title
+	}
+}
+
+// WithCovers downloads covers through client into the site, instead of
+// linking to Goodreads' copies. Covers that fail to download fall back to
+// the remote link.
+func WithCovers(client *book.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// Generate writes the site for books into dir, creating it if needed.
+// Pages from an earlier run are overwritten but not removed. Failed
+// cover downloads are returned joined together once every page has been
+// written.
+func Generate(ctx context.Context, dir string, books []book.Book, opts ...Option) error {
+	cfg := config{title: "Library"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	for _, sub := range []string{booksDir, genresDir, authorsDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+
+	entries, genres, authors := index(books)
+
+	errs := []error{}
+	if cfg.client != nil {
+		if err := downloadCovers(ctx, cfg.client, dir, entries, &errs); err != nil {
+			return err
+		}
+	}
+
+	w := writer{dir: dir, site: cfg.title}
+
+	w.write("index.html", "index.html", page{Title: cfg.title, Entries: entries, Genres: genres, Authors: authors})
+
+	for _, e := range entries {
+		w.write(e.Path, "book.html", page{Title: e.Title, Entry: e})
+	}
+
+	for _, g := range genres {
+		w.write(g.Path, "list.html", page{Title: g.Name, Entries: filter(entries, g.Path, func(e *Entry) []Link { return e.Genres })})
+	}
+
+	for _, a := range authors {
+		w.write(a.Path, "list.html", page{Title: a.Name, Entries: filter(entries, a.Path, func(e *Entry) []Link { return e.Authors })})
+	}
+
+	w.file("style.css", stylesheet)
+
+	// Without it GitHub Pages runs the site through Jekyll, which is slow
+	// and drops files starting with an underscore.
+	w.file(".nojekyll", nil)
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return errors.Join(errs...)
+}
+
+// index builds an entry per book and the genre and author pages linking
+// to them, each sorted by name.
+func index(books []book.Book) ([]*Entry, []Link, []Link) {
+	entries := make([]*Entry, 0, len(books))
+	genres, authors := map[string]*Link{}, map[string]*Link{}
+	used := map[string]int{}
+
+	for _, b := range books {
+		e := &Entry{Book: b, Cover: b.CoverUrl, Year: year(b)}
+		e.Path = unique(path.Join(booksDir, pageName(b)), used) + ".html"
+
+		for _, a := range b.Authors {
+			l := collect(authors, a.Name(), path.Join(authorsDir, authorName(a))+".html")
+			e.Authors = append(e.Authors, *l)
+		}
+
+		for _, g := range b.Genres {
+			l := collect(genres, g.Name(), path.Join(genresDir, slug(string(g)))+".html")
+			e.Genres = append(e.Genres, *l)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, sorted(genres), sorted(authors)
+}
+
+func collect(links map[string]*Link, name, p string) *Link {
+	l, ok := links[p]
+	if !ok {
+		l = &Link{Name: name, Path: p}
+		links[p] = l
+	}
+
+	l.Count++
+
+	return l
+}
+
+func sorted(links map[string]*Link) []Link {
+	out := make([]Link, 0, len(links))
+	for _, l := range links {
+		out = append(out, *l)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name) })
+
+	return out
+}
+
+func filter(entries []*Entry, p string, links func(*Entry) []Link) []*Entry {
+	out := []*Entry{}
+
+	for _, e := range entries {
+		for _, l := range links(e) {
+			if l.Path == p {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+
+	return out
+}
+
+func pageName(b book.Book) string {
+	if b.ID != "" {
+		return b.ID
+	}
+
+	if s := slug(b.Title); s != "" {
+		return s
+	}
+
+	return "book"
+}
+
+// authorName falls back to the author's ID, then a hash of the name, for
+// names in scripts Transliterate can't carry over to ASCII.
+func authorName(a book.AuthorRef) string {
+	if s := slug(a.Name()); s != "" {
+		return s
+	}
+
+	if a.ID() != "" {
+		return a.ID()
+	}
+
+	sum := sha1.Sum([]byte(a.Name()))
+
+	return hex.EncodeToString(sum[:6])
+}
+
+// unique appends "-2", "-3" and so on to names already handed out, so two
+// untitled or ID-less books don't overwrite each other's page.
+func unique(name string, used map[string]int) string {
+	used[name]++
+	if n := used[name]; n > 1 {
+		return name + "-" + strconv.Itoa(n)
+	}
+
+	return name
+}
+
+// slug makes a lowercase ASCII file name from s, e.g. "Ursula K. Le Guin"
+// gives "ursula-k-le-guin".
+func slug(s string) string {
+	var sb strings.Builder
+
+	dash := false
+	for _, r := range strings.ToLower(book.Transliterate(s)) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+
+			sb.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return sb.String()
+}
+
+func year(b book.Book) string {
+	if b.Details == nil {
+		return ""
+	}
+
+	date, err := b.Details.FirstPublishedDate()
+	if err != nil {
+		date, err = b.Details.PublishedDate()
+	}
+
+	if err != nil {
+		return ""
+	}
+
+	return strconv.Itoa(date.Time.Year())
+}
+
+func formatRating(r float64) string {
+	return strconv.FormatFloat(r, 'f', 2, 64)
+}
+
+// asset resolves a site path from a page root ("../" deep pages), leaving
+// absolute URLs, such as remote covers, alone.
+func asset(root, p string) string {
+	if strings.Contains(p, "://") {
+		return p
+	}
+
+	return root + p
+}
+
+// slugs joins the paths of links for the index's data attributes, which
+// its filter script matches against.
+func slugs(links []Link) string {
+	paths := make([]string, len(links))
+	for i, l := range links {
+		paths[i] = l.Path
+	}
+
+	return strings.Join(paths, " ")
+}
+
+func downloadCovers(ctx context.Context, client *book.Client, dir string, entries []*Entry, errs *[]error) error {
+	if err := os.MkdirAll(filepath.Join(dir, coversDir), 0o755); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if e.CoverUrl == "" {
+			continue
+		}
+
+		name := path.Join(coversDir, strings.TrimSuffix(path.Base(e.Path), ".html")+coverExt(e.CoverUrl))
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+
+		if _, err := os.Stat(dest); err == nil {
+			e.Cover = name
+			continue
+		}
+
+		body, err := client.Get(ctx, e.CoverUrl)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: cover: %w", e.Title, err))
+			continue
+		}
+
+		if err := os.WriteFile(dest, body, 0o644); err != nil {
+			return err
+		}
+
+		e.Cover = name
+	}
+
+	return nil
+}
+
+func coverExt(u string) string {
+	if ext := strings.ToLower(path.Ext(u)); ext == ".png" || ext == ".gif" || ext == ".webp" {
+		return ext
+	}
+
+	return ".jpg"
+}
+
+// writer renders pages into dir, keeping the first error so Generate can
+// check once at the end.
+type writer struct {
+	dir  string
+	site string
+	err  error
+}
+
+func (w *writer) write(name, tmpl string, p page) {
+	if w.err != nil {
+		return
+	}
+
+	p.Site = w.site
+	p.Root = strings.Repeat("../", strings.Count(name, "/"))
+
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, tmpl, p); err != nil {
+		w.err = fmt.Errorf("rendering %s: %w", name, err)
+		return
+	}
+
+	w.file(name, buf.Bytes())
+}
+
+func (w *writer) file(name string, data []byte) {
+	if w.err != nil {
+		return
+	}
+
+	w.err = os.WriteFile(filepath.Join(w.dir, filepath.FromSlash(name)), data, 0o644)
+}
@@ -0,0 +1,163 @@
+package book
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+)
+
+const SitemapPath = "/sitemap.xml"
+
+// SitemapEntry is one <url> of a urlset, or one <sitemap> of a sitemap
+// index.
+type SitemapEntry struct {
+	Loc     string `json:"loc" xml:"loc"`
+	LastMod string `json:"lastmod" xml:"lastmod"`
+}
+
+// ReadSitemap streams the entries of a sitemap or sitemap index to fn,
+// without holding the whole document in memory. index reports whether
+// the entry points at another sitemap rather than a page. Gzipped
+// sitemaps (.xml.gz) are decompressed transparently.
+func ReadSitemap(r io.Reader, fn func(entry SitemapEntry, index bool) error) error {
+	br := bufio.NewReader(r)
+
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+
+		r = gz
+	} else {
+		r = br
+	}
+
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || (start.Name.Local != "url" && start.Name.Local != "sitemap") {
+			continue
+		}
+
+		entry := SitemapEntry{}
+		if err := decoder.DecodeElement(&entry, &start); err != nil {
+			return err
+		}
+
+		entry.Loc = strings.TrimSpace(entry.Loc)
+		entry.LastMod = strings.TrimSpace(entry.LastMod)
+
+		if entry.Loc == "" {
+			continue
+		}
+
+		if err := fn(entry, start.Name.Local == "sitemap"); err != nil {
+			return err
+		}
+	}
+}
+
+// WalkSitemap reads the sitemap at sitemapURL, following sitemap indexes
+// down to their urlsets, and hands every book URL it finds to fn. Each
+// nested sitemap is fetched once, and fetched only when the walk reaches
+// it, so fn can push into a Frontier and start a crawl long before a
+// full-catalog walk is done. Returning an error from fn stops the walk.
+func (c *Client) WalkSitemap(ctx context.Context, sitemapURL string, fn func(bookURL string) error) error {
+	start, err := c.resolve(sitemapURL)
+	if err != nil {
+		return err
+	}
+
+	return c.walkSitemap(ctx, start, map[string]bool{}, fn)
+}
+
+func (c *Client) walkSitemap(ctx context.Context, current string, seen map[string]bool, fn func(string) error) error {
+	seen[current] = true
+
+	body, err := c.Get(ctx, current)
+	if err != nil {
+		return err
+	}
+
+	base, err := url.Parse(current)
+	if err != nil {
+		return err
+	}
+
+	children := []string{}
+
+	err = ReadSitemap(bytes.NewReader(body), func(entry SitemapEntry, index bool) error {
+		ref, err := url.Parse(entry.Loc)
+		if err != nil {
+			return nil
+		}
+
+		loc := base.ResolveReference(ref).String()
+
+		if index {
+			if !seen[loc] {
+				seen[loc] = true
+				children = append(children, loc)
+			}
+
+			return nil
+		}
+
+		if !strings.Contains(ref.Path, BookURLIndicator) {
+			return nil
+		}
+
+		return fn(loc)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if err := c.walkSitemap(ctx, child, seen, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SitemapSeeds collects every book URL reachable from the sitemap at
+// sitemapURL. For a full catalog prefer WalkSitemap, which doesn't hold
+// the whole list.
+func (c *Client) SitemapSeeds(ctx context.Context, sitemapURL string) ([]string, error) {
+	seeds := []string{}
+
+	err := c.WalkSitemap(ctx, sitemapURL, func(bookURL string) error {
+		seeds = append(seeds, bookURL)
+		return nil
+	})
+
+	return seeds, err
+}
+
+func WalkSitemap(ctx context.Context, sitemapURL string, fn func(bookURL string) error) error {
+	return DefaultClient.WalkSitemap(ctx, sitemapURL, fn)
+}
+
+func SitemapSeeds(ctx context.Context, sitemapURL string) ([]string, error) {
+	return DefaultClient.SitemapSeeds(ctx, sitemapURL)
+}
@@ -0,0 +1,310 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	IndexSuffix = ".idx"
+
+	frameHeaderSize = 4
+)
+
+var ErrNotFound = errors.New("snapshot: not found")
+
+type Record struct {
+	URL  string
+	Time time.Time
+	Body []byte
+}
+
+type entry struct {
+	offset int64
+	length int64
+	time   time.Time
+}
+
+type Archive struct {
+	mu      sync.Mutex
+	data    *os.File
+	index   *os.File
+	size    int64
+	entries map[string][]entry
+	enc     *zstd.Encoder
+	dec     *zstd.Decoder
+}
+
+func Open(path string) (*Archive, error) {
+	data, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := os.OpenFile(path+IndexSuffix, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+
+	enc, _ := zstd.NewWriter(nil)
+	dec, _ := zstd.NewReader(nil)
+
+	a := &Archive{
+		data:    data,
+		index:   index,
+		entries: map[string][]entry{},
+		enc:     enc,
+		dec:     dec,
+	}
+
+	if err := a.load(); err != nil {
+		a.Close()
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (a *Archive) Append(url string, t time.Time, body []byte) error {
+	payload := bytes.Buffer{}
+	payload.WriteString(url)
+	payload.WriteByte('\n')
+	payload.WriteString(strconv.FormatInt(t.UnixNano(), 10))
+	payload.WriteByte('\n')
+	payload.Write(body)
+
+	frame := a.enc.EncodeAll(payload.Bytes(), make([]byte, frameHeaderSize, frameHeaderSize+payload.Len()/4))
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-frameHeaderSize))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	offset := a.size
+
+	if _, err := a.data.WriteAt(frame, offset); err != nil {
+		return err
+	}
+
+	a.size += int64(len(frame))
+
+	e := entry{offset: offset, length: int64(len(frame)), time: t}
+	if err := a.writeIndex(url, e); err != nil {
+		return err
+	}
+
+	a.add(url, e)
+
+	return nil
+}
+
+func (a *Archive) Record(url string, t time.Time, body []byte) error {
+	return a.Append(url, t, body)
+}
+
+func (a *Archive) Get(url string) (*Record, error) {
+	a.mu.Lock()
+	versions := a.entries[url]
+	a.mu.Unlock()
+
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return a.read(versions[len(versions)-1])
+}
+
+func (a *Archive) GetAt(url string, t time.Time) (*Record, error) {
+	a.mu.Lock()
+	versions := a.entries[url]
+	a.mu.Unlock()
+
+	i := sort.Search(len(versions), func(i int) bool { return versions[i].time.After(t) })
+	if i == 0 {
+		return nil, ErrNotFound
+	}
+
+	return a.read(versions[i-1])
+}
+
+func (a *Archive) Versions(url string) []time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	times := make([]time.Time, 0, len(a.entries[url]))
+	for _, e := range a.entries[url] {
+		times = append(times, e.time)
+	}
+
+	return times
+}
+
+func (a *Archive) URLs() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	urls := make([]string, 0, len(a.entries))
+	for url := range a.entries {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	return urls
+}
+
+func (a *Archive) Close() error {
+	a.enc.Close()
+	a.dec.Close()
+
+	return errors.Join(a.index.Close(), a.data.Close())
+}
+
+func (a *Archive) read(e entry) (*Record, error) {
+	frame := make([]byte, e.length)
+
+	if _, err := a.data.ReadAt(frame, e.offset); err != nil {
+		return nil, err
+	}
+
+	return a.decode(frame)
+}
+
+func (a *Archive) decode(frame []byte) (*Record, error) {
+	if len(frame) < frameHeaderSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	payload, err := a.dec.DecodeAll(frame[frameHeaderSize:], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	url, rest, ok := bytes.Cut(payload, []byte("\n"))
+	if !ok {
+		return nil, fmt.Errorf("snapshot: malformed record header")
+	}
+
+	stamp, body, ok := bytes.Cut(rest, []byte("\n"))
+	if !ok {
+		return nil, fmt.Errorf("snapshot: malformed record header")
+	}
+
+	nanos, err := strconv.ParseInt(string(stamp), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: malformed record time: %w", err)
+	}
+
+	return &Record{URL: string(url), Time: time.Unix(0, nanos), Body: body}, nil
+}
+
+func (a *Archive) load() error {
+	info, err := a.data.Stat()
+	if err != nil {
+		return err
+	}
+
+	a.size = info.Size()
+	indexed := int64(0)
+
+	scanner := bufio.NewScanner(a.index)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		offset, err1 := strconv.ParseInt(fields[0], 10, 64)
+		length, err2 := strconv.ParseInt(fields[1], 10, 64)
+		nanos, err3 := strconv.ParseInt(fields[2], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil || offset+length > a.size {
+			continue
+		}
+
+		a.add(fields[3], entry{offset: offset, length: length, time: time.Unix(0, nanos)})
+
+		if end := offset + length; end > indexed {
+			indexed = end
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return a.recover(indexed)
+}
+
+// recover indexes the frames written after the last indexed one, such as
+// those appended by a process that died before updating the index. A
+// frame that doesn't decode is skipped by its length header, so the valid
+// frames after it are kept; only a partly written frame at the end of the
+// file is cut off.
+func (a *Archive) recover(offset int64) error {
+	header := make([]byte, frameHeaderSize)
+
+	for offset+frameHeaderSize <= a.size {
+		if _, err := a.data.ReadAt(header, offset); err != nil {
+			return err
+		}
+
+		length := frameHeaderSize + int64(binary.BigEndian.Uint32(header))
+		if offset+length > a.size {
+			break
+		}
+
+		frame := make([]byte, length)
+		if _, err := a.data.ReadAt(frame, offset); err != nil {
+			return err
+		}
+
+		record, err := a.decode(frame)
+		if err != nil {
+			offset += length
+			continue
+		}
+
+		e := entry{offset: offset, length: length, time: record.Time}
+		if err := a.writeIndex(record.URL, e); err != nil {
+			return err
+		}
+
+		a.add(record.URL, e)
+		offset += length
+	}
+
+	a.size = offset
+
+	return a.data.Truncate(offset)
+}
+
+func (a *Archive) writeIndex(url string, e entry) error {
+	_, err := fmt.Fprintf(a.index, "%d\t%d\t%d\t%s\n", e.offset, e.length, e.time.UnixNano(), url)
+
+	return err
+}
+
+// add inserts e into url's versions in time order. Get and GetAt read
+// the slice after unlocking, so it is copied rather than changed in place.
+func (a *Archive) add(url string, e entry) {
+	old := a.entries[url]
+	i := sort.Search(len(old), func(i int) bool { return old[i].time.After(e.time) })
+
+	versions := make([]entry, 0, len(old)+1)
+	versions = append(versions, old[:i]...)
+	versions = append(versions, e)
+	versions = append(versions, old[i:]...)
+
+	a.entries[url] = versions
+}
@@ -0,0 +1,124 @@
+package snapshot
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestAddLeavesEarlierSlicesAlone checks add never changes a versions
+// slice Get or GetAt may still be reading after unlocking.
+func TestAddLeavesEarlierSlicesAlone(t *testing.T) {
+	const url = "https://www.goodreads.com/book/show/1"
+	base := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	a := &Archive{entries: map[string][]entry{}}
+	for i := 0; i < 8; i++ {
+		a.add(url, entry{offset: int64(i), time: base.Add(time.Duration(i) * time.Hour)})
+	}
+
+	// Leave spare capacity, as append would, so an in-place insert is
+	// possible.
+	held := append(make([]entry, 0, 16), a.entries[url]...)
+	a.entries[url] = held
+	before := append([]entry(nil), held...)
+
+	a.add(url, entry{offset: 99, time: base.Add(-time.Hour)})
+
+	if !reflect.DeepEqual(held, before) {
+		t.Errorf("add changed a slice a reader held")
+	}
+
+	versions := a.entries[url]
+	if len(versions) != 9 || versions[0].offset != 99 {
+		t.Fatalf("new version not inserted first: %+v", versions)
+	}
+
+	for i := 1; i < len(versions); i++ {
+		if versions[i].time.Before(versions[i-1].time) {
+			t.Errorf("versions out of order at %d", i)
+		}
+	}
+}
+
+// TestRecoverSkipsBadFrames checks that a corrupt frame in the unindexed
+// tail is skipped by its length header rather than truncating the valid
+// frames after it, and that a partly written last frame is cut off.
+func TestRecoverSkipsBadFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pages.zst")
+	base := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	a, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	urls := []string{"/book/show/1", "/book/show/2", "/book/show/3"}
+	for i, url := range urls {
+		if err := a.Append(url, base.Add(time.Duration(i)*time.Hour), []byte("<html>"+url+"</html>")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	second := a.entries[urls[1]][0]
+	end := a.size
+
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lose the index, as if the process died before writing it, corrupt
+	// the middle frame's payload and leave half a frame at the end.
+	if err := os.Truncate(path+IndexSuffix, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	garbage := bytes.Repeat([]byte{0xff}, int(second.length-frameHeaderSize))
+	if _, err := f.WriteAt(garbage, second.offset+frameHeaderSize); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.WriteAt([]byte{0, 0, 1, 0, 0x28, 0xb5}, end); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	if got, want := a.URLs(), []string{urls[0], urls[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("URLs() = %q, want %q", got, want)
+	}
+
+	record, err := a.Get(urls[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(record.Body) != "<html>"+urls[2]+"</html>" {
+		t.Errorf("Get(%q) body = %q", urls[2], record.Body)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Size() != end {
+		t.Errorf("archive is %d bytes, want the partial frame cut to %d", info.Size(), end)
+	}
+}
@@ -0,0 +1,39 @@
+package book_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dchooyc/book"
+	"github.com/dchooyc/book/testutil"
+)
+
+func TestStrictModeAcceptsZeroRating(t *testing.T) {
+	unrated := book.Book{Title: "Unrated", URL: "https://www.goodreads.com/book/show/1", ID: "1"}
+
+	b, err := book.GetBook(bytes.NewReader(testutil.RenderFixture(unrated)), book.WithParseMode(book.ParseStrict))
+	if err != nil {
+		t.Fatalf("GetBook: %v", err)
+	}
+
+	if b.Rating != 0 {
+		t.Errorf("Rating = %v, want 0", b.Rating)
+	}
+}
+
+func TestStrictModeRejectsMissingRating(t *testing.T) {
+	page := `<html><head><link rel="canonical" href="https://www.goodreads.com/book/show/1"></head><body>` +
+		`<h1 class="Text Text__title1" data-testid="bookTitle" aria-label="` + book.BookTitlePrefix + `Unrated">Unrated</h1>` +
+		`</body></html>`
+
+	_, err := book.GetBook(strings.NewReader(page), book.WithParseMode(book.ParseStrict))
+	if !errors.Is(err, book.ErrMissingField) {
+		t.Fatalf("err = %v, want ErrMissingField", err)
+	}
+
+	if fes := book.FieldErrors(err); len(fes) != 1 || fes[0].Field != book.FieldRating {
+		t.Errorf("field errors = %v, want only %s", fes, book.FieldRating)
+	}
+}
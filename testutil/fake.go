@@ -0,0 +1,164 @@
+package testutil
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dchooyc/book"
+)
+
+const (
+	BookPathPrefix = "/book/show/"
+	ListPathPrefix = "/list/show/"
+	SearchPath     = "/search"
+)
+
+type FakeGoodreads struct {
+	*httptest.Server
+
+	books map[string]book.Book
+	order []string
+}
+
+func NewFakeGoodreads(books ...book.Book) *FakeGoodreads {
+	f := &FakeGoodreads{
+		books: map[string]book.Book{},
+	}
+
+	for _, b := range books {
+		path := BookPath(b)
+		if _, ok := f.books[path]; !ok {
+			f.order = append(f.order, path)
+		}
+
+		f.books[path] = b
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(BookPathPrefix, f.serveBook)
+	mux.HandleFunc(ListPathPrefix, f.serveList)
+	mux.HandleFunc(SearchPath, f.serveSearch)
+
+	f.Server = httptest.NewServer(mux)
+
+	return f
+}
+
+func (f *FakeGoodreads) BookURL(b book.Book) string {
+	return f.URL + BookPath(b)
+}
+
+func (f *FakeGoodreads) ListURL() string {
+	return f.URL + ListPathPrefix + "1.Fake_List"
+}
+
+func (f *FakeGoodreads) SearchURL(query string) string {
+	return f.URL + SearchPath + "?q=" + url.QueryEscape(query)
+}
+
+func BookPath(b book.Book) string {
+	if b.URL != "" {
+		if u, err := url.Parse(b.URL); err == nil && strings.HasPrefix(u.Path, BookPathPrefix) {
+			return u.Path
+		}
+	}
+
+	return BookPathPrefix + b.ID
+}
+
+func (f *FakeGoodreads) serveBook(w http.ResponseWriter, r *http.Request) {
+	b, ok := f.books[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeHTML(w, RenderFixture(b))
+}
+
+func (f *FakeGoodreads) serveList(w http.ResponseWriter, r *http.Request) {
+	books := make([]book.Book, 0, len(f.order))
+	for _, path := range f.order {
+		books = append(books, f.books[path])
+	}
+
+	writeHTML(w, renderListPage("Fake List", books))
+}
+
+func (f *FakeGoodreads) serveSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("q"))
+	books := []book.Book{}
+
+	for _, path := range f.order {
+		b := f.books[path]
+
+		if query == "" || matchesQuery(b, query) {
+			books = append(books, b)
+		}
+	}
+
+	writeHTML(w, renderListPage("Search results", books))
+}
+
+func matchesQuery(b book.Book, query string) bool {
+	if strings.Contains(strings.ToLower(b.Title), query) || b.ID == query {
+		return true
+	}
+
+	for _, author := range b.AuthorNames() {
+		if strings.Contains(strings.ToLower(author), query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeHTML(w http.ResponseWriter, page []byte) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+func renderListPage(title string, books []book.Book) []byte {
+	var sb strings.Builder
+	esc := html.EscapeString
+
+	fmt.Fprintf(&sb, "<!DOCTYPE html><html><head><title>%s</title></head><body>", esc(title))
+	fmt.Fprintf(&sb, `<h1 class="gr-h1 gr-h1--serif">%s</h1><table class="tableList">`, esc(title))
+
+	for i, b := range books {
+		fmt.Fprintf(&sb, `<tr itemscope itemtype="http://schema.org/Book"><td class="number">%d</td><td>`, i+1)
+		fmt.Fprintf(&sb, `<a class="bookTitle" itemprop="url" href="%s"><span itemprop="name" role="heading" aria-level="4">%s</span></a>`,
+			esc(BookPath(b)), esc(b.Title))
+
+		for _, author := range b.Authors {
+			fmt.Fprintf(&sb, `<span itemprop="author"><a class="authorName" href="%s"><span itemprop="name">%s</span></a></span>`,
+				esc(author.URL()), esc(author.Name()))
+		}
+
+		fmt.Fprintf(&sb, `<span class="minirating">%.2f avg rating &mdash; %s ratings</span>`, b.Rating, formatCount(b.Ratings))
+		sb.WriteString("</td></tr>")
+	}
+
+	sb.WriteString("</table></body></html>")
+
+	return []byte(sb.String())
+}
+
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return s
+	}
+
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+
+	return s
+}
@@ -0,0 +1,63 @@
+package testutil
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/dchooyc/book"
+)
+
+// RenderFixture generates a minimal book page using the same markup the
+// parser expects from goodreads.com, such that GetBook(RenderFixture(b))
+// yields b again.
+func RenderFixture(b book.Book) []byte {
+	var sb strings.Builder
+	esc := html.EscapeString
+
+	sb.WriteString("<!DOCTYPE html><html><head><title>")
+	sb.WriteString(esc(b.Title))
+	sb.WriteString("</title>")
+
+	if b.URL != "" {
+		fmt.Fprintf(&sb, `<link rel="canonical" href="%s">`, esc(b.URL))
+	}
+
+	sb.WriteString("</head><body>")
+
+	sb.WriteString(`<div class="BookCover__image"><div>`)
+	fmt.Fprintf(&sb, `<img class="ResponsiveImage" role="presentation" src="%s">`, esc(b.CoverUrl))
+	sb.WriteString("</div></div>")
+
+	fmt.Fprintf(&sb, `<h1 class="Text Text__title1" data-testid="bookTitle" aria-label="%s%s">%s</h1>`,
+		book.BookTitlePrefix, esc(b.Title), esc(b.Title))
+
+	if len(b.Authors) > 0 {
+		sb.WriteString(`<div class="ContributorLinksList">`)
+		for _, author := range b.Authors {
+			fmt.Fprintf(&sb, `<span tabindex="-1"><a class="ContributorLink" href="%s"><span class="ContributorLink__name" data-testid="name">%s</span></a></span>`,
+				esc(author.URL()), esc(author.Name()))
+		}
+		sb.WriteString("</div>")
+	}
+
+	sb.WriteString(`<div class="BookPageMetadataSection__genres"><ul>`)
+	for _, genre := range b.Genres {
+		fmt.Fprintf(&sb, `<span class="BookPageMetadataSection__genreButton"><a class="Button Button--tag-inline" href="https://www.goodreads.com/genres/%s"><span class="Button__labelItem">%s</span></a></span>`,
+			esc(genre.Slug()), esc(genre.Name()))
+	}
+	sb.WriteString("</ul></div>")
+
+	fmt.Fprintf(&sb, `<div class="RatingStatistics__rating">%s</div>`, strconv.FormatFloat(b.Rating, 'f', -1, 64))
+	fmt.Fprintf(&sb, `<div class="RatingStatistics__meta" aria-label="%s ratings and %s reviews"></div>`,
+		formatCount(b.Ratings), formatCount(b.Reviews))
+
+	if b.ID != "" {
+		fmt.Fprintf(&sb, `<a href="https://www.goodreads.com/work/quotes/%s">Quotes</a>`, esc(b.ID))
+	}
+
+	sb.WriteString("</body></html>")
+
+	return []byte(sb.String())
+}
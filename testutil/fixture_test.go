@@ -0,0 +1,132 @@
+package testutil
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/dchooyc/book"
+)
+
+func TestRenderFixtureRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		book book.Book
+	}{
+		{
+			name: "authors without IDs",
+			book: book.Book{
+				Title:    "Dune",
+				URL:      "https://www.goodreads.com/book/show/44767458-dune",
+				ID:       "3634639",
+				CoverUrl: "https://images.gr-assets.com/books/1555447414l/44767458.jpg",
+				Authors:  book.AuthorRefs("Frank Herbert"),
+				Genres:   []book.Genre{"science-fiction", "classics"},
+				Rating:   4.27,
+				Ratings:  1431283,
+				Reviews:  53209,
+			},
+		},
+		{
+			name: "authors with IDs",
+			book: book.Book{
+				Title:    "Good Omens",
+				URL:      "https://www.goodreads.com/book/show/12067.Good_Omens",
+				ID:       "4396",
+				CoverUrl: "https://images.gr-assets.com/books/1615552073l/12067.jpg",
+				Authors: []book.AuthorRef{
+					book.NewAuthorRef("Terry Pratchett", "1654"),
+					book.NewAuthorRef("Neil Gaiman", "1221698"),
+				},
+				Genres:  []book.Genre{"fantasy"},
+				Rating:  4.25,
+				Ratings: 750000,
+				Reviews: 30000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := book.GetBook(bytes.NewReader(RenderFixture(tt.book)))
+			if err != nil {
+				t.Fatalf("GetBook: %v", err)
+			}
+
+			if !reflect.DeepEqual(*got, tt.book) {
+				t.Errorf("round trip mismatch\n got: %+v\nwant: %+v", *got, tt.book)
+			}
+		})
+	}
+}
+
+// pageBook is a RandomBook reduced to what a book page shows: a page has
+// no details or ISBN outside the page data, collapses whitespace, shows
+// the rating to two places and links genres by lowercase slug.
+type pageBook book.Book
+
+func (pageBook) Generate(r *rand.Rand, size int) reflect.Value {
+	b := RandomBook(r)
+	b.ISBN, b.Details = "", nil
+	b.Title = pageText(r, 40)
+	b.Rating = float64(r.Intn(501)) / 100
+
+	for i, author := range b.Authors {
+		b.Authors[i] = book.NewAuthorRef(pageText(r, 20), author.ID())
+	}
+
+	for i := range b.Genres {
+		b.Genres[i] = book.Genre(randomSlug(r))
+	}
+
+	return reflect.ValueOf(pageBook(b))
+}
+
+// pageText is RandomText as a page renders it: whitespace collapsed and
+// never empty.
+func pageText(r *rand.Rand, n int) string {
+	for {
+		if s := strings.Join(strings.Fields(RandomText(r, n)), " "); s != "" {
+			return s
+		}
+	}
+}
+
+func randomSlug(r *rand.Rand) string {
+	words := make([]string, 1+r.Intn(3))
+	for i := range words {
+		w := make([]byte, 1+r.Intn(8))
+		for j := range w {
+			w[j] = byte('a' + r.Intn(26))
+		}
+		words[i] = string(w)
+	}
+
+	return strings.Join(words, "-")
+}
+
+func TestRenderFixtureProperty(t *testing.T) {
+	property := func(pb pageBook) bool {
+		want := book.Book(pb)
+
+		got, err := book.GetBook(bytes.NewReader(RenderFixture(want)))
+		if err != nil {
+			t.Logf("GetBook: %v", err)
+			return false
+		}
+
+		if !reflect.DeepEqual(*got, want) {
+			t.Logf("got %+v\nwant %+v", *got, want)
+			return false
+		}
+
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,149 @@
+package testutil
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dchooyc/book"
+)
+
+// scripts are the rune ranges RandomText draws from: ASCII including the
+// quotes, commas and separators encoders have to escape, accented Latin,
+// Cyrillic, CJK, and emoji outside the Basic Multilingual Plane.
+var scripts = [][2]rune{
+	{0x20, 0x7e},
+	{0xc0, 0x17f},
+	{0x400, 0x4ff},
+	{0x4e00, 0x9fff},
+	{0x1f300, 0x1f5ff},
+}
+
+// RandomText returns up to n runes of mixed-script text, sometimes with a
+// tab or newline in it.
+func RandomText(r *rand.Rand, n int) string {
+	var sb strings.Builder
+
+	for i := r.Intn(n + 1); i > 0; i-- {
+		switch r.Intn(20) {
+		case 0:
+			sb.WriteByte('\n')
+		case 1:
+			sb.WriteByte('\t')
+		default:
+			s := scripts[r.Intn(len(scripts))]
+			sb.WriteRune(s[0] + rune(r.Int63n(int64(s[1]-s[0]+1))))
+		}
+	}
+
+	return sb.String()
+}
+
+// RandomBook returns a book with every field filled in from r, for
+// property tests of the encoders. Counts range up to the largest int, and
+// the rating is any finite float. Author names and genres are never
+// empty, and most authors have an ID, as on a page. The details have
+// no reviews, which only the JSON encoders carry.
+func RandomBook(r *rand.Rand) book.Book {
+	id := strconv.FormatInt(r.Int63(), 10)
+
+	b := book.Book{
+		Title:    RandomText(r, 40),
+		URL:      book.GoodreadsBaseURL + book.BookURLIndicator + id,
+		ID:       id,
+		CoverUrl: "https://images.gr-assets.com/books/" + id + ".jpg?w=1&h=" + strconv.Itoa(r.Intn(1000)),
+		Rating:   randomFloat(r),
+		Ratings:  int(r.Int63n(math.MaxInt)),
+		Reviews:  int(r.Int63n(math.MaxInt)),
+	}
+
+	for i := r.Intn(4); i > 0; i-- {
+		var authorID string
+		if r.Intn(4) != 0 {
+			authorID = strconv.FormatInt(r.Int63(), 10)
+		}
+
+		b.Authors = append(b.Authors, book.NewAuthorRef(nonEmptyText(r, 20), authorID))
+	}
+
+	for i := r.Intn(4); i > 0; i-- {
+		b.Genres = append(b.Genres, book.Genre(nonEmptyText(r, 12)))
+	}
+
+	switch r.Intn(3) {
+	case 0:
+		b.ISBN = randomISBN10(r)
+	case 1:
+		b.ISBN = randomISBN13(r)
+	}
+
+	if r.Intn(4) == 0 {
+		return b
+	}
+
+	b.Details = &book.BookDetails{
+		TitleComplete:  RandomText(r, 60),
+		OriginalTitle:  RandomText(r, 40),
+		Description:    RandomText(r, 400),
+		Pages:          r.Intn(math.MaxInt32),
+		Format:         RandomText(r, 10),
+		Publisher:      RandomText(r, 20),
+		Published:      randomDate(r),
+		FirstPublished: randomDate(r),
+		Language:       RandomText(r, 10),
+		ISBN10:         randomISBN10(r),
+		ISBN13:         randomISBN13(r),
+		ASIN:           RandomText(r, 10),
+	}
+
+	for i := r.Intn(6); i > 0; i-- {
+		b.Details.RatingsHistogram = append(b.Details.RatingsHistogram, int(r.Int63n(math.MaxInt)))
+	}
+
+	return b
+}
+
+// nonEmptyText is RandomText with at least one rune.
+func nonEmptyText(r *rand.Rand, n int) string {
+	for {
+		if s := RandomText(r, n); s != "" {
+			return s
+		}
+	}
+}
+
+// randomISBN10 and randomISBN13 return digit strings of the right length;
+// the check digit isn't computed.
+func randomISBN10(r *rand.Rand) string {
+	return strconv.FormatInt(1e9+r.Int63n(9e9), 10)
+}
+
+func randomISBN13(r *rand.Rand) string {
+	return strconv.FormatInt(978e10+r.Int63n(2e10), 10)
+}
+
+// randomFloat mixes ordinary ratings with extreme magnitudes.
+func randomFloat(r *rand.Rand) float64 {
+	if r.Intn(2) == 0 {
+		return float64(r.Intn(501)) / 100
+	}
+
+	return (r.Float64()*2 - 1) * math.Pow(10, float64(r.Intn(600)-300))
+}
+
+func randomDate(r *rand.Rand) string {
+	t := time.Date(1500+r.Intn(600), time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, r.Intn(365))
+
+	switch r.Intn(4) {
+	case 0:
+		return ""
+	case 1:
+		return t.Format("2006")
+	case 2:
+		return t.Format("January 2006")
+	default:
+		return t.Format("January 2, 2006")
+	}
+}
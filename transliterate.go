@@ -0,0 +1,49 @@
+package book
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// asciiFallbacks spells out the letters and punctuation that don't
+// decompose into an ASCII base plus accents.
+var asciiFallbacks = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L",
+	'đ': "d", 'Đ': "D",
+	'ð': "d", 'Ð': "D",
+	'þ': "th", 'Þ': "Th",
+	'ı': "i",
+	'‘': "'", '’': "'", '‚': "'", '′': "'",
+	'“': `"`, '”': `"`, '„': `"`, '″': `"`,
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-",
+	'…': "...",
+	' ': " ",
+}
+
+// Transliterate approximates s in ASCII for matching titles and author
+// names across sources, e.g. "Gabriel García Márquez" becomes "Gabriel
+// Garcia Marquez" and "Straße" becomes "Strasse". Accents are stripped,
+// common ligatures and quotes are spelled out and anything else outside
+// ASCII, such as CJK text, is dropped. Keep the original for display.
+func Transliterate(s string) string {
+	var sb strings.Builder
+
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case r < unicode.MaxASCII:
+			sb.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining accent split off by NFD.
+		default:
+			sb.WriteString(asciiFallbacks[r])
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package book
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	UserURLIndicator       = "/user/show/"
+	UserNameIndicator      = "userProfileName"
+	UserInfoTitleIndicator = "infoBoxRowTitle"
+	UserStatsIndicator     = "profilePageUserStatsInfo"
+	UserShelfItemIndicator = "userShowPageShelfListItem"
+)
+
+var (
+	userAvgPattern     = regexp.MustCompile(`\(([\d.,]+)\s+avg\)`)
+	userReviewsPattern = regexp.MustCompile(`(?i)([\d,.]+)\s+reviews?\b`)
+	shelfCountPattern  = regexp.MustCompile(`^(.*?)\s*\(([\d,.]+)\)$`)
+)
+
+type User struct {
+	Name           string      `json:"name"`
+	URL            string      `json:"url"`
+	ID             string      `json:"id"`
+	Location       string      `json:"location"`
+	Ratings        int         `json:"ratings"`
+	AvgRating      float64     `json:"avg_rating"`
+	Reviews        int         `json:"reviews"`
+	Shelves        []UserShelf `json:"shelves"`
+	FavoriteGenres []string    `json:"favorite_genres"`
+}
+
+type UserShelf struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func GetUser(r io.Reader) (*User, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		Shelves:        []UserShelf{},
+		FavoriteGenres: []string{},
+	}
+
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		user.URL = getAttr(link, "href")
+		user.ID = leadingDigits(lastPathSegment(user.URL))
+	}
+
+	if h1 := findFirst(doc, byClass("h1", UserNameIndicator)); h1 != nil {
+		user.Name = textContent(h1)
+	}
+
+	for _, title := range findAll(doc, byClass("div", UserInfoTitleIndicator)) {
+		item := nextElementSibling(title)
+		if item == nil {
+			continue
+		}
+
+		switch textContent(title) {
+		case "Location", "Details":
+			if user.Location == "" {
+				user.Location = textContent(item)
+			}
+		case "Favorite Genres":
+			for _, genre := range strings.Split(textContent(item), ",") {
+				if genre = strings.TrimSpace(genre); genre != "" {
+					user.FavoriteGenres = append(user.FavoriteGenres, genre)
+				}
+			}
+		}
+	}
+
+	if stats := findFirst(doc, byClass("div", UserStatsIndicator)); stats != nil {
+		text := textContent(stats)
+		user.Ratings = matchCount(ratingsPattern, text)
+		user.AvgRating = matchFloat(userAvgPattern, text)
+		user.Reviews = matchCount(userReviewsPattern, text)
+	}
+
+	for _, a := range findAll(doc, byClass("a", UserShelfItemIndicator)) {
+		text := strings.Trim(textContent(a), "\u200e ")
+		shelf := UserShelf{Name: text}
+
+		if m := shelfCountPattern.FindStringSubmatch(text); m != nil {
+			shelf.Name = strings.TrimRight(m[1], "\u200e ")
+			shelf.Count = parseCount(m[2])
+		}
+
+		user.Shelves = append(user.Shelves, shelf)
+	}
+
+	return user, nil
+}
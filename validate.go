@@ -0,0 +1,97 @@
+package book
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// MaxRating is the top of Goodreads' star scale.
+const MaxRating = 5
+
+type IssueCode string
+
+const (
+	IssueEmptyTitle     IssueCode = "empty_title"
+	IssueRatingRange    IssueCode = "rating_out_of_range"
+	IssueReviewsExceed  IssueCode = "reviews_exceed_ratings"
+	IssueNegativeCount  IssueCode = "negative_count"
+	IssueMissingURL     IssueCode = "missing_url"
+	IssueMalformedURL   IssueCode = "malformed_url"
+	IssueMissingCover   IssueCode = "missing_cover"
+	IssueMalformedCover IssueCode = "malformed_cover"
+)
+
+// ValidationIssue is one thing wrong with a Book, against the Field
+// constants.
+type ValidationIssue struct {
+	Field   string    `json:"field"`
+	Code    IssueCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Field, i.Message)
+}
+
+// Validate checks a Book for values no real book page produces, so an
+// ingestion pipeline can quarantine the record instead of storing it.
+// An empty result means the Book passed.
+func (b *Book) Validate() []ValidationIssue {
+	issues := []ValidationIssue{}
+
+	add := func(field string, code IssueCode, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(b.Title) == "" {
+		add(FieldTitle, IssueEmptyTitle, "title is empty")
+	}
+
+	if math.IsNaN(b.Rating) || b.Rating < 0 || b.Rating > MaxRating {
+		add(FieldRating, IssueRatingRange, "rating %v is outside 0-%d", b.Rating, MaxRating)
+	}
+
+	if b.Ratings < 0 {
+		add(FieldRatings, IssueNegativeCount, "ratings count %d is negative", b.Ratings)
+	}
+
+	if b.Reviews < 0 {
+		add(FieldReviews, IssueNegativeCount, "reviews count %d is negative", b.Reviews)
+	}
+
+	// Every review carries a rating, so there can't be more reviews.
+	if b.Reviews > b.Ratings {
+		add(FieldReviews, IssueReviewsExceed, "%d reviews exceed %d ratings", b.Reviews, b.Ratings)
+	}
+
+	switch {
+	case b.URL == "":
+		add(FieldURL, IssueMissingURL, "url is empty")
+	case !isBookURL(b.URL):
+		add(FieldURL, IssueMalformedURL, "%q is not an absolute Goodreads book URL", b.URL)
+	}
+
+	switch {
+	case b.CoverUrl == "":
+		add(FieldCover, IssueMissingCover, "cover url is empty")
+	case !isAbsoluteURL(b.CoverUrl):
+		add(FieldCover, IssueMalformedCover, "%q is not an absolute URL", b.CoverUrl)
+	}
+
+	return issues
+}
+
+func isAbsoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isBookURL(raw string) bool {
+	return isAbsoluteURL(raw) && strings.Contains(raw, BookURLIndicator)
+}
@@ -0,0 +1,77 @@
+package book_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dchooyc/book"
+)
+
+func TestValidate(t *testing.T) {
+	valid := book.Book{
+		Title:    "Dune",
+		URL:      "https://www.goodreads.com/book/show/44767458-dune",
+		CoverUrl: "https://images.gr-assets.com/books/1555447414l/44767458.jpg",
+		Rating:   4.27,
+		Ratings:  1431283,
+		Reviews:  53209,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*book.Book)
+		want   []book.IssueCode
+	}{
+		{"valid", func(b *book.Book) {}, []book.IssueCode{}},
+		{"empty title", func(b *book.Book) { b.Title = " \n" }, []book.IssueCode{book.IssueEmptyTitle}},
+		{"rating above 5", func(b *book.Book) { b.Rating = 5.01 }, []book.IssueCode{book.IssueRatingRange}},
+		{"negative rating", func(b *book.Book) { b.Rating = -1 }, []book.IssueCode{book.IssueRatingRange}},
+		{"NaN rating", func(b *book.Book) { b.Rating = math.NaN() }, []book.IssueCode{book.IssueRatingRange}},
+		{"rating of exactly 5", func(b *book.Book) { b.Rating = 5 }, []book.IssueCode{}},
+		{"more reviews than ratings", func(b *book.Book) { b.Reviews = b.Ratings + 1 }, []book.IssueCode{book.IssueReviewsExceed}},
+		{"negative ratings", func(b *book.Book) { b.Ratings, b.Reviews = -1, 0 }, []book.IssueCode{book.IssueNegativeCount, book.IssueReviewsExceed}},
+		{"negative reviews", func(b *book.Book) { b.Reviews = -1 }, []book.IssueCode{book.IssueNegativeCount}},
+		{"missing URL", func(b *book.Book) { b.URL = "" }, []book.IssueCode{book.IssueMissingURL}},
+		{"relative URL", func(b *book.Book) { b.URL = "/book/show/1" }, []book.IssueCode{book.IssueMalformedURL}},
+		{"not a book URL", func(b *book.Book) { b.URL = "https://www.goodreads.com/author/show/58" }, []book.IssueCode{book.IssueMalformedURL}},
+		{"missing cover", func(b *book.Book) { b.CoverUrl = "" }, []book.IssueCode{book.IssueMissingCover}},
+		{"relative cover", func(b *book.Book) { b.CoverUrl = "covers/1.jpg" }, []book.IssueCode{book.IssueMalformedCover}},
+		{"everything wrong", func(b *book.Book) { *b = book.Book{Rating: 6, Reviews: 1} }, []book.IssueCode{
+			book.IssueEmptyTitle, book.IssueRatingRange, book.IssueReviewsExceed, book.IssueMissingURL, book.IssueMissingCover,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := valid
+			tt.modify(&b)
+
+			got := []book.IssueCode{}
+			for _, issue := range b.Validate() {
+				got = append(got, issue.Code)
+
+				if issue.Field == "" || issue.Message == "" {
+					t.Errorf("issue %+v has no field or message", issue)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate codes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationIssueFields(t *testing.T) {
+	b := book.Book{Title: "Dune", URL: "https://www.goodreads.com/book/show/1", CoverUrl: "https://images.gr-assets.com/1.jpg", Ratings: 10, Reviews: 11}
+
+	issues := b.Validate()
+	if len(issues) != 1 {
+		t.Fatalf("Validate = %+v, want one issue", issues)
+	}
+
+	if issues[0].Field != book.FieldReviews || issues[0].String() != "reviews: 11 reviews exceed 10 ratings" {
+		t.Errorf("issue = %+v (%s)", issues[0], issues[0])
+	}
+}
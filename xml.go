@@ -0,0 +1,48 @@
+package book
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// EncodeXML writes books as an XML document rooted at <books>, with one
+// <book> element each and the same field names as the JSON.
+func EncodeXML(w io.Writer, books []Book) error {
+	return encodeXML(w, Books{Books: books}, "books")
+}
+
+// EncodeBookXML writes a single Book as an XML document rooted at <book>.
+func EncodeBookXML(w io.Writer, b *Book) error {
+	return encodeXML(w, b, "book")
+}
+
+// DecodeXML reads a document written by EncodeXML.
+func DecodeXML(r io.Reader) ([]Book, error) {
+	books := Books{}
+	if err := xml.NewDecoder(r).Decode(&books); err != nil {
+		return nil, err
+	}
+
+	return books.Books, nil
+}
+
+func encodeXML(w io.Writer, v interface{}, root string) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: root}}); err != nil {
+		return err
+	}
+
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
@@ -0,0 +1,136 @@
+package book
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---\n"
+
+var ErrNoFrontMatter = errors.New("no YAML front matter")
+
+// EncodeYAML writes a single Book as a YAML document with the same field
+// names as its JSON.
+func EncodeYAML(w io.Writer, b *Book) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func DecodeYAML(r io.Reader) (*Book, error) {
+	b := &Book{}
+	if err := yaml.NewDecoder(r).Decode(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// EncodeBooksYAML writes books as one YAML document under a "books" key,
+// mirroring the JSON Books layout.
+func EncodeBooksYAML(w io.Writer, books []Book) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(Books{Books: books}); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func DecodeBooksYAML(r io.Reader) ([]Book, error) {
+	books := Books{}
+	if err := yaml.NewDecoder(r).Decode(&books); err != nil {
+		return nil, err
+	}
+
+	return books.Books, nil
+}
+
+// WriteFrontMatter writes b as YAML front matter between "---" lines, as
+// static site generators such as Hugo and Jekyll read it, followed by
+// body.
+func WriteFrontMatter(w io.Writer, b *Book, body string) error {
+	if _, err := io.WriteString(w, frontMatterDelimiter); err != nil {
+		return err
+	}
+
+	if err := EncodeYAML(w, b); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, frontMatterDelimiter); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, body)
+
+	return err
+}
+
+// ReadFrontMatter splits a document written by WriteFrontMatter back into
+// its Book and the body after the front matter.
+func ReadFrontMatter(r io.Reader) (*Book, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	rest, ok := bytes.CutPrefix(data, []byte(frontMatterDelimiter))
+	if !ok {
+		return nil, "", ErrNoFrontMatter
+	}
+
+	var front, body []byte
+	if bytes.HasPrefix(rest, []byte(frontMatterDelimiter)) {
+		body = rest[len(frontMatterDelimiter):]
+	} else if front, body, ok = bytes.Cut(rest, []byte("\n"+frontMatterDelimiter)); ok {
+		front = append(front, '\n')
+	} else {
+		return nil, "", fmt.Errorf("%w: closing %q not found", ErrNoFrontMatter, "---")
+	}
+
+	b := &Book{}
+	if err := yaml.Unmarshal(front, b); err != nil {
+		return nil, "", err
+	}
+
+	return b, string(body), nil
+}
+
+func (a AuthorRef) MarshalYAML() (interface{}, error) {
+	if a.id == "" {
+		return a.name, nil
+	}
+
+	return authorRefJSON{Name: a.name, ID: a.id}, nil
+}
+
+// UnmarshalYAML accepts both the plain name and a name/id mapping.
+func (a *AuthorRef) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*a = AuthorRef{name: value.Value}
+		return nil
+	}
+
+	var ref authorRefJSON
+	if err := value.Decode(&ref); err != nil {
+		return err
+	}
+
+	*a = AuthorRef{name: ref.Name, id: ref.ID}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package book
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	YearInBooksURLIndicator       = "/user/year_in_books/"
+	YearInBooksStatsIndicator     = "yyibStats"
+	YearInBooksHighlightIndicator = "yyibHighlight"
+	YearInBooksShelfIndicator     = "yyibBookShelf"
+)
+
+var (
+	yearInBooksYearPattern   = regexp.MustCompile(`/year_in_books/(\d{4})`)
+	yearInBooksBooksPattern  = regexp.MustCompile(`(?i)([\d,]+)\s+books?\b`)
+	yearInBooksPagesPattern  = regexp.MustCompile(`(?i)([\d,]+)\s+pages?\b`)
+	yearInBooksRatingPattern = regexp.MustCompile(`(?i)average rating\D*?([\d.]+)`)
+)
+
+type YearInBooks struct {
+	Year      int         `json:"year"`
+	UserID    string      `json:"user_id"`
+	Books     int         `json:"books"`
+	Pages     int         `json:"pages"`
+	AvgRating float64     `json:"avg_rating"`
+	Shortest  *YearInBook `json:"shortest,omitempty"`
+	Longest   *YearInBook `json:"longest,omitempty"`
+	Read      []BookRef   `json:"read"`
+}
+
+type YearInBook struct {
+	BookRef
+	Pages int `json:"pages"`
+}
+
+func GetYearInBooks(r io.Reader) (*YearInBooks, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	yib := &YearInBooks{
+		Read: []BookRef{},
+	}
+
+	if link := findFirst(doc, byAttr("link", "rel", "canonical")); link != nil {
+		href := getAttr(link, "href")
+		yib.UserID = leadingDigits(lastPathSegment(href))
+
+		if m := yearInBooksYearPattern.FindStringSubmatch(href); m != nil {
+			yib.Year, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	if stats := findFirst(doc, byClass("", YearInBooksStatsIndicator)); stats != nil {
+		text := textContent(stats)
+		yib.Books = matchCount(yearInBooksBooksPattern, text)
+		yib.Pages = matchCount(yearInBooksPagesPattern, text)
+		yib.AvgRating = matchFloat(yearInBooksRatingPattern, text)
+	}
+
+	for _, highlight := range findAll(doc, byClass("", YearInBooksHighlightIndicator)) {
+		text := strings.ToLower(textContent(highlight))
+
+		refs := extractSectionRefs(highlight)
+		if len(refs) == 0 {
+			continue
+		}
+
+		book := &YearInBook{
+			BookRef: refs[0],
+			Pages:   matchCount(yearInBooksPagesPattern, text),
+		}
+
+		switch {
+		case strings.Contains(text, "shortest"):
+			yib.Shortest = book
+		case strings.Contains(text, "longest"):
+			yib.Longest = book
+		}
+	}
+
+	if shelf := findFirst(doc, byClass("", YearInBooksShelfIndicator)); shelf != nil {
+		yib.Read = extractSectionRefs(shelf)
+	}
+
+	if yib.Books == 0 {
+		yib.Books = len(yib.Read)
+	}
+
+	return yib, nil
+}